@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  sFlow Agent Registry
+// ****************************************************************************************************
+
+type XnfvAgentRegistryConfig struct {
+	// MaxAge removes agents that have not sent a datagram for that long,
+	// and the VMs whose counters have not been reported for that long
+	MaxAge time.Duration
+	// DatapathLostPps is the OVS datapath loss rate reported as an event
+	DatapathLostPps float64
+	// DatapathMissPps and DatapathMissRatio: a cache thrashing event is
+	// reported when both the upcall rate and the share of packets missing
+	// the datapath flow cache reach them
+	DatapathMissPps   float64
+	DatapathMissRatio float64
+}
+
+func DefaultXnfvAgentRegistryConfig() XnfvAgentRegistryConfig {
+	return XnfvAgentRegistryConfig{
+		MaxAge:            10 * time.Minute,
+		DatapathLostPps:   1,
+		DatapathMissPps:   1000,
+		DatapathMissRatio: 0.2,
+	}
+}
+
+// XnfvHostAdapter is a network adapter of a host, with the ifIndex its
+// interface counters are reported with
+type XnfvHostAdapter struct {
+	IfIndex      uint32   `json:"ifIndex"`
+	MacAddresses []string `json:"macAddresses"`
+}
+
+// XnfvAgentHost holds the latest host counters (hsflowd) of an agent
+type XnfvAgentHost struct {
+	// SourceClass and SourceIndex are the data source the host counters
+	// are reported with, the parent record of a VM names it
+	SourceClass uint32                         `json:"sourceClass"`
+	SourceIndex uint32                         `json:"sourceIndex"`
+	Hostname    string                         `json:"hostname"`
+	UUID        string                         `json:"uuid"`
+	MachineType sflow.SFlowMachineType         `json:"machineType"`
+	OSName      sflow.SFlowOSName              `json:"osName"`
+	OSRelease   string                         `json:"osRelease"`
+	Adapters    []XnfvHostAdapter              `json:"adapters,omitempty"`
+	Parent      *sflow.SFlowHostParentCounters `json:"parent,omitempty"`
+	CPU         *sflow.SFlowHostCPUCounters    `json:"cpu,omitempty"`
+	Memory      *sflow.SFlowHostMemoryCounters `json:"memory,omitempty"`
+	Disk        *sflow.SFlowHostDiskCounters   `json:"disk,omitempty"`
+	NetIO       *sflow.SFlowHostNetIOCounters  `json:"netIO,omitempty"`
+	IP          *sflow.SFlowHostIPCounters     `json:"ip,omitempty"`
+	ICMP        *sflow.SFlowHostICMPCounters   `json:"icmp,omitempty"`
+	TCP         *sflow.SFlowHostTCPCounters    `json:"tcp,omitempty"`
+	UDP         *sflow.SFlowHostUDPCounters    `json:"udp,omitempty"`
+	VirtNode    *sflow.SFlowVirtNodeCounters   `json:"virtNode,omitempty"`
+	LastUpdate  time.Time                      `json:"lastUpdate"`
+}
+
+// XnfvVirtualMachine holds the latest counters hsflowd reports for one
+// virtual machine (a VNF) of the host, and the OVS ports its adapters are
+// attached to
+type XnfvVirtualMachine struct {
+	// SourceClass and SourceIndex are the logical entity data source the
+	// counters are reported with
+	SourceClass uint32            `json:"sourceClass"`
+	SourceIndex uint32            `json:"sourceIndex"`
+	Hostname    string            `json:"hostname"`
+	UUID        string            `json:"uuid"`
+	OSName      sflow.SFlowOSName `json:"osName"`
+	OSRelease   string            `json:"osRelease"`
+	// ParentClass and ParentIndex are the data source of the host, or of
+	// the VM the VM runs in, from its parent record (container_type and
+	// container_index), ParentHostname is its hostname
+	ParentClass    uint32                         `json:"parentClass"`
+	ParentIndex    uint32                         `json:"parentIndex"`
+	ParentHostname string                         `json:"parentHostname"`
+	Adapters       []XnfvHostAdapter              `json:"adapters,omitempty"`
+	Ports          []XnfvHostPort                 `json:"ports,omitempty"`
+	CPU            *sflow.SFlowVirtCPUCounters    `json:"cpu,omitempty"`
+	Memory         *sflow.SFlowVirtMemoryCounters `json:"memory,omitempty"`
+	Disk           *sflow.SFlowVirtDiskCounters   `json:"disk,omitempty"`
+	NetIO          *sflow.SFlowVirtNetIOCounters  `json:"netIO,omitempty"`
+	LastUpdate     time.Time                      `json:"lastUpdate"`
+}
+
+// XnfvAgent is one sFlow agent, identified by its agent address, with
+// the host it runs on and the OVS switches (datapath IDs, hex) it reports
+type XnfvAgent struct {
+	Address         string               `json:"address"`
+	Host            *XnfvAgentHost       `json:"host,omitempty"`
+	SwitchDataPaths []string             `json:"switchDataPaths"`
+	VirtualMachines []XnfvVirtualMachine `json:"virtualMachines,omitempty"`
+	Datapath        *XnfvOVSDatapath     `json:"datapath,omitempty"`
+	LastSeen        time.Time            `json:"lastSeen"`
+}
+
+// XnfvAgentRegistry links the host counters of hsflowd and the OpenFlow
+// port counters of OVS that share an agent address, so the metrics of a
+// compute node can be found from one of its switches and the other way
+// around. It is safe for concurrent use.
+type XnfvAgentRegistry struct {
+	config XnfvAgentRegistryConfig
+	mutex  sync.RWMutex
+	agents map[string]*XnfvAgent
+}
+
+func NewXnfvAgentRegistry(config XnfvAgentRegistryConfig) *XnfvAgentRegistry {
+	return &XnfvAgentRegistry{config: config, agents: map[string]*XnfvAgent{}}
+}
+
+// ObserveDatagram records the switches, host, VM and OVS datapath
+// counters found in the counter samples of a datagram decoded by
+// sflow.Decode, and returns the datapath events
+// they raise
+func (r *XnfvAgentRegistry) ObserveDatagram(datagram sflow.Datagram, now time.Time) []XnfvDatapathEvent {
+	if datagram.AgentAddress == nil {
+		return nil
+	}
+	address := datagram.AgentAddress.String()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	agent, ok := r.agents[address]
+	if !ok {
+		agent = &XnfvAgent{Address: address}
+		r.agents[address] = agent
+	}
+	agent.LastSeen = now
+	var events []XnfvDatapathEvent
+	for _, counterSample := range datagram.CounterSamples {
+		if isVirtualMachineSample(counterSample) {
+			agent.observeVirtualMachine(counterSample, now)
+			continue
+		}
+		for _, record := range counterSample.Records {
+			if ofPortCounter, ok := record.(sflow.SFlowOFPortCounters); ok {
+				agent.addSwitch(dataPathString(ofPortCounter.OfDataPathId))
+				continue
+			}
+			if datapathCounter, ok := record.(sflow.SFlowOVSDPCounters); ok {
+				events = append(events, agent.observeOVSDatapath(datapathCounter, r.config, now)...)
+				continue
+			}
+			if isHostCounterRecord(record) {
+				if agent.Host == nil {
+					agent.Host = &XnfvAgentHost{}
+				}
+				agent.Host.apply(record)
+				agent.Host.SourceClass, agent.Host.SourceIndex = uint32(counterSample.SourceIDClass), uint32(counterSample.SourceIDIndex)
+				agent.Host.LastUpdate = now
+			}
+		}
+	}
+	return events
+}
+
+func (a *XnfvAgent) addSwitch(dataPath string) {
+	for _, known := range a.SwitchDataPaths {
+		if known == dataPath {
+			return
+		}
+	}
+	a.SwitchDataPaths = append(a.SwitchDataPaths, dataPath)
+	sort.Strings(a.SwitchDataPaths)
+}
+
+func isHostCounterRecord(record sflow.SFlowRecord) bool {
+	switch record.(type) {
+	case sflow.SFlowHostDescrCounters, sflow.SFlowHostAdaptersCounters, sflow.SFlowHostParentCounters,
+		sflow.SFlowHostCPUCounters, sflow.SFlowHostMemoryCounters, sflow.SFlowHostDiskCounters, sflow.SFlowHostNetIOCounters,
+		sflow.SFlowHostIPCounters, sflow.SFlowHostICMPCounters, sflow.SFlowHostTCPCounters, sflow.SFlowHostUDPCounters,
+		sflow.SFlowVirtNodeCounters:
+		return true
+	}
+	return false
+}
+
+// apply stores a host counter record. Records are replaced, never
+// modified, so the pointers can be shared with readers.
+func (h *XnfvAgentHost) apply(record sflow.SFlowRecord) {
+	switch r := record.(type) {
+	case sflow.SFlowHostDescrCounters:
+		h.Hostname, h.UUID = r.Hostname, formatUUID(r.UUID)
+		h.MachineType, h.OSName, h.OSRelease = r.MachineType, r.OSName, r.OSRelease
+	case sflow.SFlowHostAdaptersCounters:
+		h.Adapters = hostAdapters(r)
+	case sflow.SFlowHostParentCounters:
+		h.Parent = &r
+	case sflow.SFlowHostCPUCounters:
+		h.CPU = &r
+	case sflow.SFlowHostMemoryCounters:
+		h.Memory = &r
+	case sflow.SFlowHostDiskCounters:
+		h.Disk = &r
+	case sflow.SFlowHostNetIOCounters:
+		h.NetIO = &r
+	case sflow.SFlowHostIPCounters:
+		h.IP = &r
+	case sflow.SFlowHostICMPCounters:
+		h.ICMP = &r
+	case sflow.SFlowHostTCPCounters:
+		h.TCP = &r
+	case sflow.SFlowHostUDPCounters:
+		h.UDP = &r
+	case sflow.SFlowVirtNodeCounters:
+		h.VirtNode = &r
+	}
+}
+
+func hostAdapters(record sflow.SFlowHostAdaptersCounters) []XnfvHostAdapter {
+	var adapters []XnfvHostAdapter
+	for _, adapter := range record.Adapters {
+		hostAdapter := XnfvHostAdapter{IfIndex: adapter.IfIndex}
+		for _, mac := range adapter.MacAddresses {
+			hostAdapter.MacAddresses = append(hostAdapter.MacAddresses, mac.String())
+		}
+		adapters = append(adapters, hostAdapter)
+	}
+	return adapters
+}
+
+// isVirtualMachineSample reports whether a counter sample describes a
+// virtual machine: hsflowd sends a host parent record and the virtual
+// CPU / memory / disk / network records with each VM's counters
+func isVirtualMachineSample(counterSample sflow.SFlowCounterSample) bool {
+	for _, record := range counterSample.Records {
+		switch record.(type) {
+		case sflow.SFlowHostParentCounters, sflow.SFlowVirtCPUCounters, sflow.SFlowVirtMemoryCounters,
+			sflow.SFlowVirtDiskCounters, sflow.SFlowVirtNetIOCounters:
+			return true
+		}
+	}
+	return false
+}
+
+// observeVirtualMachine stores the counters of a VM sample. The VM entry is
+// replaced, never modified, so copies handed to readers stay valid.
+func (a *XnfvAgent) observeVirtualMachine(counterSample sflow.SFlowCounterSample, now time.Time) {
+	index := -1
+	vm := XnfvVirtualMachine{SourceClass: uint32(counterSample.SourceIDClass), SourceIndex: uint32(counterSample.SourceIDIndex)}
+	for i := range a.VirtualMachines {
+		if a.VirtualMachines[i].SourceIndex == vm.SourceIndex {
+			index, vm = i, a.VirtualMachines[i]
+			break
+		}
+	}
+	for _, record := range counterSample.Records {
+		switch r := record.(type) {
+		case sflow.SFlowHostParentCounters:
+			vm.ParentClass, vm.ParentIndex = uint32(r.ContainerType), r.ContainerIndex
+		case sflow.SFlowHostDescrCounters:
+			vm.Hostname, vm.UUID = r.Hostname, formatUUID(r.UUID)
+			vm.OSName, vm.OSRelease = r.OSName, r.OSRelease
+		case sflow.SFlowHostAdaptersCounters:
+			vm.Adapters = hostAdapters(r)
+		case sflow.SFlowVirtCPUCounters:
+			vm.CPU = &r
+		case sflow.SFlowVirtMemoryCounters:
+			vm.Memory = &r
+		case sflow.SFlowVirtDiskCounters:
+			vm.Disk = &r
+		case sflow.SFlowVirtNetIOCounters:
+			vm.NetIO = &r
+		}
+	}
+	vm.LastUpdate = now
+	if index < 0 {
+		a.VirtualMachines = append(a.VirtualMachines, vm)
+	} else {
+		a.VirtualMachines[index] = vm
+	}
+}
+
+// XnfvPortResolver finds the OVS port a VM adapter is attached to, from
+// the adapter's ifIndex on one of the agent's switches or from its MAC
+type XnfvPortResolver func(agent string, adapter XnfvHostAdapter) (XnfvHostPort, bool)
+
+// ResolveVirtualMachinePorts links the adapters of the VMs of an agent to
+// OVS ports
+func (r *XnfvAgentRegistry) ResolveVirtualMachinePorts(address string, resolve XnfvPortResolver) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	agent, ok := r.agents[address]
+	if !ok {
+		return
+	}
+	for i := range agent.VirtualMachines {
+		vm := &agent.VirtualMachines[i]
+		var ports []XnfvHostPort
+		for _, adapter := range vm.Adapters {
+			if port, ok := resolve(agent.Address, adapter); ok {
+				ports = append(ports, port)
+			}
+		}
+		vm.Ports = ports
+	}
+}
+
+// parentHostname returns the hostname of the host, or of the VM, with the
+// data source class and index of a VM's parent record
+func (a *XnfvAgent) parentHostname(vm XnfvVirtualMachine) string {
+	if a.Host != nil && a.Host.SourceClass == vm.ParentClass && a.Host.SourceIndex == vm.ParentIndex {
+		return a.Host.Hostname
+	}
+	for _, parent := range a.VirtualMachines {
+		if parent.SourceClass == vm.ParentClass && parent.SourceIndex == vm.ParentIndex {
+			return parent.Hostname
+		}
+	}
+	return ""
+}
+
+func formatUUID(uuid []byte) string {
+	if len(uuid) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
+// AgeOut removes the agents not heard from since MaxAge before now, and
+// the VMs (destroyed VNFs) whose counters were last reported before that
+func (r *XnfvAgentRegistry) AgeOut(now time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for address, agent := range r.agents {
+		if now.Sub(agent.LastSeen) > r.config.MaxAge {
+			delete(r.agents, address)
+			continue
+		}
+		// a new slice, the copies handed to readers share the old one
+		var vms []XnfvVirtualMachine
+		for _, vm := range agent.VirtualMachines {
+			if now.Sub(vm.LastUpdate) <= r.config.MaxAge {
+				vms = append(vms, vm)
+			}
+		}
+		agent.VirtualMachines = vms
+	}
+}
+
+// ****************************************************************************************************
+//  Agent Query API
+// ****************************************************************************************************
+
+func (r *XnfvAgentRegistry) copyAgent(agent *XnfvAgent) XnfvAgent {
+	copied := *agent
+	copied.SwitchDataPaths = append([]string(nil), agent.SwitchDataPaths...)
+	if agent.Host != nil {
+		host := *agent.Host
+		host.Adapters = append([]XnfvHostAdapter(nil), agent.Host.Adapters...)
+		copied.Host = &host
+	}
+	copied.VirtualMachines = append([]XnfvVirtualMachine(nil), agent.VirtualMachines...)
+	for i := range copied.VirtualMachines {
+		copied.VirtualMachines[i].ParentHostname = agent.parentHostname(copied.VirtualMachines[i])
+	}
+	if agent.Datapath != nil {
+		datapath := *agent.Datapath
+		copied.Datapath = &datapath
+	}
+	return copied
+}
+
+// Agent returns the agent with the given address
+func (r *XnfvAgentRegistry) Agent(address string) (XnfvAgent, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if agent, ok := r.agents[address]; ok {
+		return r.copyAgent(agent), true
+	}
+	return XnfvAgent{}, false
+}
+
+// AgentForSwitch returns the agent reporting the switch with the given
+// datapath ID (hex), and with it the host the switch runs on
+func (r *XnfvAgentRegistry) AgentForSwitch(switchDataPath string) (XnfvAgent, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, agent := range r.agents {
+		for _, dataPath := range agent.SwitchDataPaths {
+			if dataPath == switchDataPath {
+				return r.copyAgent(agent), true
+			}
+		}
+	}
+	return XnfvAgent{}, false
+}
+
+// Agents returns every known agent
+func (r *XnfvAgentRegistry) Agents() []XnfvAgent {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	agents := make([]XnfvAgent, 0, len(r.agents))
+	for _, agent := range r.agents {
+		agents = append(agents, r.copyAgent(agent))
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Address < agents[j].Address })
+	return agents
+}
+
+// ServeHTTP answers GET /agents[?address=|switch=] with the agents and
+// their host metrics as JSON
+func (r *XnfvAgentRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	var result interface{}
+	var found bool
+	switch {
+	case query.Get("address") != "":
+		result, found = r.Agent(query.Get("address"))
+	case query.Get("switch") != "":
+		result, found = r.AgentForSwitch(query.Get("switch"))
+	default:
+		result, found = r.Agents(), true
+	}
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+	writeJSON(w, result)
+}
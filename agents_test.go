@@ -0,0 +1,229 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// xnfvTestAgentDatagram wraps counter samples in a datagram of the agent
+// 192.0.2.1
+func xnfvTestAgentDatagram(counterSamples ...sflow.SFlowCounterSample) sflow.Datagram {
+	return sflow.Datagram{AgentAddress: net.ParseIP("192.0.2.1"), CounterSamples: counterSamples}
+}
+
+// xnfvTestHostSample is the host counter sample of hsflowd, the physical
+// entity 2:1
+func xnfvTestHostSample(records ...sflow.SFlowRecord) sflow.SFlowCounterSample {
+	return sflow.SFlowCounterSample{SourceIDClass: 2, SourceIDIndex: 1, Records: records}
+}
+
+// xnfvTestVMSample is the counter sample of the VM 3:index running in the
+// parent data source class:parent
+func xnfvTestVMSample(index uint32, hostname string, class uint32, parent uint32, records ...sflow.SFlowRecord) sflow.SFlowCounterSample {
+	records = append(records,
+		sflow.SFlowHostDescrCounters{Hostname: hostname},
+		sflow.SFlowHostParentCounters{ContainerType: sflow.SFlowSourceFormat(class), ContainerIndex: parent},
+		sflow.SFlowVirtCPUCounters{NrVirtCPU: 2},
+	)
+	return sflow.SFlowCounterSample{SourceIDClass: 3, SourceIDIndex: sflow.SFlowSourceValue(index), Records: records}
+}
+
+func TestXnfvAgentRegistryVirtualMachines(t *testing.T) {
+	registry := NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig())
+	registry.ObserveDatagram(xnfvTestAgentDatagram(
+		xnfvTestHostSample(sflow.SFlowHostDescrCounters{Hostname: "compute-1"}),
+		xnfvTestVMSample(5, "vnf-fw", 2, 1),
+		// a container running in the VM 3:5
+		xnfvTestVMSample(6, "vnf-fw-agent", 3, 5),
+		// the parent is not reported by the agent
+		xnfvTestVMSample(7, "vnf-lb", 2, 9),
+	), xnfvTestEpoch)
+
+	agent, ok := registry.Agent("192.0.2.1")
+	if !ok || len(agent.VirtualMachines) != 3 {
+		t.Fatalf("agent %+v", agent)
+	}
+	parents := map[string]string{}
+	for _, vm := range agent.VirtualMachines {
+		if vm.SourceClass != 3 || vm.CPU == nil || vm.CPU.NrVirtCPU != 2 {
+			t.Fatalf("vm %+v", vm)
+		}
+		parents[vm.Hostname] = vm.ParentHostname
+	}
+	want := map[string]string{"vnf-fw": "compute-1", "vnf-fw-agent": "vnf-fw", "vnf-lb": ""}
+	for hostname, parent := range want {
+		if parents[hostname] != parent {
+			t.Errorf("%s: parent %q, want %q", hostname, parents[hostname], parent)
+		}
+	}
+
+	// the VM 3:7 was destroyed, the host keeps reporting the others
+	for minute := time.Duration(1); minute <= 11; minute++ {
+		registry.ObserveDatagram(xnfvTestAgentDatagram(
+			xnfvTestHostSample(sflow.SFlowHostDescrCounters{Hostname: "compute-1"}),
+			xnfvTestVMSample(5, "vnf-fw", 2, 1),
+			xnfvTestVMSample(6, "vnf-fw-agent", 3, 5),
+		), xnfvTestEpoch.Add(minute*time.Minute))
+	}
+	registry.AgeOut(xnfvTestEpoch.Add(11 * time.Minute))
+	agent, _ = registry.Agent("192.0.2.1")
+	if len(agent.VirtualMachines) != 2 || agent.VirtualMachines[0].Hostname != "vnf-fw" || agent.VirtualMachines[1].Hostname != "vnf-fw-agent" {
+		t.Fatalf("virtual machines after AgeOut %+v", agent.VirtualMachines)
+	}
+}
+
+func TestXnfvAgentRegistryResolveVirtualMachinePorts(t *testing.T) {
+	adapters := sflow.SFlowHostAdaptersCounters{Adapters: []sflow.SFlowHostAdapter{
+		{IfIndex: 12, MacAddresses: []net.HardwareAddr{{2, 0, 0, 0, 0, 5}}},
+		{IfIndex: 13},
+	}}
+	registry := NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig())
+	registry.ObserveDatagram(xnfvTestAgentDatagram(xnfvTestVMSample(5, "vnf-fw", 2, 1, adapters)), xnfvTestEpoch)
+	other := xnfvTestAgentDatagram(xnfvTestVMSample(5, "vnf-dns", 2, 1, adapters))
+	other.AgentAddress = net.ParseIP("192.0.2.2")
+	registry.ObserveDatagram(other, xnfvTestEpoch)
+
+	var resolved []string
+	registry.ResolveVirtualMachinePorts("192.0.2.1", func(agent string, adapter XnfvHostAdapter) (XnfvHostPort, bool) {
+		resolved = append(resolved, agent)
+		if adapter.IfIndex != 12 || adapter.MacAddresses[0] != "02:00:00:00:00:05" {
+			return XnfvHostPort{}, false
+		}
+		return XnfvHostPort{SwitchDataPath: "0000000000000001", PortName: "vnet0", OfPort: 3, InputInterface: 12}, true
+	})
+	// only the adapters of the agent that sent the datagram are resolved
+	if len(resolved) != 2 || resolved[0] != "192.0.2.1" || resolved[1] != "192.0.2.1" {
+		t.Fatalf("resolved %v", resolved)
+	}
+	agent, _ := registry.Agent("192.0.2.1")
+	if ports := agent.VirtualMachines[0].Ports; len(ports) != 1 || ports[0].PortName != "vnet0" {
+		t.Fatalf("ports %+v", ports)
+	}
+	if agent, _ := registry.Agent("192.0.2.2"); len(agent.VirtualMachines[0].Ports) != 0 {
+		t.Fatalf("ports of the other agent %+v", agent.VirtualMachines[0].Ports)
+	}
+}
+
+func TestXnfvAgentRegistryHost(t *testing.T) {
+	registry := NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig())
+	registry.ObserveDatagram(xnfvTestAgentDatagram(xnfvTestHostSample(
+		sflow.SFlowHostDescrCounters{Hostname: "compute-1", UUID: []byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}, OSRelease: "5.4.0"},
+		sflow.SFlowHostCPUCounters{LoadOne: 1.5, CPUNum: 8},
+		sflow.SFlowHostMemoryCounters{MemTotal: 16 << 30, MemFree: 4 << 30},
+		sflow.SFlowHostDiskCounters{DiskTotal: 100 << 30, DiskFree: 60 << 30},
+		sflow.SFlowHostAdaptersCounters{Adapters: []sflow.SFlowHostAdapter{{IfIndex: 2, MacAddresses: []net.HardwareAddr{{2, 0, 0, 0, 0, 2}}}}},
+	)), xnfvTestEpoch)
+
+	agent, ok := registry.Agent("192.0.2.1")
+	if !ok || agent.Host == nil || len(agent.VirtualMachines) != 0 {
+		t.Fatalf("agent %+v", agent)
+	}
+	host := agent.Host
+	if host.Hostname != "compute-1" || host.UUID != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" || host.OSRelease != "5.4.0" || host.SourceClass != 2 || host.SourceIndex != 1 {
+		t.Errorf("description %+v", host)
+	}
+	if host.CPU == nil || host.CPU.LoadOne != 1.5 || host.CPU.CPUNum != 8 {
+		t.Errorf("cpu %+v", host.CPU)
+	}
+	if host.Memory == nil || host.Memory.MemFree != 4<<30 || host.Disk == nil || host.Disk.DiskFree != 60<<30 {
+		t.Errorf("memory %+v disk %+v", host.Memory, host.Disk)
+	}
+	if len(host.Adapters) != 1 || host.Adapters[0].IfIndex != 2 || host.Adapters[0].MacAddresses[0] != "02:00:00:00:00:02" {
+		t.Errorf("adapters %+v", host.Adapters)
+	}
+	if !host.LastUpdate.Equal(xnfvTestEpoch) || !agent.LastSeen.Equal(xnfvTestEpoch) {
+		t.Errorf("updated %v seen %v", host.LastUpdate, agent.LastSeen)
+	}
+
+	// the next record replaces the previous one, the others are kept
+	registry.ObserveDatagram(xnfvTestAgentDatagram(xnfvTestHostSample(sflow.SFlowHostCPUCounters{LoadOne: 3, CPUNum: 8})), xnfvTestEpoch.Add(time.Minute))
+	agent, _ = registry.Agent("192.0.2.1")
+	if agent.Host.CPU.LoadOne != 3 || agent.Host.Memory.MemFree != 4<<30 || agent.Host.Hostname != "compute-1" || !agent.Host.LastUpdate.Equal(xnfvTestEpoch.Add(time.Minute)) {
+		t.Fatalf("host after the update %+v", agent.Host)
+	}
+	// the copy does not share the adapters
+	agent.Host.Adapters[0].IfIndex = 9
+	if agent, _ := registry.Agent("192.0.2.1"); agent.Host.Adapters[0].IfIndex != 2 {
+		t.Fatalf("the copy shares the adapters")
+	}
+}
+
+// The OpenFlow port and the datapath counters of the same agent link the
+// switch to the host it runs on
+func TestXnfvAgentRegistrySwitch(t *testing.T) {
+	registry := NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig())
+	switchSample := func(dataPath byte, ofPort uint32) sflow.SFlowCounterSample {
+		return sflow.SFlowCounterSample{SourceIDIndex: sflow.SFlowSourceValue(ofPort), Records: sflow.SFlowRecords{
+			sflow.SFlowOFPortCounters{OfDataPathId: []byte{0, 0, 0, 0, 0, 0, 0, dataPath}, OfPort: ofPort},
+		}}
+	}
+	datapathSample := func(hits uint32, misses uint32) sflow.SFlowCounterSample {
+		return xnfvTestHostSample(sflow.SFlowOVSDPCounters{Hits: hits, Misses: misses, Flows: 10})
+	}
+	events := registry.ObserveDatagram(xnfvTestAgentDatagram(
+		xnfvTestHostSample(sflow.SFlowHostDescrCounters{Hostname: "compute-1"}),
+		switchSample(2, 1), switchSample(1, 1), switchSample(1, 2),
+		datapathSample(1000, 10),
+	), xnfvTestEpoch)
+	other := xnfvTestAgentDatagram(switchSample(3, 1))
+	other.AgentAddress = net.ParseIP("192.0.2.2")
+	registry.ObserveDatagram(other, xnfvTestEpoch)
+	if len(events) != 0 {
+		t.Fatalf("events of the first datapath record %v", events)
+	}
+
+	agent, ok := registry.AgentForSwitch("0000000000000001")
+	if !ok || agent.Address != "192.0.2.1" || agent.Host == nil || agent.Host.Hostname != "compute-1" {
+		t.Fatalf("agent %+v", agent)
+	}
+	if len(agent.SwitchDataPaths) != 2 || agent.SwitchDataPaths[0] != "0000000000000001" || agent.SwitchDataPaths[1] != "0000000000000002" {
+		t.Errorf("switches %v", agent.SwitchDataPaths)
+	}
+	if agent.Datapath == nil || agent.Datapath.Counters.Flows != 10 {
+		t.Errorf("datapath %+v", agent.Datapath)
+	}
+	if agent, ok := registry.AgentForSwitch("0000000000000003"); !ok || agent.Address != "192.0.2.2" || agent.Host != nil || agent.Datapath != nil {
+		t.Errorf("agent of the other switch %+v", agent)
+	}
+	if _, ok := registry.AgentForSwitch("0000000000000004"); ok {
+		t.Errorf("agent of an unknown switch")
+	}
+
+	// the datapath rates and events of the next record
+	events = registry.ObserveDatagram(xnfvTestAgentDatagram(datapathSample(11000, 20010)), xnfvTestEpoch.Add(10*time.Second))
+	if len(events) != 1 || events[0].Type != XnfvDatapathCacheThrashing || events[0].Agent != "192.0.2.1" || len(events[0].SwitchDataPaths) != 2 {
+		t.Fatalf("events %+v", events)
+	}
+	if agent, _ := registry.AgentForSwitch("0000000000000002"); agent.Datapath.HitRate != 1000 || agent.Datapath.MissRate != 2000 {
+		t.Fatalf("datapath %+v", agent.Datapath)
+	}
+}
+
+func TestXnfvAgentRegistryAgeOut(t *testing.T) {
+	registry := NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig())
+	registry.ObserveDatagram(xnfvTestAgentDatagram(xnfvTestHostSample(sflow.SFlowHostDescrCounters{Hostname: "compute-1"})), xnfvTestEpoch)
+	other := xnfvTestAgentDatagram(xnfvTestHostSample(sflow.SFlowHostDescrCounters{Hostname: "compute-2"}))
+	other.AgentAddress = net.ParseIP("192.0.2.2")
+	registry.ObserveDatagram(other, xnfvTestEpoch.Add(9*time.Minute))
+	// a datagram without counters still tells the agent is alive
+	registry.ObserveDatagram(xnfvTestAgentDatagram(), xnfvTestEpoch.Add(8*time.Minute))
+	// without an address it is not attributed to any agent
+	registry.ObserveDatagram(sflow.Datagram{}, xnfvTestEpoch.Add(8*time.Minute))
+
+	registry.AgeOut(xnfvTestEpoch.Add(10 * time.Minute))
+	if agents := registry.Agents(); len(agents) != 2 || agents[0].Address != "192.0.2.1" || agents[1].Address != "192.0.2.2" {
+		t.Fatalf("agents %+v", agents)
+	}
+	registry.AgeOut(xnfvTestEpoch.Add(18*time.Minute + time.Second))
+	agents := registry.Agents()
+	if len(agents) != 1 || agents[0].Address != "192.0.2.2" {
+		t.Fatalf("agents after AgeOut %+v", agents)
+	}
+	registry.AgeOut(xnfvTestEpoch.Add(19*time.Minute + time.Second))
+	if _, ok := registry.Agent("192.0.2.2"); ok {
+		t.Fatalf("agent 192.0.2.2 was not aged out")
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"log"
+	"net"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Flow Analytics Pipeline
+// ****************************************************************************************************
+
+// xnfvAnalytics feeds every normalized flow sample, whichever decoder
+// produced it, to the analytics modules and prints their events
+type xnfvAnalytics struct {
+	ddosDetector *XnfvDDoSDetector
+	elephants    *XnfvElephantDetector
+	mitigator    *XnfvMitigator
+	scanDetector *XnfvScanDetector
+	hostTable    *XnfvHostTable
+	bindingTable *XnfvBindingTable
+	agents       *XnfvAgentRegistry
+	portTable    *XnfvPortTable
+	appTable     *XnfvAppTable
+	dropTable    *XnfvDropTable
+	connections  *XnfvConnectionTable
+	queues       *XnfvQueueTable
+	wireless     *XnfvWirelessTable
+	lastAgeOut   time.Time
+}
+
+func newXnfvAnalytics(mitigationConfig XnfvMitigationConfig) *xnfvAnalytics {
+	return &xnfvAnalytics{
+		ddosDetector: NewXnfvDDoSDetector(DefaultXnfvDDoSConfig()),
+		elephants:    NewXnfvElephantDetector(DefaultXnfvElephantConfig()),
+		mitigator:    NewXnfvMitigator(mitigationConfig),
+		scanDetector: NewXnfvScanDetector(DefaultXnfvScanConfig()),
+		hostTable:    NewXnfvHostTable(DefaultXnfvHostTableConfig()),
+		bindingTable: NewXnfvBindingTable(DefaultXnfvBindingConfig()),
+		agents:       NewXnfvAgentRegistry(DefaultXnfvAgentRegistryConfig()),
+		portTable:    NewXnfvPortTable(DefaultXnfvPortTableConfig()),
+		appTable:     NewXnfvAppTable(DefaultXnfvAppTableConfig()),
+		dropTable:    NewXnfvDropTable(DefaultXnfvDropTableConfig()),
+		connections:  NewXnfvConnectionTable(DefaultXnfvConnectionTableConfig()),
+		queues:       NewXnfvQueueTable(DefaultXnfvQueueTableConfig()),
+		wireless:     NewXnfvWirelessTable(DefaultXnfvWirelessTableConfig()),
+	}
+}
+
+func (a *xnfvAnalytics) observeFlowSample(flowSample XnfvFlowSample) {
+	a.reportAttacks(a.ddosDetector.Observe(flowSample), flowSample.Timestamp)
+	a.reportElephants(a.elephants.Observe(flowSample), flowSample.Timestamp)
+	for _, scanEvent := range a.scanDetector.Observe(flowSample) {
+		printXnfvEvent(scanEvent)
+	}
+	if movedEvent := a.hostTable.Observe(flowSample); movedEvent != nil {
+		printXnfvEvent(movedEvent)
+	}
+	for _, bindingEvent := range a.bindingTable.Observe(flowSample) {
+		printXnfvEvent(bindingEvent)
+	}
+	a.connections.Observe(flowSample)
+	a.queues.Observe(flowSample)
+}
+
+// observeGenericDatagram updates the agent registry, the port, application,
+// wireless and drop tables with the samples of a datagram, prints the OVS
+// datapath, port health and drop events and links the VMs it reports to
+// the OVS ports of the inventory
+func (a *xnfvAnalytics) observeGenericDatagram(datagram sflow.Datagram, xnfvAllSwitches *XnfvAllSwitches, now time.Time) {
+	for _, datapathEvent := range a.agents.ObserveDatagram(datagram, now) {
+		printXnfvEvent(datapathEvent)
+	}
+	for _, portEvent := range a.portTable.ObserveDatagram(datagram, now) {
+		printXnfvEvent(portEvent)
+	}
+	a.appTable.ObserveDatagram(datagram, now)
+	a.wireless.ObserveDatagram(datagram, now)
+	dropEvents := a.dropTable.ObserveDatagram(datagram, func(agent string, ifIndex uint32) (string, string, bool) {
+		xnfvSwitch, port := xnfvAllSwitches.lookupSwitchPort(net.ParseIP(agent), ifIndex)
+		if port == nil {
+			return "", "", false
+		}
+		return dataPathString(xnfvSwitch.switchDataPath), port.interfacePortName, true
+	}, now)
+	for _, dropEvent := range dropEvents {
+		printXnfvEvent(dropEvent)
+	}
+	if datagram.AgentAddress == nil {
+		return
+	}
+	// only the VMs of the agent that sent the datagram, the ports of the
+	// other agents did not change
+	a.agents.ResolveVirtualMachinePorts(datagram.AgentAddress.String(), func(agent string, adapter XnfvHostAdapter) (XnfvHostPort, bool) {
+		// the adapter's ifIndex is the one OVS reports the port counters with
+		if xnfvSwitch, port := xnfvAllSwitches.lookupSwitchPort(net.ParseIP(agent), adapter.IfIndex); port != nil {
+			return XnfvHostPort{dataPathString(xnfvSwitch.switchDataPath), port.interfacePortName, port.ofPort(), adapter.IfIndex}, true
+		}
+		// otherwise find where the host table saw the VM's MAC
+		for _, mac := range adapter.MacAddresses {
+			if location, ok := a.hostTable.Lookup(mac); ok {
+				return location.Port, true
+			}
+		}
+		return XnfvHostPort{}, false
+	})
+}
+
+// reportAttacks prints the attack events and mitigates them
+func (a *xnfvAnalytics) reportAttacks(attackEvents []XnfvAttackEvent, now time.Time) {
+	for _, attackEvent := range attackEvents {
+		printXnfvEvent(attackEvent)
+		mitigations, errs := a.mitigator.MitigateAttack(attackEvent, now)
+		for _, mitigation := range mitigations {
+			printXnfvEvent(mitigation)
+		}
+		for _, err := range errs {
+			log.Println(err)
+		}
+	}
+}
+
+// reportElephants prints the elephant flow events and mitigates them
+func (a *xnfvAnalytics) reportElephants(elephantEvents []XnfvElephantEvent, now time.Time) {
+	for _, elephantEvent := range elephantEvents {
+		printXnfvEvent(elephantEvent)
+		// no rule for the flows entering on a port outside the inventory
+		if elephantEvent.SwitchDataPath == "" {
+			continue
+		}
+		mitigation, err := a.mitigator.MitigateElephant(elephantEvent, now)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		printXnfvEvent(mitigation)
+	}
+}
+
+// tick runs the time based housekeeping: the DDoS, scan and elephant
+// flow windows and mitigation TTLs on every call, table aging once a minute
+func (a *xnfvAnalytics) tick(now time.Time) {
+	a.reportAttacks(a.ddosDetector.Tick(now), now)
+	for _, scanEvent := range a.scanDetector.Tick(now) {
+		printXnfvEvent(scanEvent)
+	}
+	a.reportElephants(a.elephants.Tick(now), now)
+	for _, err := range a.mitigator.Expire(now) {
+		log.Println(err)
+	}
+	if now.Sub(a.lastAgeOut) >= time.Minute {
+		a.hostTable.AgeOut(now)
+		a.bindingTable.AgeOut(now)
+		a.agents.AgeOut(now)
+		a.portTable.AgeOut(now)
+		a.appTable.AgeOut(now)
+		a.dropTable.AgeOut(now)
+		a.connections.AgeOut(now)
+		a.queues.AgeOut(now)
+		a.wireless.AgeOut(now)
+		a.lastAgeOut = now
+	}
+}
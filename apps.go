@@ -0,0 +1,333 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Application (L7) Monitoring
+// ****************************************************************************************************
+
+type XnfvAppTableConfig struct {
+	// LatencySamples is how many sampled operation durations are kept per
+	// application for the latency percentiles
+	LatencySamples int
+	// MaxAge removes applications that have not been reported for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvAppTableConfig() XnfvAppTableConfig {
+	return XnfvAppTableConfig{
+		LatencySamples: 1024,
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// XnfvAppKey identifies an application by the agent embedded in it and
+// the data source index its counter and flow samples share
+type XnfvAppKey struct {
+	Agent      string `json:"agent"`
+	DataSource uint32 `json:"dataSource"`
+}
+
+// XnfvAppLatency summarizes the durations of the sampled operations
+type XnfvAppLatency struct {
+	Samples int     `json:"samples"`
+	MeanMs  float64 `json:"meanMs"`
+	P50Ms   float64 `json:"p50Ms"`
+	P95Ms   float64 `json:"p95Ms"`
+	P99Ms   float64 `json:"p99Ms"`
+	MaxMs   float64 `json:"maxMs"`
+}
+
+// XnfvApplication is the latest counters of an application together with
+// the rates derived from the two last records and the latency of its
+// sampled operations
+type XnfvApplication struct {
+	XnfvAppKey
+	Application string                           `json:"application,omitempty"`
+	HTTP        *sflow.SFlowHTTPCounters         `json:"http,omitempty"`
+	App         *sflow.SFlowAppCounters          `json:"app,omitempty"`
+	Resources   *sflow.SFlowAppResourcesCounters `json:"resources,omitempty"`
+	Workers     *sflow.SFlowAppWorkersCounters   `json:"workers,omitempty"`
+	Memcache    *sflow.SFlowMemcacheCounters     `json:"memcache,omitempty"`
+	VDI         *sflow.SFlowVDICounters          `json:"vdi,omitempty"`
+	// per second rates over the last counter interval, by HTTP status
+	// class (1xx ... 5xx, other) or by application status (OK, TIMEOUT, ...)
+	RequestRate float64            `json:"requestRate"`
+	StatusRates map[string]float64 `json:"statusRates,omitempty"`
+	// ErrorRatio is 5xx (HTTP) or not OK (application) over all requests
+	ErrorRatio float64 `json:"errorRatio"`
+	// SampledStatus counts the status codes of the sampled operations
+	SampledStatus map[string]uint64 `json:"sampledStatus,omitempty"`
+	Latency       XnfvAppLatency    `json:"latency"`
+	Interval      float64           `json:"interval"`
+	LastUpdate    time.Time         `json:"lastUpdate"`
+}
+
+type xnfvAppEntry struct {
+	app XnfvApplication
+	// ring of the last sampled durations, in microseconds
+	durations []uint32
+	next      int
+	// times of the HTTP and application counter records the rates are
+	// derived from
+	httpTime time.Time
+	appTime  time.Time
+}
+
+// XnfvAppTable keeps the HTTP and application counters and the sampled
+// operations of the sFlow agents embedded in VNFs. It is safe for
+// concurrent use.
+type XnfvAppTable struct {
+	config XnfvAppTableConfig
+	mutex  sync.RWMutex
+	apps   map[XnfvAppKey]*xnfvAppEntry
+}
+
+func NewXnfvAppTable(config XnfvAppTableConfig) *XnfvAppTable {
+	return &XnfvAppTable{config: config, apps: map[XnfvAppKey]*xnfvAppEntry{}}
+}
+
+// ObserveDatagram updates the applications with the application counter
+// records and the HTTP, application and memcache operations of a datagram
+func (t *XnfvAppTable) ObserveDatagram(datagram sflow.Datagram, now time.Time) {
+	if datagram.AgentAddress == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	agent := datagram.AgentAddress.String()
+	for _, counterSample := range datagram.CounterSamples {
+		key := XnfvAppKey{agent, uint32(counterSample.SourceIDIndex)}
+		for _, record := range counterSample.Records {
+			switch r := record.(type) {
+			case sflow.SFlowHTTPCounters:
+				t.entry(key).observeHTTP(r, now)
+			case sflow.SFlowAppCounters:
+				t.entry(key).observeApp(r, now)
+			case sflow.SFlowAppResourcesCounters:
+				entry := t.entry(key)
+				entry.app.Resources, entry.app.LastUpdate = &r, now
+			case sflow.SFlowAppWorkersCounters:
+				entry := t.entry(key)
+				entry.app.Workers, entry.app.LastUpdate = &r, now
+			case sflow.SFlowMemcacheCounters:
+				entry := t.entry(key)
+				entry.app.Memcache, entry.app.LastUpdate = &r, now
+			case sflow.SFlowVDICounters:
+				entry := t.entry(key)
+				entry.app.VDI, entry.app.LastUpdate = &r, now
+			}
+		}
+	}
+	for _, flowSample := range datagram.FlowSamples {
+		key := XnfvAppKey{agent, uint32(flowSample.SourceIDIndex)}
+		for _, record := range flowSample.Records {
+			switch r := record.(type) {
+			case sflow.SFlowHTTPRequestFlowRecord:
+				t.entry(key).observeOperation(r.Duration, strconv.Itoa(int(r.Status)), t.config, now)
+			case sflow.SFlowAppOperationFlowRecord:
+				entry := t.entry(key)
+				if entry.app.Application == "" {
+					entry.app.Application = r.Context.Application
+				}
+				entry.observeOperation(r.Duration, r.Status.String(), t.config, now)
+			case sflow.SFlowMemcacheFlowRecord:
+				t.entry(key).observeOperation(r.Duration, r.Status.String(), t.config, now)
+			}
+		}
+	}
+}
+
+func (t *XnfvAppTable) entry(key XnfvAppKey) *xnfvAppEntry {
+	entry, ok := t.apps[key]
+	if !ok {
+		entry = &xnfvAppEntry{app: XnfvApplication{XnfvAppKey: key}}
+		t.apps[key] = entry
+	}
+	return entry
+}
+
+// observeHTTP stores an HTTP counter record and derives the request and
+// status class rates from the previous one. After a restart of the
+// application the record only becomes the reference of the next one.
+func (e *xnfvAppEntry) observeHTTP(counters sflow.SFlowHTTPCounters, now time.Time) {
+	previous, interval := e.app.HTTP, now.Sub(e.httpTime).Seconds()
+	e.app.HTTP, e.app.LastUpdate, e.httpTime = &counters, now, now
+	if e.app.Application == "" {
+		e.app.Application = "http"
+	}
+	if previous == nil || interval <= 0 {
+		return
+	}
+	var deltas xnfvCounterDeltas
+	statuses := map[string]uint32{
+		"1xx":   deltas.delta(previous.Status1XXCount, counters.Status1XXCount),
+		"2xx":   deltas.delta(previous.Status2XXCount, counters.Status2XXCount),
+		"3xx":   deltas.delta(previous.Status3XXCount, counters.Status3XXCount),
+		"4xx":   deltas.delta(previous.Status4XXCount, counters.Status4XXCount),
+		"5xx":   deltas.delta(previous.Status5XXCount, counters.Status5XXCount),
+		"other": deltas.delta(previous.StatusOtherCount, counters.StatusOtherCount),
+	}
+	if deltas.reset {
+		e.clearRates()
+		return
+	}
+	e.setRates(statuses, statuses["5xx"], interval)
+}
+
+// observeApp stores an application counter record and derives the
+// operation rates by status from the previous one
+func (e *xnfvAppEntry) observeApp(counters sflow.SFlowAppCounters, now time.Time) {
+	previous, interval := e.app.App, now.Sub(e.appTime).Seconds()
+	e.app.App, e.app.LastUpdate, e.appTime = &counters, now, now
+	if counters.Application != "" {
+		e.app.Application = counters.Application
+	}
+	// an HTTP record of the same data source has the rates already
+	if previous == nil || interval <= 0 || e.app.HTTP != nil {
+		return
+	}
+	var deltas xnfvCounterDeltas
+	statuses := map[string]uint32{
+		sflow.SFlowAppStatusOK.String():             deltas.delta(previous.StatusOK, counters.StatusOK),
+		sflow.SFlowAppStatusOther.String():          deltas.delta(previous.StatusOther, counters.StatusOther),
+		sflow.SFlowAppStatusTimeout.String():        deltas.delta(previous.StatusTimeout, counters.StatusTimeout),
+		sflow.SFlowAppStatusInternalError.String():  deltas.delta(previous.StatusInternalError, counters.StatusInternalError),
+		sflow.SFlowAppStatusBadRequest.String():     deltas.delta(previous.StatusBadRequest, counters.StatusBadRequest),
+		sflow.SFlowAppStatusForbidden.String():      deltas.delta(previous.StatusForbidden, counters.StatusForbidden),
+		sflow.SFlowAppStatusTooLarge.String():       deltas.delta(previous.StatusTooLarge, counters.StatusTooLarge),
+		sflow.SFlowAppStatusNotImplemented.String(): deltas.delta(previous.StatusNotImplemented, counters.StatusNotImplemented),
+		sflow.SFlowAppStatusNotFound.String():       deltas.delta(previous.StatusNotFound, counters.StatusNotFound),
+		sflow.SFlowAppStatusUnavailable.String():    deltas.delta(previous.StatusUnavailable, counters.StatusUnavailable),
+		sflow.SFlowAppStatusUnauthorized.String():   deltas.delta(previous.StatusUnauthorized, counters.StatusUnauthorized),
+	}
+	if deltas.reset {
+		e.clearRates()
+		return
+	}
+	var total uint32
+	for _, count := range statuses {
+		total += count
+	}
+	e.setRates(statuses, total-statuses[sflow.SFlowAppStatusOK.String()], interval)
+}
+
+func (e *xnfvAppEntry) setRates(statuses map[string]uint32, errors uint32, interval float64) {
+	var total uint32
+	e.app.StatusRates = make(map[string]float64, len(statuses))
+	for status, count := range statuses {
+		e.app.StatusRates[status] = float64(count) / interval
+		total += count
+	}
+	e.app.RequestRate = float64(total) / interval
+	e.app.ErrorRatio = 0
+	if total > 0 {
+		e.app.ErrorRatio = float64(errors) / float64(total)
+	}
+	e.app.Interval = interval
+}
+
+// clearRates drops the rates of the last interval, the counters they
+// were derived from were reset
+func (e *xnfvAppEntry) clearRates() {
+	e.app.RequestRate, e.app.StatusRates, e.app.ErrorRatio, e.app.Interval = 0, nil, 0, 0
+}
+
+func (e *xnfvAppEntry) observeOperation(duration uint32, status string, config XnfvAppTableConfig, now time.Time) {
+	if e.app.SampledStatus == nil {
+		e.app.SampledStatus = map[string]uint64{}
+	}
+	e.app.SampledStatus[status]++
+	if len(e.durations) < config.LatencySamples {
+		e.durations = append(e.durations, duration)
+	} else if config.LatencySamples > 0 {
+		e.durations[e.next] = duration
+		e.next = (e.next + 1) % config.LatencySamples
+	}
+	e.app.LastUpdate = now
+}
+
+// latency computes the percentiles of the kept durations
+func (e *xnfvAppEntry) latency() XnfvAppLatency {
+	if len(e.durations) == 0 {
+		return XnfvAppLatency{}
+	}
+	durations := append([]uint32(nil), e.durations...)
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var sum float64
+	for _, duration := range durations {
+		sum += float64(duration)
+	}
+	// nearest rank
+	percentile := func(p float64) float64 {
+		return float64(durations[int(math.Ceil(p*float64(len(durations))))-1]) / 1000
+	}
+	return XnfvAppLatency{
+		Samples: len(durations),
+		MeanMs:  sum / float64(len(durations)) / 1000,
+		P50Ms:   percentile(0.50),
+		P95Ms:   percentile(0.95),
+		P99Ms:   percentile(0.99),
+		MaxMs:   float64(durations[len(durations)-1]) / 1000,
+	}
+}
+
+// AgeOut removes the applications not reported since MaxAge before now
+func (t *XnfvAppTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, entry := range t.apps {
+		if now.Sub(entry.app.LastUpdate) > t.config.MaxAge {
+			delete(t.apps, key)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Application Query API
+// ****************************************************************************************************
+
+// Applications returns the applications, optionally of one agent only
+func (t *XnfvAppTable) Applications(agent string) []XnfvApplication {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var apps []XnfvApplication
+	for key, entry := range t.apps {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		app := entry.app
+		app.Latency = entry.latency()
+		app.StatusRates = make(map[string]float64, len(entry.app.StatusRates))
+		for status, rate := range entry.app.StatusRates {
+			app.StatusRates[status] = rate
+		}
+		app.SampledStatus = make(map[string]uint64, len(entry.app.SampledStatus))
+		for status, count := range entry.app.SampledStatus {
+			app.SampledStatus[status] = count
+		}
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Agent != apps[j].Agent {
+			return apps[i].Agent < apps[j].Agent
+		}
+		return apps[i].DataSource < apps[j].DataSource
+	})
+	return apps
+}
+
+// ServeHTTP answers GET /apps[?agent=] with the applications as JSON
+func (t *XnfvAppTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.Applications(r.URL.Query().Get("agent")))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+func TestXnfvAppTableHTTPRates(t *testing.T) {
+	http := func(ok uint32, serverErrors uint32) sflow.SFlowRecord {
+		return sflow.SFlowHTTPCounters{Status2XXCount: ok, Status5XXCount: serverErrors}
+	}
+	// requests and server errors over the 10 second intervals
+	steps := []struct {
+		record       sflow.SFlowRecord
+		requests     uint32
+		serverErrors uint32
+	}{
+		{http(0xFFFFFE00, 0), 0, 0},
+		{http(0xFFFFFF00, 100), 356, 100},
+		// the 2xx counter wraps
+		{http(0x000000C8, 200), 556, 100},
+		// the application restarted, the interval is skipped
+		{http(10, 0), 0, 0},
+		{http(410, 100), 500, 100},
+	}
+	table := NewXnfvAppTable(DefaultXnfvAppTableConfig())
+	for i, step := range steps {
+		table.ObserveDatagram(xnfvTestCounterDatagram(7, step.record), xnfvTestEpoch.Add(time.Duration(i)*10*time.Second))
+		apps := table.Applications("192.0.2.1")
+		if len(apps) != 1 || apps[0].Application != "http" || apps[0].DataSource != 7 {
+			t.Fatalf("step %d: applications %+v", i, apps)
+		}
+		if want := float64(step.requests) / 10; apps[0].RequestRate != want {
+			t.Fatalf("step %d: request rate %v, want %v", i, apps[0].RequestRate, want)
+		}
+		var ratio float64
+		if step.requests > 0 {
+			ratio = float64(step.serverErrors) / float64(step.requests)
+		}
+		if apps[0].ErrorRatio != ratio {
+			t.Fatalf("step %d: error ratio %v, want %v", i, apps[0].ErrorRatio, ratio)
+		}
+	}
+}
+
+func TestXnfvAppTableAppRates(t *testing.T) {
+	table := NewXnfvAppTable(DefaultXnfvAppTableConfig())
+	table.ObserveDatagram(xnfvTestCounterDatagram(1, sflow.SFlowAppCounters{Application: "payments", StatusOK: 100, StatusTimeout: 5}), xnfvTestEpoch)
+	table.ObserveDatagram(xnfvTestCounterDatagram(1, sflow.SFlowAppCounters{Application: "payments", StatusOK: 280, StatusTimeout: 25}), xnfvTestEpoch.Add(20*time.Second))
+
+	apps := table.Applications("")
+	if len(apps) != 1 || apps[0].Application != "payments" {
+		t.Fatalf("applications %+v", apps)
+	}
+	if apps[0].RequestRate != 10 || apps[0].ErrorRatio != 0.1 || apps[0].Interval != 20 {
+		t.Fatalf("rates %+v", apps[0])
+	}
+	if apps[0].StatusRates["OK"] != 9 || apps[0].StatusRates["TIMEOUT"] != 1 {
+		t.Fatalf("status rates %v", apps[0].StatusRates)
+	}
+
+	// a restart of the application drops the rates of the last interval
+	table.ObserveDatagram(xnfvTestCounterDatagram(1, sflow.SFlowAppCounters{Application: "payments", StatusOK: 3}), xnfvTestEpoch.Add(40*time.Second))
+	apps = table.Applications("")
+	if apps[0].RequestRate != 0 || apps[0].ErrorRatio != 0 || len(apps[0].StatusRates) != 0 {
+		t.Fatalf("rates after the restart %+v", apps[0])
+	}
+}
+
+func TestXnfvAppTableLatency(t *testing.T) {
+	config := DefaultXnfvAppTableConfig()
+	config.LatencySamples = 100
+	table := NewXnfvAppTable(config)
+	datagram := sflow.Datagram{AgentAddress: net.ParseIP("192.0.2.1")}
+	// 1 ... 200 ms, only the last 100 are kept
+	for duration := uint32(1); duration <= 200; duration++ {
+		record := sflow.SFlowHTTPRequestFlowRecord{Duration: duration * 1000, Status: 200}
+		if duration == 200 {
+			record.Status = 503
+		}
+		datagram.FlowSamples = append(datagram.FlowSamples, sflow.SFlowFlowSample{SourceIDIndex: 4, Records: []sflow.SFlowRecord{record}})
+	}
+	table.ObserveDatagram(datagram, xnfvTestEpoch)
+
+	apps := table.Applications("")
+	if len(apps) != 1 {
+		t.Fatalf("applications %+v", apps)
+	}
+	want := XnfvAppLatency{Samples: 100, MeanMs: 150.5, P50Ms: 150, P95Ms: 195, P99Ms: 199, MaxMs: 200}
+	if apps[0].Latency != want {
+		t.Fatalf("latency %+v, want %+v", apps[0].Latency, want)
+	}
+	if apps[0].SampledStatus["200"] != 199 || apps[0].SampledStatus["503"] != 1 {
+		t.Fatalf("sampled status %v", apps[0].SampledStatus)
+	}
+}
+
+func TestXnfvAppTableAgeOut(t *testing.T) {
+	table := NewXnfvAppTable(DefaultXnfvAppTableConfig())
+	table.ObserveDatagram(xnfvTestCounterDatagram(1, sflow.SFlowHTTPCounters{}), xnfvTestEpoch)
+	table.AgeOut(xnfvTestEpoch.Add(5 * time.Minute))
+	if len(table.Applications("")) != 1 {
+		t.Fatalf("application removed before MaxAge")
+	}
+	table.AgeOut(xnfvTestEpoch.Add(11 * time.Minute))
+	if len(table.Applications("")) != 0 {
+		t.Fatalf("application kept after MaxAge")
+	}
+}
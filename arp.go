@@ -0,0 +1,264 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  ARP / IPv6 Neighbor Discovery Binding Tracking
+// ****************************************************************************************************
+
+// XnfvBindingEventType names the kind of XnfvBindingEvent
+type XnfvBindingEventType string
+
+const (
+	// the IP moved to a new MAC and the old one went quiet
+	XnfvBindingChanged XnfvBindingEventType = "binding-changed"
+	// two MACs keep claiming the same IP, a likely spoofing attempt
+	XnfvBindingConflict XnfvBindingEventType = "binding-conflict"
+	// a MAC sent a burst of gratuitous ARP / unsolicited neighbor advertisements
+	XnfvGratuitousARPBurst XnfvBindingEventType = "gratuitous-arp-burst"
+)
+
+type XnfvBindingConfig struct {
+	// ConflictWindow: when the previous MAC claimed the IP within this
+	// window, a new MAC is a conflict rather than a legitimate change
+	ConflictWindow time.Duration
+	// GratuitousWindow and GratuitousBurst bound the estimated number of
+	// gratuitous announcements a MAC may send before it is reported
+	GratuitousWindow time.Duration
+	GratuitousBurst  float64
+	// MaxAge removes bindings that have not been refreshed for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvBindingConfig() XnfvBindingConfig {
+	return XnfvBindingConfig{
+		ConflictWindow:   5 * time.Minute,
+		GratuitousWindow: 10 * time.Second,
+		GratuitousBurst:  50,
+		MaxAge:           4 * time.Hour,
+	}
+}
+
+// XnfvBinding is one IP <-> MAC binding learned from ARP / NDP
+type XnfvBinding struct {
+	IP        string       `json:"ip"`
+	Mac       string       `json:"mac"`
+	VNI       uint32       `json:"vni"`
+	Port      XnfvHostPort `json:"port"`
+	FirstSeen time.Time    `json:"firstSeen"`
+	LastSeen  time.Time    `json:"lastSeen"`
+}
+
+// XnfvBindingEvent reports a binding change, a conflict or a gratuitous
+// ARP burst, tagged with the switch port the offending packet came from
+type XnfvBindingEvent struct {
+	Type        XnfvBindingEventType `json:"type"`
+	IP          string               `json:"ip,omitempty"`
+	Mac         string               `json:"mac"`
+	PreviousMac string               `json:"previousMac,omitempty"`
+	VNI         uint32               `json:"vni"`
+	Port        XnfvHostPort         `json:"port"`
+	Estimated   float64              `json:"estimated,omitempty"`
+	Time        time.Time            `json:"time"`
+}
+
+type xnfvBindingKey struct {
+	ip  string
+	vni uint32
+}
+
+type xnfvGratuitousCounter struct {
+	windowStart time.Time
+	estimated   float64
+	reported    bool
+}
+
+// XnfvBindingTable builds the IP <-> MAC bindings of the sampled ARP and
+// NDP packets. It is safe for concurrent use.
+type XnfvBindingTable struct {
+	config     XnfvBindingConfig
+	mutex      sync.RWMutex
+	bindings   map[xnfvBindingKey]*XnfvBinding
+	gratuitous map[string]*xnfvGratuitousCounter
+}
+
+func NewXnfvBindingTable(config XnfvBindingConfig) *XnfvBindingTable {
+	return &XnfvBindingTable{
+		config:     config,
+		bindings:   map[xnfvBindingKey]*XnfvBinding{},
+		gratuitous: map[string]*xnfvGratuitousCounter{},
+	}
+}
+
+// xnfvNeighborClaim is an "IP is at MAC" statement found in an ARP or
+// NDP packet
+type xnfvNeighborClaim struct {
+	ip         net.IP
+	mac        net.HardwareAddr
+	gratuitous bool
+}
+
+// neighborClaims extracts the bindings announced by the ARP / NDP
+// packet of a flow sample
+func neighborClaims(s XnfvFlowSample) []xnfvNeighborClaim {
+	if s.Header == nil {
+		return nil
+	}
+	var claims []xnfvNeighborClaim
+	for _, layer := range s.Header.Layers() {
+		switch l := layer.(type) {
+		case *layers.ARP:
+			if l.Protocol != layers.EthernetTypeIPv4 || len(l.SourceProtAddress) != 4 || len(l.SourceHwAddress) == 0 {
+				continue
+			}
+			senderIP := net.IP(l.SourceProtAddress)
+			if senderIP.IsUnspecified() {
+				// ARP probe (RFC 5227), the sender does not own an address yet
+				continue
+			}
+			claims = append(claims, xnfvNeighborClaim{
+				ip:         senderIP,
+				mac:        net.HardwareAddr(l.SourceHwAddress),
+				gratuitous: net.IP(l.DstProtAddress).Equal(senderIP),
+			})
+		case *layers.ICMPv6NeighborSolicitation:
+			if mac := ndpLinkLayerOption(l.Options, layers.ICMPv6OptSourceAddress); mac != nil && s.SrcIP != nil && !s.SrcIP.IsUnspecified() {
+				claims = append(claims, xnfvNeighborClaim{ip: s.SrcIP, mac: mac})
+			}
+		case *layers.ICMPv6NeighborAdvertisement:
+			mac := ndpLinkLayerOption(l.Options, layers.ICMPv6OptTargetAddress)
+			if mac == nil {
+				mac = s.SrcMac
+			}
+			// a header without the Ethernet frame does not tell the MAC
+			if len(mac) == 0 {
+				continue
+			}
+			claims = append(claims, xnfvNeighborClaim{ip: l.TargetAddress, mac: mac, gratuitous: !l.Solicited()})
+		}
+	}
+	return claims
+}
+
+func ndpLinkLayerOption(options layers.ICMPv6Options, optionType layers.ICMPv6Opt) net.HardwareAddr {
+	for _, option := range options {
+		if option.Type == optionType && len(option.Data) >= 6 {
+			return net.HardwareAddr(option.Data[:6])
+		}
+	}
+	return nil
+}
+
+// Observe learns the bindings announced by a flow sample and returns the
+// alerts they raise
+func (t *XnfvBindingTable) Observe(s XnfvFlowSample) []XnfvBindingEvent {
+	claims := neighborClaims(s)
+	if len(claims) == 0 {
+		return nil
+	}
+	port := XnfvHostPort{s.SwitchDataPathString(), s.PortName, s.OfPort, s.InputInterface}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var events []XnfvBindingEvent
+	for _, claim := range claims {
+		ip, mac := claim.ip.String(), claim.mac.String()
+		key := xnfvBindingKey{ip, s.VNI}
+		binding, ok := t.bindings[key]
+		switch {
+		case !ok:
+			t.bindings[key] = &XnfvBinding{ip, mac, s.VNI, port, s.Timestamp, s.Timestamp}
+		case binding.Mac == mac:
+			binding.LastSeen, binding.Port = s.Timestamp, port
+		default:
+			eventType := XnfvBindingChanged
+			if s.Timestamp.Sub(binding.LastSeen) < t.config.ConflictWindow {
+				eventType = XnfvBindingConflict
+			}
+			events = append(events, XnfvBindingEvent{
+				Type:        eventType,
+				IP:          ip,
+				Mac:         mac,
+				PreviousMac: binding.Mac,
+				VNI:         s.VNI,
+				Port:        port,
+				Time:        s.Timestamp,
+			})
+			t.bindings[key] = &XnfvBinding{ip, mac, s.VNI, port, s.Timestamp, s.Timestamp}
+		}
+
+		if claim.gratuitous {
+			if event := t.countGratuitous(mac, s, port); event != nil {
+				events = append(events, *event)
+			}
+		}
+	}
+	return events
+}
+
+// countGratuitous accounts a gratuitous announcement, scaled by the
+// sampling rate, and reports the burst once per window
+func (t *XnfvBindingTable) countGratuitous(mac string, s XnfvFlowSample, port XnfvHostPort) *XnfvBindingEvent {
+	counter, ok := t.gratuitous[mac]
+	if !ok || s.Timestamp.Sub(counter.windowStart) >= t.config.GratuitousWindow {
+		counter = &xnfvGratuitousCounter{windowStart: s.Timestamp}
+		t.gratuitous[mac] = counter
+	}
+	counter.estimated += s.EstimatedPackets()
+	if counter.reported || counter.estimated < t.config.GratuitousBurst {
+		return nil
+	}
+	counter.reported = true
+	return &XnfvBindingEvent{
+		Type:      XnfvGratuitousARPBurst,
+		Mac:       mac,
+		VNI:       s.VNI,
+		Port:      port,
+		Estimated: counter.estimated,
+		Time:      s.Timestamp,
+	}
+}
+
+// AgeOut removes bindings and burst counters that are no longer refreshed
+func (t *XnfvBindingTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, binding := range t.bindings {
+		if now.Sub(binding.LastSeen) > t.config.MaxAge {
+			delete(t.bindings, key)
+		}
+	}
+	for mac, counter := range t.gratuitous {
+		if now.Sub(counter.windowStart) >= t.config.GratuitousWindow {
+			delete(t.gratuitous, mac)
+		}
+	}
+}
+
+// Bindings returns every binding, optionally restricted to one IP or MAC
+func (t *XnfvBindingTable) Bindings(ip string, mac string) []XnfvBinding {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var bindings []XnfvBinding
+	for _, binding := range t.bindings {
+		if (ip == "" || binding.IP == ip) && (mac == "" || binding.Mac == mac) {
+			bindings = append(bindings, *binding)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].IP < bindings[j].IP })
+	return bindings
+}
+
+// ServeHTTP answers GET /bindings[?ip=|mac=] with the bindings as JSON
+func (t *XnfvBindingTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.Bindings(r.URL.Query().Get("ip"), r.URL.Query().Get("mac")))
+}
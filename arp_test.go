@@ -0,0 +1,178 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// xnfvTestARPSample is a sampled ARP reply of mac claiming ip, sent to
+// target. It is a gratuitous announcement when target is ip. The claim is
+// read from the ARP packet, the frame is always sent by 02:00:00:00:00:01.
+func xnfvTestARPSample(t *testing.T, at time.Duration, ip string, mac net.HardwareAddr, target string, samplingRate uint32) XnfvFlowSample {
+	ethernet := &layers.Ethernet{SrcMAC: net.HardwareAddr{2, 0, 0, 0, 0, 1}, DstMAC: layers.EthernetBroadcast, EthernetType: layers.EthernetTypeARP}
+	arp := &layers.ARP{
+		AddrType:          layers.LinkTypeEthernet,
+		Protocol:          layers.EthernetTypeIPv4,
+		HwAddressSize:     uint8(len(mac)),
+		ProtAddressSize:   4,
+		Operation:         layers.ARPReply,
+		SourceHwAddress:   mac,
+		SourceProtAddress: net.ParseIP(ip).To4(),
+		DstHwAddress:      layers.EthernetBroadcast[:len(mac)],
+		DstProtAddress:    net.ParseIP(target).To4(),
+	}
+	buffer := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{}, ethernet, arp); err != nil {
+		t.Fatal(err)
+	}
+	s := newXnfvFlowSample(gopacket.NewPacket(buffer.Bytes(), layers.LayerTypeEthernet, gopacket.Default), samplingRate, 1, 2, 0)
+	s.Timestamp = xnfvTestEpoch.Add(at)
+	return s
+}
+
+// xnfvTestNeighborAdvertisementSample is a sampled neighbor advertisement
+// for ip. The MAC is carried in the target link-layer address option when
+// option is set, otherwise only by the Ethernet frame; without the frame
+// the header starts at the IPv6 layer.
+func xnfvTestNeighborAdvertisementSample(t *testing.T, ip string, mac net.HardwareAddr, option bool, frame bool) XnfvFlowSample {
+	ipv6 := &layers.IPv6{Version: 6, HopLimit: 255, NextHeader: layers.IPProtocolICMPv6, SrcIP: net.ParseIP(ip), DstIP: net.ParseIP("ff02::1")}
+	icmp := &layers.ICMPv6{TypeCode: layers.CreateICMPv6TypeCode(layers.ICMPv6TypeNeighborAdvertisement, 0)}
+	icmp.SetNetworkLayerForChecksum(ipv6)
+	advertisement := &layers.ICMPv6NeighborAdvertisement{TargetAddress: net.ParseIP(ip), Flags: 0x20}
+	if option {
+		advertisement.Options = layers.ICMPv6Options{{Type: layers.ICMPv6OptTargetAddress, Data: mac}}
+	}
+	serialized := []gopacket.SerializableLayer{ipv6, icmp, advertisement}
+	first := gopacket.LayerType(layers.LayerTypeIPv6)
+	if frame {
+		ethernet := &layers.Ethernet{SrcMAC: mac, DstMAC: net.HardwareAddr{0x33, 0x33, 0, 0, 0, 1}, EthernetType: layers.EthernetTypeIPv6}
+		serialized, first = append([]gopacket.SerializableLayer{ethernet}, serialized...), layers.LayerTypeEthernet
+	}
+	buffer := gopacket.NewSerializeBuffer()
+	options := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buffer, options, serialized...); err != nil {
+		t.Fatal(err)
+	}
+	s := newXnfvFlowSample(gopacket.NewPacket(buffer.Bytes(), first, gopacket.Default), 1, 1, 2, 0)
+	s.Timestamp = xnfvTestEpoch
+	return s
+}
+
+var (
+	xnfvTestMacA = net.HardwareAddr{2, 0, 0, 0, 0, 0xa}
+	xnfvTestMacB = net.HardwareAddr{2, 0, 0, 0, 0, 0xb}
+)
+
+func TestXnfvBindingTableSpoofing(t *testing.T) {
+	steps := []struct {
+		name  string
+		s     XnfvFlowSample
+		event XnfvBindingEventType
+		mac   net.HardwareAddr
+	}{
+		{"first claim", xnfvTestARPSample(t, 0, "10.0.0.1", xnfvTestMacA, "10.0.0.2", 1), "", xnfvTestMacA},
+		{"refresh", xnfvTestARPSample(t, time.Minute, "10.0.0.1", xnfvTestMacA, "10.0.0.2", 1), "", xnfvTestMacA},
+		// another MAC while the owner is still talking
+		{"spoofing", xnfvTestARPSample(t, 2*time.Minute, "10.0.0.1", xnfvTestMacB, "10.0.0.2", 1), XnfvBindingConflict, xnfvTestMacB},
+		{"spoofing back", xnfvTestARPSample(t, 3*time.Minute, "10.0.0.1", xnfvTestMacA, "10.0.0.2", 1), XnfvBindingConflict, xnfvTestMacA},
+		// the owner went quiet for longer than the conflict window
+		{"change", xnfvTestARPSample(t, 10*time.Minute, "10.0.0.1", xnfvTestMacB, "10.0.0.2", 1), XnfvBindingChanged, xnfvTestMacB},
+	}
+	table := NewXnfvBindingTable(DefaultXnfvBindingConfig())
+	var previous net.HardwareAddr
+	for _, step := range steps {
+		events := table.Observe(step.s)
+		if step.event == "" {
+			if len(events) != 0 {
+				t.Fatalf("%s: events %+v", step.name, events)
+			}
+		} else {
+			if len(events) != 1 || events[0].Type != step.event || events[0].IP != "10.0.0.1" ||
+				events[0].Mac != step.mac.String() || events[0].PreviousMac != previous.String() || events[0].Port.InputInterface != 1 {
+				t.Fatalf("%s: events %+v, want %s", step.name, events, step.event)
+			}
+		}
+		bindings := table.Bindings("10.0.0.1", "")
+		if len(bindings) != 1 || bindings[0].Mac != step.mac.String() || !bindings[0].LastSeen.Equal(step.s.Timestamp) {
+			t.Fatalf("%s: bindings %+v", step.name, bindings)
+		}
+		previous = step.mac
+	}
+	if !table.Bindings("", "")[0].FirstSeen.Equal(xnfvTestEpoch.Add(10 * time.Minute)) {
+		t.Fatalf("a change starts a new binding")
+	}
+
+	table.AgeOut(xnfvTestEpoch.Add(10*time.Minute + 4*time.Hour + time.Second))
+	if bindings := table.Bindings("", ""); len(bindings) != 0 {
+		t.Fatalf("bindings after AgeOut %+v", bindings)
+	}
+}
+
+func TestXnfvBindingTableGratuitousBurst(t *testing.T) {
+	table := NewXnfvBindingTable(DefaultXnfvBindingConfig())
+	// 4 sampled announcements at 1:10 stand for 40, below the burst of 50
+	for i := 0; i < 4; i++ {
+		if events := table.Observe(xnfvTestARPSample(t, time.Duration(i)*time.Second, "10.0.0.1", xnfvTestMacA, "10.0.0.1", 10)); len(events) != 0 {
+			t.Fatalf("announcement %d: events %+v", i, events)
+		}
+	}
+	events := table.Observe(xnfvTestARPSample(t, 4*time.Second, "10.0.0.1", xnfvTestMacA, "10.0.0.1", 10))
+	if len(events) != 1 || events[0].Type != XnfvGratuitousARPBurst || events[0].Mac != xnfvTestMacA.String() || events[0].Estimated != 50 {
+		t.Fatalf("events %+v", events)
+	}
+	// once per window
+	if events := table.Observe(xnfvTestARPSample(t, 5*time.Second, "10.0.0.1", xnfvTestMacA, "10.0.0.1", 10)); len(events) != 0 {
+		t.Fatalf("reported twice: %+v", events)
+	}
+	// a new window counts from zero
+	if events := table.Observe(xnfvTestARPSample(t, 10*time.Second, "10.0.0.1", xnfvTestMacA, "10.0.0.1", 10)); len(events) != 0 {
+		t.Fatalf("new window: %+v", events)
+	}
+	// replies to another host are not gratuitous
+	table = NewXnfvBindingTable(DefaultXnfvBindingConfig())
+	for i := 0; i < 10; i++ {
+		if events := table.Observe(xnfvTestARPSample(t, 0, "10.0.0.1", xnfvTestMacA, "10.0.0.2", 10)); len(events) != 0 {
+			t.Fatalf("reply %d: events %+v", i, events)
+		}
+	}
+}
+
+func TestXnfvNeighborClaims(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          XnfvFlowSample
+		mac        string
+		gratuitous bool
+	}{
+		{"arp reply", xnfvTestARPSample(t, 0, "10.0.0.1", xnfvTestMacA, "10.0.0.2", 1), xnfvTestMacA.String(), false},
+		{"gratuitous arp", xnfvTestARPSample(t, 0, "10.0.0.1", xnfvTestMacA, "10.0.0.1", 1), xnfvTestMacA.String(), true},
+		{"arp probe", xnfvTestARPSample(t, 0, "0.0.0.0", xnfvTestMacA, "10.0.0.1", 1), "", false},
+		{"arp without hardware address", xnfvTestARPSample(t, 0, "10.0.0.1", net.HardwareAddr{}, "10.0.0.2", 1), "", false},
+		{"advertisement option", xnfvTestNeighborAdvertisementSample(t, "2001:db8::1", xnfvTestMacA, true, false), xnfvTestMacA.String(), true},
+		{"advertisement frame", xnfvTestNeighborAdvertisementSample(t, "2001:db8::1", xnfvTestMacB, false, true), xnfvTestMacB.String(), true},
+		{"advertisement without mac", xnfvTestNeighborAdvertisementSample(t, "2001:db8::1", xnfvTestMacA, false, false), "", false},
+	}
+	for _, test := range tests {
+		claims := neighborClaims(test.s)
+		if test.mac == "" {
+			if len(claims) != 0 {
+				t.Errorf("%s: claims %+v", test.name, claims)
+			}
+			continue
+		}
+		if len(claims) != 1 || claims[0].mac.String() != test.mac || claims[0].gratuitous != test.gratuitous {
+			t.Errorf("%s: claims %+v, want %s gratuitous %v", test.name, claims, test.mac, test.gratuitous)
+		}
+	}
+
+	// a claim without a MAC would bind the IP to the empty MAC
+	table := NewXnfvBindingTable(DefaultXnfvBindingConfig())
+	table.Observe(xnfvTestNeighborAdvertisementSample(t, "2001:db8::1", xnfvTestMacA, false, false))
+	if bindings := table.Bindings("", ""); len(bindings) != 0 {
+		t.Fatalf("bindings %+v", bindings)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build cgo
+// +build cgo
+
+package main
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcap"
+)
+
+// ****************************************************************************************************
+//  Packet Capture
+// ****************************************************************************************************
+
+// The captures go through libpcap, which needs cgo. Without it the
+// collector builds, and the tests run, but it cannot capture.
+
+// openXnfvLiveCapture captures the sFlow datagrams (UDP port 6343) sent to
+// a network device
+func openXnfvLiveCapture(device string) (*gopacket.PacketSource, error) {
+	handle, err := pcap.OpenLive(device, 1600, true, pcap.BlockForever)
+	if err != nil {
+		return nil, err
+	}
+	if err := handle.SetBPFFilter("udp and port 6343"); err != nil {
+		handle.Close()
+		return nil, err
+	}
+	return gopacket.NewPacketSource(handle, handle.LinkType()), nil
+}
+
+// openXnfvCaptureFile reads the packets of a pcap or pcapng file, the
+// returned function closes it
+func openXnfvCaptureFile(path string) (*gopacket.PacketSource, func(), error) {
+	handle, err := pcap.OpenOffline(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gopacket.NewPacketSource(handle, handle.LinkType()), handle.Close, nil
+}
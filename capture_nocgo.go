@@ -0,0 +1,20 @@
+//go:build !cgo
+// +build !cgo
+
+package main
+
+import (
+	"errors"
+
+	"github.com/google/gopacket"
+)
+
+var errXnfvNoCapture = errors.New("packet capture needs libpcap, build with cgo enabled")
+
+func openXnfvLiveCapture(device string) (*gopacket.PacketSource, error) {
+	return nil, errXnfvNoCapture
+}
+
+func openXnfvCaptureFile(path string) (*gopacket.PacketSource, func(), error) {
+	return nil, nil, errXnfvNoCapture
+}
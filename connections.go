@@ -0,0 +1,142 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ****************************************************************************************************
+//  TCP Connection Performance
+// ****************************************************************************************************
+
+type XnfvConnectionTableConfig struct {
+	// MaxAge removes connections that have not been sampled for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvConnectionTableConfig() XnfvConnectionTableConfig {
+	return XnfvConnectionTableConfig{MaxAge: 10 * time.Minute}
+}
+
+// XnfvConnectionKey identifies a connection from the point of view of the
+// host that sampled it
+type XnfvConnectionKey struct {
+	Protocol   uint32 `json:"protocol"`
+	LocalIP    string `json:"localIP"`
+	LocalPort  uint32 `json:"localPort"`
+	RemoteIP   string `json:"remoteIP"`
+	RemotePort uint32 `json:"remotePort"`
+}
+
+// XnfvConnection is the TCP state of a connection at its last sampled
+// packet, with the retransmission rate derived from the two last samples
+type XnfvConnection struct {
+	XnfvConnectionKey
+	Samples  uint64  `json:"samples"`
+	RTTMs    float64 `json:"rttMs"`
+	RTTVarMs float64 `json:"rttVarMs"`
+	MinRTTMs float64 `json:"minRttMs"`
+	SndMSS   uint32  `json:"sndMss"`
+	RcvMSS   uint32  `json:"rcvMss"`
+	PMTU     uint32  `json:"pmtu"`
+	SndCwnd  uint32  `json:"sndCwnd"`
+	Unacked  uint32  `json:"unacked"`
+	// Lost is the packets currently considered lost (tcpi_lost), a gauge
+	Lost       uint32 `json:"lost"`
+	Retrans    uint32 `json:"retrans"`
+	Reordering uint32 `json:"reordering"`
+	// per second, 0 for the interval in which the counter was reset
+	RetransRate float64   `json:"retransRate"`
+	LastUpdate  time.Time `json:"lastUpdate"`
+}
+
+// XnfvConnectionTable keeps the TCP info of the connections of the hosts
+// running hsflowd. It is safe for concurrent use.
+type XnfvConnectionTable struct {
+	config      XnfvConnectionTableConfig
+	mutex       sync.RWMutex
+	connections map[XnfvConnectionKey]*XnfvConnection
+}
+
+func NewXnfvConnectionTable(config XnfvConnectionTableConfig) *XnfvConnectionTable {
+	return &XnfvConnectionTable{config: config, connections: map[XnfvConnectionKey]*XnfvConnection{}}
+}
+
+// Observe updates the connection of a flow sample that carries the
+// extended socket and TCP info records
+func (t *XnfvConnectionTable) Observe(flowSample XnfvFlowSample) {
+	if flowSample.TCPInfo == nil || flowSample.SocketLocalIP == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := XnfvConnectionKey{
+		Protocol:   flowSample.SocketProtocol,
+		LocalIP:    flowSample.SocketLocalIP.String(),
+		LocalPort:  flowSample.SocketLocalPort,
+		RemoteIP:   ipString(flowSample.SocketRemoteIP),
+		RemotePort: flowSample.SocketRemotePort,
+	}
+	tcpInfo := flowSample.TCPInfo
+	connection, ok := t.connections[key]
+	if !ok {
+		connection = &XnfvConnection{XnfvConnectionKey: key}
+		t.connections[key] = connection
+	} else if interval := flowSample.Timestamp.Sub(connection.LastUpdate).Seconds(); interval > 0 {
+		var deltas xnfvCounterDeltas
+		retrans := deltas.delta(connection.Retrans, tcpInfo.Retrans)
+		connection.RetransRate = 0
+		if !deltas.reset {
+			connection.RetransRate = float64(retrans) / interval
+		}
+	}
+	connection.Samples++
+	connection.RTTMs = float64(tcpInfo.RTT) / 1000
+	connection.RTTVarMs = float64(tcpInfo.RTTVar) / 1000
+	connection.MinRTTMs = float64(tcpInfo.MinRTT) / 1000
+	connection.SndMSS, connection.RcvMSS, connection.PMTU = tcpInfo.SndMSS, tcpInfo.RcvMSS, tcpInfo.PMTU
+	connection.SndCwnd, connection.Unacked = tcpInfo.SndCwnd, tcpInfo.Unacked
+	connection.Lost, connection.Retrans, connection.Reordering = tcpInfo.Lost, tcpInfo.Retrans, tcpInfo.Reordering
+	connection.LastUpdate = flowSample.Timestamp
+}
+
+// AgeOut removes the connections not sampled since MaxAge before now
+func (t *XnfvConnectionTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, connection := range t.connections {
+		if now.Sub(connection.LastUpdate) > t.config.MaxAge {
+			delete(t.connections, key)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Connection Query API
+// ****************************************************************************************************
+
+// Connections returns the connections, optionally only those with a local
+// or remote address ip, the slowest first
+func (t *XnfvConnectionTable) Connections(ip string) []XnfvConnection {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	connections := []XnfvConnection{}
+	for key, connection := range t.connections {
+		if ip != "" && key.LocalIP != ip && key.RemoteIP != ip {
+			continue
+		}
+		connections = append(connections, *connection)
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].RTTMs > connections[j].RTTMs
+	})
+	return connections
+}
+
+// ServeHTTP answers GET /connections[?ip=] with the connections as JSON
+func (t *XnfvConnectionTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.Connections(r.URL.Query().Get("ip")))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// xnfvTestTCPInfoSample is a sampled packet of the connection
+// 10.0.0.1:40000 -> 10.0.0.2:443 carrying its TCP info
+func xnfvTestTCPInfoSample(at time.Duration, rtt uint32, lost uint32, retrans uint32) XnfvFlowSample {
+	return XnfvFlowSample{
+		Timestamp:        xnfvTestEpoch.Add(at),
+		SocketProtocol:   6,
+		SocketLocalIP:    net.ParseIP("10.0.0.1"),
+		SocketLocalPort:  40000,
+		SocketRemoteIP:   net.ParseIP("10.0.0.2"),
+		SocketRemotePort: 443,
+		TCPInfo:          &sflow.SFlowExtendedTCPInfoFlowRecord{RTT: rtt, Lost: lost, Retrans: retrans, SndMSS: 1448},
+	}
+}
+
+func TestXnfvConnectionTableRates(t *testing.T) {
+	steps := []struct {
+		sample      XnfvFlowSample
+		retransRate float64
+	}{
+		{xnfvTestTCPInfoSample(0, 20000, 0, 10), 0},
+		{xnfvTestTCPInfoSample(10*time.Second, 25000, 5, 60), 5},
+		// the lost packets were recovered, lost is a gauge
+		{xnfvTestTCPInfoSample(20*time.Second, 21000, 0, 80), 2},
+		{xnfvTestTCPInfoSample(40*time.Second, 30000, 2, 100), 1},
+		// the retransmissions were reset, the interval is skipped
+		{xnfvTestTCPInfoSample(50*time.Second, 30000, 2, 3), 0},
+		{xnfvTestTCPInfoSample(60*time.Second, 30000, 2, 13), 1},
+	}
+	table := NewXnfvConnectionTable(DefaultXnfvConnectionTableConfig())
+	for i, step := range steps {
+		table.Observe(step.sample)
+		connections := table.Connections("10.0.0.2")
+		if len(connections) != 1 {
+			t.Fatalf("step %d: connections %+v", i, connections)
+		}
+		connection := connections[0]
+		if connection.RetransRate != step.retransRate || connection.Lost != step.sample.TCPInfo.Lost {
+			t.Fatalf("step %d: retrans %v lost %v, want %v %v", i, connection.RetransRate, connection.Lost, step.retransRate, step.sample.TCPInfo.Lost)
+		}
+		if connection.Samples != uint64(i+1) || connection.RTTMs != float64(step.sample.TCPInfo.RTT)/1000 || connection.SndMSS != 1448 {
+			t.Fatalf("step %d: connection %+v", i, connection)
+		}
+	}
+	if key := table.Connections("")[0].XnfvConnectionKey; key != (XnfvConnectionKey{6, "10.0.0.1", 40000, "10.0.0.2", 443}) {
+		t.Fatalf("key %+v", key)
+	}
+}
+
+func TestXnfvConnectionTableQuery(t *testing.T) {
+	table := NewXnfvConnectionTable(DefaultXnfvConnectionTableConfig())
+	// samples without the socket or TCP info are ignored
+	table.Observe(XnfvFlowSample{Timestamp: xnfvTestEpoch})
+	table.Observe(xnfvTestTCPInfoSample(0, 20000, 0, 0))
+	slow := xnfvTestTCPInfoSample(time.Minute, 90000, 0, 0)
+	slow.SocketRemoteIP = net.ParseIP("10.0.0.3")
+	table.Observe(slow)
+
+	connections := table.Connections("")
+	if len(connections) != 2 || connections[0].RemoteIP != "10.0.0.3" || connections[1].RemoteIP != "10.0.0.2" {
+		t.Fatalf("connections %+v, want the slowest first", connections)
+	}
+	if len(table.Connections("10.0.0.1")) != 2 || len(table.Connections("10.0.0.3")) != 1 || len(table.Connections("10.0.0.4")) != 0 {
+		t.Fatalf("query by address")
+	}
+
+	table.AgeOut(xnfvTestEpoch.Add(10*time.Minute + time.Second))
+	if connections := table.Connections(""); len(connections) != 1 || connections[0].RemoteIP != "10.0.0.3" {
+		t.Fatalf("connections after AgeOut %+v", connections)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+// ****************************************************************************************************
+//  Counter Deltas
+// ****************************************************************************************************
+
+// xnfvCounterDeltas computes how much the counters of a record grew since
+// the previous record of the same source. A counter that went down either
+// wrapped, the growth is then the unsigned difference, or was reset when
+// the agent (or OVS, or the application) restarted: the growth over the
+// interval is unknown and reset is set, the caller skips the interval and
+// the current record becomes the new reference.
+type xnfvCounterDeltas struct {
+	reset bool
+}
+
+// A 32 bit counter that went down wrapped when the unsigned difference is
+// below 2^31, that is when it went down by more than half of its range.
+// A smaller drop would mean a growth of 2^31 or more within a polling
+// interval, the counter was reset.
+const xnfvCounterMaxWrap = 1 << 31
+
+func (d *xnfvCounterDeltas) delta(previous uint32, current uint32) uint32 {
+	delta := current - previous
+	if current < previous && delta >= xnfvCounterMaxWrap {
+		d.reset = true
+		return 0
+	}
+	return delta
+}
+
+// delta64 is delta for the 64 bit counters, they do not wrap
+func (d *xnfvCounterDeltas) delta64(previous uint64, current uint64) uint64 {
+	if current < previous {
+		d.reset = true
+		return 0
+	}
+	return current - previous
+}
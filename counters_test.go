@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestXnfvCounterDeltas(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous uint32
+		current  uint32
+		delta    uint32
+		reset    bool
+	}{
+		{"growth", 1000, 1500, 500, false},
+		{"unchanged", 1000, 1000, 0, false},
+		{"wrap", 0xFFFFFF00, 0x100, 0x200, false},
+		{"restart", 1000000, 5, 0, true},
+		{"restart from a high count", 0x50000000, 10, 0, true},
+		// a drop of exactly half of the range is a reset, a larger one a wrap
+		{"half of the range", 0x80000000, 0, 0, true},
+		{"wrap of almost half of the range", 0x80000001, 0, 0x7FFFFFFF, false},
+	}
+	for _, test := range tests {
+		var deltas xnfvCounterDeltas
+		if delta := deltas.delta(test.previous, test.current); delta != test.delta || deltas.reset != test.reset {
+			t.Errorf("%s: delta %d reset %v, want %d %v", test.name, delta, deltas.reset, test.delta, test.reset)
+		}
+	}
+}
+
+func TestXnfvCounterDeltas64(t *testing.T) {
+	var deltas xnfvCounterDeltas
+	if delta := deltas.delta64(1<<40, 1<<40+7); delta != 7 || deltas.reset {
+		t.Errorf("growth: delta %d reset %v", delta, deltas.reset)
+	}
+	if delta := deltas.delta64(1<<40, 3); delta != 0 || !deltas.reset {
+		t.Errorf("restart: delta %d reset %v", delta, deltas.reset)
+	}
+	// a reset counter resets the record
+	if delta := deltas.delta(1, 2); delta != 1 || !deltas.reset {
+		t.Errorf("after the restart: delta %d reset %v", delta, deltas.reset)
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Volumetric DDoS / SYN Flood Detection
+// ****************************************************************************************************
+
+// XnfvAttackType names the kind of attack an XnfvAttackEvent reports
+type XnfvAttackType string
+
+const (
+	XnfvAttackVolumetric    XnfvAttackType = "volumetric"
+	XnfvAttackSynFlood      XnfvAttackType = "syn-flood"
+	XnfvAttackUDPReflection XnfvAttackType = "udp-reflection"
+	XnfvAttackICMPFlood     XnfvAttackType = "icmp-flood"
+)
+
+// XnfvDDoSConfig holds the detection thresholds. Rates are estimated
+// from sampled packets scaled by the sampling rate and are evaluated per
+// destination IP once per Window.
+type XnfvDDoSConfig struct {
+	Window time.Duration
+
+	// Static thresholds, a victim is only reported above these rates
+	VolumetricPps float64
+	VolumetricBps float64
+	SynPps        float64
+	ReflectionPps float64
+	ICMPPps       float64
+
+	// SynAckRatio is the minimum SYN / SYN-ACK ratio of a SYN flood
+	SynAckRatio float64
+
+	// BaselineAlpha is the EWMA weight used to learn each victim's normal
+	// packet rate, and BaselineFactor how far above that baseline the
+	// current rate must be. A BaselineFactor of 0 disables the baseline.
+	BaselineAlpha  float64
+	BaselineFactor float64
+
+	// BaselineMaxAge forgets the baseline of a destination that has not
+	// been sampled for that long
+	BaselineMaxAge time.Duration
+
+	// TopSources is the number of sources and ingress ports listed in an event
+	TopSources int
+
+	// AmplificationPorts lists well-known UDP source ports of reflection attacks
+	AmplificationPorts map[uint16]string
+}
+
+// DefaultXnfvDDoSConfig returns thresholds suitable for a small NFV pod
+func DefaultXnfvDDoSConfig() XnfvDDoSConfig {
+	return XnfvDDoSConfig{
+		Window:         10 * time.Second,
+		VolumetricPps:  100000,
+		VolumetricBps:  1e9,
+		SynPps:         10000,
+		ReflectionPps:  10000,
+		ICMPPps:        10000,
+		SynAckRatio:    3,
+		BaselineAlpha:  0.2,
+		BaselineFactor: 4,
+		BaselineMaxAge: time.Hour,
+		TopSources:     5,
+		AmplificationPorts: map[uint16]string{
+			19:    "chargen",
+			53:    "dns",
+			111:   "portmap",
+			123:   "ntp",
+			137:   "netbios",
+			161:   "snmp",
+			389:   "cldap",
+			1900:  "ssdp",
+			3702:  "ws-discovery",
+			5353:  "mdns",
+			11211: "memcached",
+		},
+	}
+}
+
+// XnfvAttackSource is one of the top talkers towards a victim
+type XnfvAttackSource struct {
+	IP           string  `json:"ip"`
+	EstimatedPps float64 `json:"estimatedPps"`
+}
+
+// XnfvAttackIngress is a switch port the attack traffic entered on
+type XnfvAttackIngress struct {
+	SwitchDataPath string  `json:"switchDataPath"`
+	PortName       string  `json:"portName"`
+	OfPort         uint32  `json:"ofPort"`
+	InputInterface uint32  `json:"inputInterface"`
+	EstimatedPps   float64 `json:"estimatedPps"`
+}
+
+// XnfvAttackEvent is emitted once per window for each detected attack.
+// SynAckRatio is only set for a SYN flood the victim answered some SYNs of.
+type XnfvAttackEvent struct {
+	Type         XnfvAttackType      `json:"type"`
+	Victim       string              `json:"victim"`
+	WindowStart  time.Time           `json:"windowStart"`
+	WindowEnd    time.Time           `json:"windowEnd"`
+	EstimatedPps float64             `json:"estimatedPps"`
+	EstimatedBps float64             `json:"estimatedBps"`
+	BaselinePps  float64             `json:"baselinePps"`
+	SynAckRatio  *float64            `json:"synAckRatio,omitempty"`
+	TopSources   []XnfvAttackSource  `json:"topSources"`
+	Ingress      []XnfvAttackIngress `json:"ingress"`
+}
+
+type xnfvIngressKey struct {
+	dataPath       string
+	portName       string
+	ofPort         uint32
+	inputInterface uint32
+}
+
+// xnfvVictimStats accumulates the estimated traffic towards one
+// destination IP during the current window.
+type xnfvVictimStats struct {
+	packets    float64
+	bytes      float64
+	syn        float64
+	synAck     float64
+	reflection float64
+	icmp       float64
+	sources    map[string]float64
+	ingress    map[xnfvIngressKey]float64
+}
+
+func newXnfvVictimStats() *xnfvVictimStats {
+	return &xnfvVictimStats{
+		sources: map[string]float64{},
+		ingress: map[xnfvIngressKey]float64{},
+	}
+}
+
+// xnfvBaseline is the learned packet rate of a destination
+type xnfvBaseline struct {
+	pps      float64
+	lastSeen time.Time
+}
+
+// XnfvDDoSDetector estimates per-destination traffic from flow samples
+// and reports volumetric, SYN, UDP reflection and ICMP floods.
+type XnfvDDoSDetector struct {
+	config      XnfvDDoSConfig
+	windowStart time.Time
+	victims     map[string]*xnfvVictimStats
+	baselines   map[string]*xnfvBaseline
+}
+
+func NewXnfvDDoSDetector(config XnfvDDoSConfig) *XnfvDDoSDetector {
+	return &XnfvDDoSDetector{
+		config:    config,
+		victims:   map[string]*xnfvVictimStats{},
+		baselines: map[string]*xnfvBaseline{},
+	}
+}
+
+func (d *XnfvDDoSDetector) victim(ip string) *xnfvVictimStats {
+	v, ok := d.victims[ip]
+	if !ok {
+		v = newXnfvVictimStats()
+		d.victims[ip] = v
+	}
+	return v
+}
+
+// Observe accounts one flow sample. When the sample falls after the end of
+// the current window the window is evaluated first and its events returned,
+// the sample then starts the next window.
+func (d *XnfvDDoSDetector) Observe(s XnfvFlowSample) []XnfvAttackEvent {
+	var events []XnfvAttackEvent
+	if d.windowStart.IsZero() {
+		d.windowStart = s.Timestamp
+	} else if s.Timestamp.Sub(d.windowStart) >= d.config.Window {
+		// the rates are those of the window, not of the quiet time after it
+		events = d.Flush(d.windowStart.Add(d.config.Window))
+		d.windowStart = s.Timestamp
+	}
+	if s.DstIP == nil {
+		return events
+	}
+
+	pkts := s.EstimatedPackets()
+	v := d.victim(s.DstIP.String())
+	v.packets += pkts
+	v.bytes += s.EstimatedBytes()
+	if s.SrcIP != nil {
+		v.sources[s.SrcIP.String()] += pkts
+	}
+	v.ingress[xnfvIngressKey{s.SwitchDataPathString(), s.PortName, s.OfPort, s.InputInterface}] += pkts
+
+	switch s.IPProtocol {
+	case layers.IPProtocolTCP:
+		if s.TCPFlags&xnfvTCPFlagSYN != 0 {
+			if s.TCPFlags&xnfvTCPFlagACK != 0 {
+				// a SYN-ACK is the victim answering, account it to the sender
+				if s.SrcIP != nil {
+					d.victim(s.SrcIP.String()).synAck += pkts
+				}
+			} else {
+				v.syn += pkts
+			}
+		}
+	case layers.IPProtocolUDP:
+		if _, ok := d.config.AmplificationPorts[s.SrcPort]; ok {
+			v.reflection += pkts
+		}
+	case layers.IPProtocolICMPv4, layers.IPProtocolICMPv6:
+		v.icmp += pkts
+	}
+	return events
+}
+
+// Tick closes the current window once it is Window long, so that the
+// last samples before a quiet period are evaluated without waiting for
+// the next one. It is called on a timer.
+func (d *XnfvDDoSDetector) Tick(now time.Time) []XnfvAttackEvent {
+	if d.windowStart.IsZero() || now.Sub(d.windowStart) < d.config.Window {
+		return nil
+	}
+	return d.Flush(now)
+}
+
+// Flush evaluates the current window, returns the detected attacks and
+// starts a new window at now.
+func (d *XnfvDDoSDetector) Flush(now time.Time) []XnfvAttackEvent {
+	var events []XnfvAttackEvent
+	seconds := now.Sub(d.windowStart).Seconds()
+	if seconds <= 0 {
+		seconds = d.config.Window.Seconds()
+	}
+
+	for ip, v := range d.victims {
+		detected := len(events)
+		pps := v.packets / seconds
+		bps := v.bytes * 8 / seconds
+		var baseline float64
+		learned, known := d.baselines[ip]
+		if known {
+			baseline = learned.pps
+		}
+		aboveBaseline := !known || d.config.BaselineFactor <= 0 || pps > baseline*d.config.BaselineFactor
+
+		newEvent := func(attackType XnfvAttackType, attackPps float64) XnfvAttackEvent {
+			return XnfvAttackEvent{
+				Type:         attackType,
+				Victim:       ip,
+				WindowStart:  d.windowStart,
+				WindowEnd:    now,
+				EstimatedPps: attackPps,
+				EstimatedBps: bps,
+				BaselinePps:  baseline,
+				TopSources:   d.topSources(v, seconds),
+				Ingress:      d.topIngress(v, seconds),
+			}
+		}
+
+		if aboveBaseline && (pps >= d.config.VolumetricPps || bps >= d.config.VolumetricBps) {
+			events = append(events, newEvent(XnfvAttackVolumetric, pps))
+		}
+		if synPps := v.syn / seconds; synPps >= d.config.SynPps {
+			// none of the SYNs was answered, there is no ratio to report
+			if v.synAck == 0 {
+				events = append(events, newEvent(XnfvAttackSynFlood, synPps))
+			} else if ratio := v.syn / v.synAck; ratio >= d.config.SynAckRatio {
+				event := newEvent(XnfvAttackSynFlood, synPps)
+				event.SynAckRatio = &ratio
+				events = append(events, event)
+			}
+		}
+		if reflectionPps := v.reflection / seconds; reflectionPps >= d.config.ReflectionPps {
+			events = append(events, newEvent(XnfvAttackUDPReflection, reflectionPps))
+		}
+		if icmpPps := v.icmp / seconds; icmpPps >= d.config.ICMPPps {
+			events = append(events, newEvent(XnfvAttackICMPFlood, icmpPps))
+		}
+
+		if known {
+			learned.lastSeen = now
+		}
+		// only learn from windows that were not themselves an attack
+		if len(events) > detected {
+			continue
+		}
+		if !known {
+			d.baselines[ip] = &xnfvBaseline{pps, now}
+		} else {
+			learned.pps += d.config.BaselineAlpha * (pps - learned.pps)
+		}
+	}
+	for ip, learned := range d.baselines {
+		if now.Sub(learned.lastSeen) > d.config.BaselineMaxAge {
+			delete(d.baselines, ip)
+		}
+	}
+
+	d.windowStart = now
+	d.victims = map[string]*xnfvVictimStats{}
+	return events
+}
+
+func (d *XnfvDDoSDetector) topSources(v *xnfvVictimStats, seconds float64) []XnfvAttackSource {
+	sources := make([]XnfvAttackSource, 0, len(v.sources))
+	for ip, pkts := range v.sources {
+		sources = append(sources, XnfvAttackSource{ip, pkts / seconds})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].EstimatedPps > sources[j].EstimatedPps })
+	if len(sources) > d.config.TopSources {
+		sources = sources[:d.config.TopSources]
+	}
+	return sources
+}
+
+func (d *XnfvDDoSDetector) topIngress(v *xnfvVictimStats, seconds float64) []XnfvAttackIngress {
+	ingress := make([]XnfvAttackIngress, 0, len(v.ingress))
+	for key, pkts := range v.ingress {
+		ingress = append(ingress, XnfvAttackIngress{key.dataPath, key.portName, key.ofPort, key.inputInterface, pkts / seconds})
+	}
+	sort.Slice(ingress, func(i, j int) bool { return ingress[i].EstimatedPps > ingress[j].EstimatedPps })
+	if len(ingress) > d.config.TopSources {
+		ingress = ingress[:d.config.TopSources]
+	}
+	return ingress
+}
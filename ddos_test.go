@@ -0,0 +1,204 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+var xnfvTestEpoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func xnfvTestTCPSample(at time.Duration, src string, dst string, flags uint8) XnfvFlowSample {
+	return XnfvFlowSample{
+		Timestamp:    xnfvTestEpoch.Add(at),
+		SamplingRate: 1000,
+		FrameLength:  64,
+		SrcIP:        net.ParseIP(src),
+		DstIP:        net.ParseIP(dst),
+		IPProtocol:   layers.IPProtocolTCP,
+		SrcPort:      40000,
+		DstPort:      80,
+		TCPFlags:     flags,
+	}
+}
+
+func xnfvEventsOfType(events []XnfvAttackEvent, attackType XnfvAttackType) []XnfvAttackEvent {
+	var found []XnfvAttackEvent
+	for _, event := range events {
+		if event.Type == attackType {
+			found = append(found, event)
+		}
+	}
+	return found
+}
+
+func TestXnfvDDoSDetectorSynFlood(t *testing.T) {
+	tests := []struct {
+		name    string
+		syn     int
+		synAck  int
+		flagged bool
+	}{
+		{"syn flood", 200, 0, true},
+		{"few answered syns", 200, 40, true},
+		{"answered syns", 200, 200, false},
+		{"below the syn rate", 50, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := NewXnfvDDoSDetector(DefaultXnfvDDoSConfig())
+			for i := 0; i < test.syn; i++ {
+				d.Observe(xnfvTestTCPSample(time.Duration(i)*time.Millisecond, "192.0.2.1", "10.0.0.1", xnfvTCPFlagSYN))
+			}
+			for i := 0; i < test.synAck; i++ {
+				d.Observe(xnfvTestTCPSample(time.Duration(i)*time.Millisecond, "10.0.0.1", "192.0.2.1", xnfvTCPFlagSYN|xnfvTCPFlagACK))
+			}
+			events := xnfvEventsOfType(d.Tick(xnfvTestEpoch.Add(10*time.Second)), XnfvAttackSynFlood)
+			if flagged := len(events) == 1; flagged != test.flagged {
+				t.Fatalf("syn flood events %v, want flagged %v", events, test.flagged)
+			}
+			if test.flagged && (events[0].Victim != "10.0.0.1" || events[0].EstimatedPps != 20000) {
+				t.Errorf("event %+v, want victim 10.0.0.1 at 20000 pps", events[0])
+			}
+			// the ratio of unanswered SYNs is left out rather than made up
+			if test.flagged && test.synAck == 0 && events[0].SynAckRatio != nil {
+				t.Errorf("ratio %v without SYN-ACKs", *events[0].SynAckRatio)
+			}
+			if test.flagged && test.synAck > 0 && (events[0].SynAckRatio == nil || *events[0].SynAckRatio != 5) {
+				t.Errorf("ratio %v, want 5", events[0].SynAckRatio)
+			}
+		})
+	}
+}
+
+func TestXnfvDDoSDetectorFloods(t *testing.T) {
+	sample := func(protocol layers.IPProtocol, srcPort uint16) XnfvFlowSample {
+		s := xnfvTestTCPSample(0, "192.0.2.1", "10.0.0.1", 0)
+		s.IPProtocol, s.SrcPort = protocol, srcPort
+		return s
+	}
+	tests := []struct {
+		name    string
+		s       XnfvFlowSample
+		samples int
+		attack  XnfvAttackType
+	}{
+		{"ntp reflection", sample(layers.IPProtocolUDP, 123), 200, XnfvAttackUDPReflection},
+		{"memcached reflection", sample(layers.IPProtocolUDP, 11211), 200, XnfvAttackUDPReflection},
+		{"udp from another port", sample(layers.IPProtocolUDP, 40000), 200, ""},
+		{"reflection below the rate", sample(layers.IPProtocolUDP, 53), 50, ""},
+		{"icmp flood", sample(layers.IPProtocolICMPv4, 0), 200, XnfvAttackICMPFlood},
+		{"icmpv6 flood", sample(layers.IPProtocolICMPv6, 0), 200, XnfvAttackICMPFlood},
+		{"icmp below the rate", sample(layers.IPProtocolICMPv4, 0), 50, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := NewXnfvDDoSDetector(DefaultXnfvDDoSConfig())
+			for i := 0; i < test.samples; i++ {
+				d.Observe(test.s)
+			}
+			events := d.Tick(xnfvTestEpoch.Add(10 * time.Second))
+			if test.attack == "" {
+				if len(events) != 0 {
+					t.Fatalf("events %+v", events)
+				}
+				return
+			}
+			// 200 samples at 1:1000 in 10 seconds
+			if len(events) != 1 || events[0].Type != test.attack || events[0].Victim != "10.0.0.1" || events[0].EstimatedPps != 20000 {
+				t.Fatalf("events %+v, want %s", events, test.attack)
+			}
+			if events[0].SynAckRatio != nil || len(events[0].TopSources) != 1 || events[0].TopSources[0].IP != "192.0.2.1" {
+				t.Errorf("event %+v", events[0])
+			}
+		})
+	}
+}
+
+func TestXnfvDDoSDetectorTick(t *testing.T) {
+	d := NewXnfvDDoSDetector(DefaultXnfvDDoSConfig())
+	if events := d.Tick(xnfvTestEpoch); events != nil {
+		t.Fatalf("tick without samples: %v", events)
+	}
+	for i := 0; i < 200; i++ {
+		d.Observe(xnfvTestTCPSample(0, "192.0.2.1", "10.0.0.1", xnfvTCPFlagSYN))
+	}
+	if events := d.Tick(xnfvTestEpoch.Add(9 * time.Second)); events != nil {
+		t.Fatalf("tick before the end of the window: %v", events)
+	}
+	if events := d.Tick(xnfvTestEpoch.Add(10 * time.Second)); len(xnfvEventsOfType(events, XnfvAttackSynFlood)) != 1 {
+		t.Fatalf("tick at the end of the window: %v", events)
+	}
+	if events := d.Tick(xnfvTestEpoch.Add(20 * time.Second)); len(events) != 0 {
+		t.Fatalf("the window was not reset: %v", events)
+	}
+}
+
+func TestXnfvDDoSDetectorQuietPeriod(t *testing.T) {
+	d := NewXnfvDDoSDetector(DefaultXnfvDDoSConfig())
+	for i := 0; i < 200; i++ {
+		d.Observe(xnfvTestTCPSample(time.Duration(i)*time.Millisecond, "192.0.2.1", "10.0.0.1", xnfvTCPFlagSYN))
+	}
+	// without a timer the next sample closes the window, an hour later
+	events := xnfvEventsOfType(d.Observe(xnfvTestTCPSample(time.Hour, "192.0.2.1", "10.0.0.9", 0)), XnfvAttackSynFlood)
+	if len(events) != 1 {
+		t.Fatalf("syn flood spread over the quiet hour: %v", events)
+	}
+	if end := xnfvTestEpoch.Add(10 * time.Second); !events[0].WindowEnd.Equal(end) || events[0].EstimatedPps != 20000 {
+		t.Errorf("window end %v at %v pps, want %v at 20000 pps", events[0].WindowEnd, events[0].EstimatedPps, end)
+	}
+	if !d.windowStart.Equal(xnfvTestEpoch.Add(time.Hour)) {
+		t.Errorf("next window starts at %v", d.windowStart)
+	}
+}
+
+func TestXnfvDDoSDetectorBaselineAgeOut(t *testing.T) {
+	config := DefaultXnfvDDoSConfig()
+	d := NewXnfvDDoSDetector(config)
+	d.Observe(xnfvTestTCPSample(0, "192.0.2.1", "10.0.0.1", xnfvTCPFlagACK))
+	d.Tick(xnfvTestEpoch.Add(config.Window))
+	if len(d.baselines) != 1 {
+		t.Fatalf("baselines %v, want the one of 10.0.0.1", d.baselines)
+	}
+
+	d.Observe(xnfvTestTCPSample(config.Window, "192.0.2.1", "10.0.0.2", xnfvTCPFlagACK))
+	d.Tick(xnfvTestEpoch.Add(2 * config.Window))
+	if len(d.baselines) != 2 {
+		t.Fatalf("baselines %v, want 10.0.0.1 and 10.0.0.2", d.baselines)
+	}
+
+	later := 2*config.Window + config.BaselineMaxAge
+	d.Observe(xnfvTestTCPSample(later, "192.0.2.1", "10.0.0.2", xnfvTCPFlagACK))
+	d.Tick(xnfvTestEpoch.Add(later + config.Window))
+	if _, ok := d.baselines["10.0.0.1"]; ok || len(d.baselines) != 1 {
+		t.Errorf("baselines %v, want 10.0.0.1 aged out", d.baselines)
+	}
+}
+
+func TestXnfvDDoSDetectorBaseline(t *testing.T) {
+	config := DefaultXnfvDDoSConfig()
+	config.VolumetricPps = 10000
+	d := NewXnfvDDoSDetector(config)
+	// a steady 5000 pps towards a busy server is learned as its baseline
+	for window := 0; window < 5; window++ {
+		start := time.Duration(window) * config.Window
+		for i := 0; i < 50; i++ {
+			d.Observe(xnfvTestTCPSample(start+time.Duration(i)*time.Millisecond, "192.0.2.1", "10.0.0.1", xnfvTCPFlagACK))
+		}
+		events := d.Tick(xnfvTestEpoch.Add(start + config.Window))
+		if window > 0 && len(events) != 0 {
+			t.Fatalf("window %d: %v", window, events)
+		}
+	}
+	// four times the baseline is an attack
+	start := 5 * config.Window
+	for i := 0; i < 250; i++ {
+		d.Observe(xnfvTestTCPSample(start+time.Duration(i)*time.Millisecond, "192.0.2.1", "10.0.0.1", xnfvTCPFlagACK))
+	}
+	events := xnfvEventsOfType(d.Tick(xnfvTestEpoch.Add(start+config.Window)), XnfvAttackVolumetric)
+	if len(events) != 1 || events[0].BaselinePps != 5000 || events[0].EstimatedPps != 25000 {
+		t.Fatalf("volumetric events %+v", events)
+	}
+}
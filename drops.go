@@ -0,0 +1,316 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Dropped Packet Monitoring
+// ****************************************************************************************************
+
+// XnfvDropEventType names the kind of XnfvDropEvent
+type XnfvDropEventType string
+
+const (
+	// a port starts dropping packets for a reason not seen on it before
+	XnfvDropNewReason XnfvDropEventType = "packet-drop-new-reason"
+	// a port dropped more than BurstDrops packets for one reason within
+	// BurstWindow
+	XnfvDropBurst XnfvDropEventType = "packet-drop-burst"
+)
+
+type XnfvDropTableConfig struct {
+	BurstWindow time.Duration
+	BurstDrops  uint64
+	// MaxAge removes the drop statistics not updated for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvDropTableConfig() XnfvDropTableConfig {
+	return XnfvDropTableConfig{
+		BurstWindow: time.Minute,
+		BurstDrops:  100,
+		MaxAge:      time.Hour,
+	}
+}
+
+// XnfvDropKey aggregates the drops of an agent by port and reason. The
+// port is the input interface, or the output interface for the drops
+// that happened on egress.
+type XnfvDropKey struct {
+	Agent   string                `json:"agent"`
+	IfIndex uint32                `json:"ifIndex"`
+	Egress  bool                  `json:"egress"`
+	Reason  sflow.SFlowDropReason `json:"reason"`
+}
+
+// XnfvDroppedPacket describes the last packet dropped for a key
+type XnfvDroppedPacket struct {
+	SrcMac     string `json:"srcMac,omitempty"`
+	DstMac     string `json:"dstMac,omitempty"`
+	VLAN       uint16 `json:"vlan,omitempty"`
+	SrcIP      string `json:"srcIP,omitempty"`
+	DstIP      string `json:"dstIP,omitempty"`
+	IPProtocol uint8  `json:"ipProtocol,omitempty"`
+	SrcPort    uint16 `json:"srcPort,omitempty"`
+	DstPort    uint16 `json:"dstPort,omitempty"`
+	// Function is the code that dropped the packet, when reported
+	Function string `json:"function,omitempty"`
+}
+
+// XnfvDropStats counts the discard samples of a key
+type XnfvDropStats struct {
+	XnfvDropKey
+	ReasonName string `json:"reasonName"`
+	// switch and port resolved from the inventory, empty when unknown
+	SwitchDataPath string `json:"switchDataPath,omitempty"`
+	PortName       string `json:"portName,omitempty"`
+	Drops          uint64 `json:"drops"`
+	// Rate is the drops per second over the last complete BurstWindow
+	Rate       float64           `json:"rate"`
+	LastPacket XnfvDroppedPacket `json:"lastPacket"`
+	FirstSeen  time.Time         `json:"firstSeen"`
+	LastSeen   time.Time         `json:"lastSeen"`
+}
+
+// XnfvDropSource is the drop accounting of a data source of an agent.
+// Unreported are the drops the agent did not send samples for because of
+// its rate limit.
+type XnfvDropSource struct {
+	Agent       string    `json:"agent"`
+	SourceIndex uint32    `json:"sourceIndex"`
+	Reported    uint64    `json:"reported"`
+	Unreported  uint64    `json:"unreported"`
+	LastSeen    time.Time `json:"lastSeen"`
+}
+
+// XnfvDropReport is the answer of the drop query API
+type XnfvDropReport struct {
+	Drops   []XnfvDropStats  `json:"drops"`
+	Sources []XnfvDropSource `json:"sources"`
+}
+
+// XnfvDropEvent reports a new drop reason or a drop burst on a port
+type XnfvDropEvent struct {
+	Type  XnfvDropEventType `json:"type"`
+	Stats XnfvDropStats     `json:"stats"`
+	// Count is the drops within Window for a burst
+	Count  uint64        `json:"count,omitempty"`
+	Window time.Duration `json:"window,omitempty"`
+	Time   time.Time     `json:"time"`
+}
+
+type xnfvDropSourceKey struct {
+	agent       string
+	sourceIndex uint32
+}
+
+type xnfvDropEntry struct {
+	stats        XnfvDropStats
+	windowStart  time.Time
+	windowDrops  uint64
+	burstPrinted bool
+}
+
+type xnfvDropSourceEntry struct {
+	source    XnfvDropSource
+	lastDrops uint32
+}
+
+// XnfvDropResolver finds the switch and port name of an interface of an
+// agent in the inventory
+type XnfvDropResolver func(agent string, ifIndex uint32) (switchDataPath string, portName string, ok bool)
+
+// XnfvDropTable aggregates the discard samples by agent, port and reason.
+// It is safe for concurrent use.
+type XnfvDropTable struct {
+	config  XnfvDropTableConfig
+	mutex   sync.RWMutex
+	drops   map[XnfvDropKey]*xnfvDropEntry
+	sources map[xnfvDropSourceKey]*xnfvDropSourceEntry
+}
+
+func NewXnfvDropTable(config XnfvDropTableConfig) *XnfvDropTable {
+	return &XnfvDropTable{
+		config:  config,
+		drops:   map[XnfvDropKey]*xnfvDropEntry{},
+		sources: map[xnfvDropSourceKey]*xnfvDropSourceEntry{},
+	}
+}
+
+// ObserveDatagram counts the discard samples of a datagram and returns
+// the new reason and burst events they raise. resolve may be nil.
+func (t *XnfvDropTable) ObserveDatagram(datagram sflow.Datagram, resolve XnfvDropResolver, now time.Time) []XnfvDropEvent {
+	if datagram.AgentAddress == nil || len(datagram.DiscardSamples) == 0 {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	agent := datagram.AgentAddress.String()
+	var events []XnfvDropEvent
+	for _, discardSample := range datagram.DiscardSamples {
+		t.observeSource(agent, discardSample, now)
+
+		key := XnfvDropKey{Agent: agent, IfIndex: discardSample.InputInterface, Reason: discardSample.Reason}
+		if key.IfIndex == 0 && discardSample.OutputInterface != 0 {
+			key.IfIndex, key.Egress = discardSample.OutputInterface, true
+		}
+		entry, ok := t.drops[key]
+		if !ok {
+			entry = &xnfvDropEntry{
+				stats:       XnfvDropStats{XnfvDropKey: key, ReasonName: key.Reason.String(), FirstSeen: now},
+				windowStart: now,
+			}
+			if resolve != nil {
+				entry.stats.SwitchDataPath, entry.stats.PortName, _ = resolve(agent, key.IfIndex)
+			}
+			t.drops[key] = entry
+		}
+		entry.stats.Drops++
+		entry.stats.LastPacket = droppedPacket(discardSample)
+		entry.stats.LastSeen = now
+
+		if elapsed := now.Sub(entry.windowStart); elapsed >= t.config.BurstWindow {
+			// the drops of the window that just ended give the rate, an
+			// idle period resets it
+			entry.stats.Rate = 0
+			if elapsed < 2*t.config.BurstWindow {
+				entry.stats.Rate = float64(entry.windowDrops) / t.config.BurstWindow.Seconds()
+			}
+			entry.windowStart, entry.windowDrops, entry.burstPrinted = now, 0, false
+		}
+		entry.windowDrops++
+
+		if !ok {
+			events = append(events, XnfvDropEvent{Type: XnfvDropNewReason, Stats: entry.stats, Time: now})
+		}
+		if entry.windowDrops >= t.config.BurstDrops && !entry.burstPrinted {
+			entry.burstPrinted = true
+			events = append(events, XnfvDropEvent{
+				Type:   XnfvDropBurst,
+				Stats:  entry.stats,
+				Count:  entry.windowDrops,
+				Window: t.config.BurstWindow,
+				Time:   now,
+			})
+		}
+	}
+	return events
+}
+
+// observeSource accounts the drops of a data source. Drops counts the
+// unreported discards, after a restart of the agent the sample only
+// becomes the reference of the next one.
+func (t *XnfvDropTable) observeSource(agent string, discardSample sflow.SFlowDiscardSample, now time.Time) {
+	key := xnfvDropSourceKey{agent, uint32(discardSample.SourceIDIndex)}
+	entry, ok := t.sources[key]
+	if !ok {
+		entry = &xnfvDropSourceEntry{source: XnfvDropSource{Agent: agent, SourceIndex: key.sourceIndex}}
+		t.sources[key] = entry
+	} else {
+		var deltas xnfvCounterDeltas
+		if unreported := deltas.delta(entry.lastDrops, discardSample.Drops); !deltas.reset {
+			entry.source.Unreported += uint64(unreported)
+		}
+	}
+	entry.lastDrops = discardSample.Drops
+	entry.source.Reported++
+	entry.source.LastSeen = now
+}
+
+// droppedPacket summarizes the header of a discard sample
+func droppedPacket(discardSample sflow.SFlowDiscardSample) XnfvDroppedPacket {
+	var packet XnfvDroppedPacket
+	sample := sflow.SFlowFlowSample{
+		SamplingRate:    1,
+		InputInterface:  discardSample.InputInterface,
+		OutputInterface: discardSample.OutputInterface,
+		Records:         discardSample.Records,
+	}
+	if flowSample, ok := xnfvFlowSampleFromGeneric(sample); ok {
+		packet = XnfvDroppedPacket{
+			SrcIP:      ipString(flowSample.SrcIP),
+			DstIP:      ipString(flowSample.DstIP),
+			VLAN:       flowSample.VLAN,
+			IPProtocol: uint8(flowSample.IPProtocol),
+			SrcPort:    flowSample.SrcPort,
+			DstPort:    flowSample.DstPort,
+		}
+		if flowSample.SrcMac != nil {
+			packet.SrcMac, packet.DstMac = flowSample.SrcMac.String(), flowSample.DstMac.String()
+		}
+	}
+	for _, record := range discardSample.Records {
+		if function, ok := record.(sflow.SFlowExtendedFunctionFlowRecord); ok {
+			packet.Function = function.Symbol
+		}
+	}
+	return packet
+}
+
+// AgeOut removes the statistics not updated since MaxAge before now
+func (t *XnfvDropTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, entry := range t.drops {
+		if now.Sub(entry.stats.LastSeen) > t.config.MaxAge {
+			delete(t.drops, key)
+		}
+	}
+	for key, entry := range t.sources {
+		if now.Sub(entry.source.LastSeen) > t.config.MaxAge {
+			delete(t.sources, key)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Drop Query API
+// ****************************************************************************************************
+
+// Report returns the drop statistics, optionally of one agent or one
+// reason (name or code) only, the largest drop counts first
+func (t *XnfvDropTable) Report(agent string, reason string) XnfvDropReport {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	report := XnfvDropReport{Drops: []XnfvDropStats{}, Sources: []XnfvDropSource{}}
+	for key, entry := range t.drops {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		if reason != "" && reason != entry.stats.ReasonName && reason != strconv.Itoa(int(key.Reason)) {
+			continue
+		}
+		report.Drops = append(report.Drops, entry.stats)
+	}
+	sort.Slice(report.Drops, func(i, j int) bool {
+		return report.Drops[i].Drops > report.Drops[j].Drops
+	})
+	for key, entry := range t.sources {
+		if agent != "" && key.agent != agent {
+			continue
+		}
+		report.Sources = append(report.Sources, entry.source)
+	}
+	sort.Slice(report.Sources, func(i, j int) bool {
+		if report.Sources[i].Agent != report.Sources[j].Agent {
+			return report.Sources[i].Agent < report.Sources[j].Agent
+		}
+		return report.Sources[i].SourceIndex < report.Sources[j].SourceIndex
+	})
+	return report
+}
+
+// ServeHTTP answers GET /drops[?agent=][&reason=] with the drop statistics
+// as JSON
+func (t *XnfvDropTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	writeJSON(w, t.Report(query.Get("agent"), query.Get("reason")))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// xnfvTestDropDatagram wraps discard samples in a datagram of the agent
+// 192.0.2.1
+func xnfvTestDropDatagram(discardSamples ...sflow.SFlowDiscardSample) sflow.Datagram {
+	return sflow.Datagram{AgentAddress: net.ParseIP("192.0.2.1"), DiscardSamples: discardSamples}
+}
+
+func xnfvTestDropEventTypes(events []XnfvDropEvent) []XnfvDropEventType {
+	var types []XnfvDropEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestXnfvDropTableAggregation(t *testing.T) {
+	resolve := func(agent string, ifIndex uint32) (string, string, bool) {
+		if agent == "192.0.2.1" && ifIndex == 3 {
+			return "0000000000000001", "s1-eth3", true
+		}
+		return "", "", false
+	}
+	ipv4 := sflow.SFlowIpv4FlowRecord{SFlowIpv4Record: sflow.SFlowIpv4Record{
+		Protocol: 17, IPSrc: net.ParseIP("10.0.0.1").To4(), IPDst: net.ParseIP("10.0.0.2").To4(), PortSrc: 5000, PortDst: 53,
+	}}
+	table := NewXnfvDropTable(DefaultXnfvDropTableConfig())
+	events := table.ObserveDatagram(xnfvTestDropDatagram(
+		sflow.SFlowDiscardSample{InputInterface: 3, Reason: sflow.SFlowDropACL, Records: sflow.SFlowRecords{ipv4}},
+		sflow.SFlowDiscardSample{InputInterface: 3, Reason: sflow.SFlowDropACL, Records: sflow.SFlowRecords{ipv4, sflow.SFlowExtendedFunctionFlowRecord{Symbol: "acl_drop"}}},
+		// dropped on egress
+		sflow.SFlowDiscardSample{OutputInterface: 4, Reason: sflow.SFlowDropTTLExceeded},
+	), resolve, xnfvTestEpoch)
+
+	types := xnfvTestDropEventTypes(events)
+	if len(types) != 2 || types[0] != XnfvDropNewReason || types[1] != XnfvDropNewReason {
+		t.Fatalf("events %v, want two new reasons", types)
+	}
+	report := table.Report("", "")
+	if len(report.Drops) != 2 {
+		t.Fatalf("drops %+v", report.Drops)
+	}
+	acl := report.Drops[0]
+	if acl.XnfvDropKey != (XnfvDropKey{"192.0.2.1", 3, false, sflow.SFlowDropACL}) || acl.Drops != 2 || acl.ReasonName != "acl" {
+		t.Fatalf("acl drops %+v", acl)
+	}
+	if acl.SwitchDataPath != "0000000000000001" || acl.PortName != "s1-eth3" {
+		t.Fatalf("acl port %q %q, want resolved", acl.SwitchDataPath, acl.PortName)
+	}
+	want := XnfvDroppedPacket{SrcIP: "10.0.0.1", DstIP: "10.0.0.2", IPProtocol: 17, SrcPort: 5000, DstPort: 53, Function: "acl_drop"}
+	if acl.LastPacket != want {
+		t.Fatalf("last packet %+v, want %+v", acl.LastPacket, want)
+	}
+	if egress := report.Drops[1]; egress.IfIndex != 4 || !egress.Egress || egress.PortName != "" {
+		t.Fatalf("egress drops %+v", egress)
+	}
+	if len(table.Report("", "ttl_exceeded").Drops) != 1 || len(table.Report("", "258").Drops) != 1 || len(table.Report("192.0.2.2", "").Drops) != 0 {
+		t.Fatalf("report filters")
+	}
+}
+
+func TestXnfvDropTableBurst(t *testing.T) {
+	config := DefaultXnfvDropTableConfig()
+	config.BurstDrops = 3
+	table := NewXnfvDropTable(config)
+	drop := sflow.SFlowDiscardSample{InputInterface: 1, Reason: sflow.SFlowDropNoBufferSpace}
+	steps := []struct {
+		at   time.Duration
+		want []XnfvDropEventType
+	}{
+		{0, []XnfvDropEventType{XnfvDropNewReason}},
+		{10 * time.Second, nil},
+		{20 * time.Second, []XnfvDropEventType{XnfvDropBurst}},
+		// reported once per window
+		{30 * time.Second, nil},
+		// a new window
+		{70 * time.Second, nil},
+		{80 * time.Second, nil},
+		{90 * time.Second, []XnfvDropEventType{XnfvDropBurst}},
+	}
+	for i, step := range steps {
+		types := xnfvTestDropEventTypes(table.ObserveDatagram(xnfvTestDropDatagram(drop), nil, xnfvTestEpoch.Add(step.at)))
+		if len(types) != len(step.want) || (len(types) == 1 && types[0] != step.want[0]) {
+			t.Fatalf("step %d: events %v, want %v", i, types, step.want)
+		}
+	}
+	// 4 drops in the first minute
+	if rate := table.Report("", "").Drops[0].Rate; rate != 4.0/60 {
+		t.Fatalf("rate %v, want %v", rate, 4.0/60)
+	}
+}
+
+func TestXnfvDropTableUnreported(t *testing.T) {
+	table := NewXnfvDropTable(DefaultXnfvDropTableConfig())
+	for i, drops := range []uint32{
+		0xFFFFFF00,
+		0xFFFFFF80,
+		// wraps
+		0x00000010,
+		// the agent restarted
+		5,
+		25,
+	} {
+		table.ObserveDatagram(xnfvTestDropDatagram(sflow.SFlowDiscardSample{SourceIDIndex: 2, Drops: drops, InputInterface: 1}), nil, xnfvTestEpoch.Add(time.Duration(i)*time.Second))
+	}
+	sources := table.Report("", "").Sources
+	want := XnfvDropSource{Agent: "192.0.2.1", SourceIndex: 2, Reported: 5, Unreported: 0x80 + 0x90 + 20, LastSeen: xnfvTestEpoch.Add(4 * time.Second)}
+	if len(sources) != 1 || sources[0] != want {
+		t.Fatalf("sources %+v, want %+v", sources, want)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Elephant Flow Detection
+// ****************************************************************************************************
+
+// XnfvElephantConfig holds the elephant flow threshold. A flow is the
+// 5-tuple of the sampled headers on one ingress switch port, its rate is
+// estimated from the sampled bytes scaled by the sampling rate over Window.
+type XnfvElephantConfig struct {
+	Window time.Duration
+
+	// ThresholdBps is the rate above which a flow is an elephant
+	ThresholdBps float64
+
+	// MaxFlows bounds the flows accounted per window, the flows first
+	// sampled once it is reached are not tracked until the next window
+	MaxFlows int
+}
+
+func DefaultXnfvElephantConfig() XnfvElephantConfig {
+	return XnfvElephantConfig{
+		Window:       10 * time.Second,
+		ThresholdBps: 1e9,
+		MaxFlows:     100000,
+	}
+}
+
+// XnfvElephantFlow is the 5-tuple and the ingress switch port of a flow
+type XnfvElephantFlow struct {
+	SrcIP          string            `json:"srcIp"`
+	DstIP          string            `json:"dstIp"`
+	IPProtocol     layers.IPProtocol `json:"ipProtocol"`
+	SrcPort        uint16            `json:"srcPort"`
+	DstPort        uint16            `json:"dstPort"`
+	SwitchDataPath string            `json:"switchDataPath"`
+	PortName       string            `json:"portName"`
+	OfPort         uint32            `json:"ofPort"`
+	InputInterface uint32            `json:"inputInterface"`
+}
+
+// XnfvElephantEvent is emitted once per window for each elephant flow
+type XnfvElephantEvent struct {
+	Type string `json:"type"`
+	XnfvElephantFlow
+	WindowStart  time.Time `json:"windowStart"`
+	WindowEnd    time.Time `json:"windowEnd"`
+	EstimatedBps float64   `json:"estimatedBps"`
+	EstimatedPps float64   `json:"estimatedPps"`
+}
+
+type xnfvElephantStats struct {
+	packets float64
+	bytes   float64
+}
+
+// XnfvElephantDetector estimates the rate of each flow from the flow
+// samples and reports the flows above the threshold
+type XnfvElephantDetector struct {
+	config      XnfvElephantConfig
+	windowStart time.Time
+	flows       map[XnfvElephantFlow]*xnfvElephantStats
+}
+
+func NewXnfvElephantDetector(config XnfvElephantConfig) *XnfvElephantDetector {
+	return &XnfvElephantDetector{config: config, flows: map[XnfvElephantFlow]*xnfvElephantStats{}}
+}
+
+// Observe accounts one flow sample. When the sample falls after the end of
+// the current window the window is evaluated first and its events returned.
+func (d *XnfvElephantDetector) Observe(s XnfvFlowSample) []XnfvElephantEvent {
+	var events []XnfvElephantEvent
+	if d.windowStart.IsZero() {
+		d.windowStart = s.Timestamp
+	} else if s.Timestamp.Sub(d.windowStart) >= d.config.Window {
+		events = d.Flush(d.windowStart.Add(d.config.Window))
+		d.windowStart = s.Timestamp
+	}
+	if s.SrcIP == nil || s.DstIP == nil {
+		return events
+	}
+
+	flow := XnfvElephantFlow{
+		SrcIP:          s.SrcIP.String(),
+		DstIP:          s.DstIP.String(),
+		IPProtocol:     s.IPProtocol,
+		SrcPort:        s.SrcPort,
+		DstPort:        s.DstPort,
+		SwitchDataPath: s.SwitchDataPathString(),
+		PortName:       s.PortName,
+		OfPort:         s.OfPort,
+		InputInterface: s.InputInterface,
+	}
+	stats, ok := d.flows[flow]
+	if !ok {
+		if len(d.flows) >= d.config.MaxFlows {
+			return events
+		}
+		stats = &xnfvElephantStats{}
+		d.flows[flow] = stats
+	}
+	stats.packets += s.EstimatedPackets()
+	stats.bytes += s.EstimatedBytes()
+	return events
+}
+
+// Tick closes the current window once it is Window long, it is called on
+// a timer
+func (d *XnfvElephantDetector) Tick(now time.Time) []XnfvElephantEvent {
+	if d.windowStart.IsZero() || now.Sub(d.windowStart) < d.config.Window {
+		return nil
+	}
+	return d.Flush(now)
+}
+
+// Flush evaluates the current window, returns the elephant flows, the
+// largest first, and starts a new window at now.
+func (d *XnfvElephantDetector) Flush(now time.Time) []XnfvElephantEvent {
+	var events []XnfvElephantEvent
+	seconds := now.Sub(d.windowStart).Seconds()
+	if seconds <= 0 {
+		seconds = d.config.Window.Seconds()
+	}
+	for flow, stats := range d.flows {
+		if bps := stats.bytes * 8 / seconds; bps >= d.config.ThresholdBps {
+			events = append(events, XnfvElephantEvent{
+				Type:             "elephant-flow",
+				XnfvElephantFlow: flow,
+				WindowStart:      d.windowStart,
+				WindowEnd:        now,
+				EstimatedBps:     bps,
+				EstimatedPps:     stats.packets / seconds,
+			})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].EstimatedBps > events[j].EstimatedBps })
+	d.windowStart = now
+	d.flows = map[XnfvElephantFlow]*xnfvElephantStats{}
+	return events
+}
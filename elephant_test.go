@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// xnfvTestElephantSample is a 1500 byte TCP packet sampled 1 in 1000 on
+// the port 3 of the switch 0000000000000001
+func xnfvTestElephantSample(at time.Duration, src string) XnfvFlowSample {
+	s := xnfvTestTCPSample(at, src, "10.0.0.1", xnfvTCPFlagACK)
+	s.FrameLength = 1500
+	s.SwitchDataPath, s.PortName, s.OfPort, s.InputInterface = []byte{0, 0, 0, 0, 0, 0, 0, 1}, "vnet0", 3, 7
+	return s
+}
+
+func TestXnfvElephantDetector(t *testing.T) {
+	d := NewXnfvElephantDetector(DefaultXnfvElephantConfig())
+	// 1000 samples of 1.5 MB in 10s are 1.2 Gbit/s, 100 are a mouse
+	for i := 0; i < 1000; i++ {
+		d.Observe(xnfvTestElephantSample(time.Duration(i)*time.Millisecond, "192.0.2.1"))
+		if i%10 == 0 {
+			d.Observe(xnfvTestElephantSample(time.Duration(i)*time.Millisecond, "192.0.2.2"))
+		}
+	}
+	if events := d.Tick(xnfvTestEpoch.Add(5 * time.Second)); len(events) != 0 {
+		t.Fatalf("events before the end of the window %+v", events)
+	}
+	events := d.Tick(xnfvTestEpoch.Add(10 * time.Second))
+	if len(events) != 1 {
+		t.Fatalf("events %+v", events)
+	}
+	event := events[0]
+	want := XnfvElephantFlow{"192.0.2.1", "10.0.0.1", layers.IPProtocolTCP, 40000, 80, "0000000000000001", "vnet0", 3, 7}
+	if event.XnfvElephantFlow != want || event.EstimatedBps != 1.2e9 || event.EstimatedPps != 100000 {
+		t.Fatalf("event %+v", event)
+	}
+	if !event.WindowStart.Equal(xnfvTestEpoch) || !event.WindowEnd.Equal(xnfvTestEpoch.Add(10*time.Second)) {
+		t.Fatalf("window %v - %v", event.WindowStart, event.WindowEnd)
+	}
+	// the window was reset
+	if events := d.Tick(xnfvTestEpoch.Add(20 * time.Second)); len(events) != 0 {
+		t.Fatalf("events of an empty window %+v", events)
+	}
+}
+
+func TestXnfvElephantDetectorMaxFlows(t *testing.T) {
+	config := DefaultXnfvElephantConfig()
+	config.MaxFlows = 1
+	d := NewXnfvElephantDetector(config)
+	for i := 0; i < 1000; i++ {
+		d.Observe(xnfvTestElephantSample(time.Duration(i)*time.Millisecond, "192.0.2.1"))
+		d.Observe(xnfvTestElephantSample(time.Duration(i)*time.Millisecond, "192.0.2.2"))
+	}
+	// a sample after the window closes it
+	events := d.Observe(xnfvTestElephantSample(10*time.Second, "192.0.2.2"))
+	if len(events) != 1 || events[0].SrcIP != "192.0.2.1" {
+		t.Fatalf("events %+v", events)
+	}
+}
+
+func TestXnfvMitigatorElephant(t *testing.T) {
+	controller := newXnfvControllerStandIn(t)
+	m := NewXnfvMitigator(xnfvTestMitigationConfig(controller.server.URL, XnfvControllerRyu))
+	event := XnfvElephantEvent{
+		Type:             "elephant-flow",
+		XnfvElephantFlow: XnfvElephantFlow{"192.0.2.1", "10.0.0.1", layers.IPProtocolTCP, 40000, 80, "0000000000000001", "vnet0", 3, 7},
+	}
+	mitigation, err := m.MitigateElephant(event, xnfvTestEpoch)
+	if err != nil || mitigation.Reason != "elephant-flow" {
+		t.Fatalf("mitigation %+v, error %v", mitigation, err)
+	}
+	requests := controller.received()
+	if len(requests) != 1 || requests[0].Path != "/stats/flowentry/add" {
+		t.Fatalf("requests %+v", requests)
+	}
+	match := requests[0].Body["match"].(map[string]interface{})
+	if match["in_port"] != float64(3) || match["ipv4_src"] != "192.0.2.1" || match["ipv4_dst"] != "10.0.0.1" ||
+		match["ip_proto"] != float64(layers.IPProtocolTCP) || match["tcp_src"] != float64(40000) || match["tcp_dst"] != float64(80) {
+		t.Fatalf("match %v", match)
+	}
+}
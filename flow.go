@@ -0,0 +1,383 @@
+package main
+
+import (
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Sampled Flow Model
+// ****************************************************************************************************
+
+// TCP flag bits as they appear in the TCP header (and in the TCPFlags
+// field of sflow.SFlowIpv4Record / sflow.SFlowIpv6Record).
+const (
+	xnfvTCPFlagFIN uint8 = 0x01
+	xnfvTCPFlagSYN uint8 = 0x02
+	xnfvTCPFlagRST uint8 = 0x04
+	xnfvTCPFlagPSH uint8 = 0x08
+	xnfvTCPFlagACK uint8 = 0x10
+	xnfvTCPFlagURG uint8 = 0x20
+	xnfvTCPFlagECE uint8 = 0x40
+	xnfvTCPFlagCWR uint8 = 0x80
+)
+
+// XnfvFlowKey identifies a unidirectional flow. It is comparable so it
+// can be used directly as a map key by the analytics modules.
+type XnfvFlowKey struct {
+	SrcIP    string
+	DstIP    string
+	Protocol layers.IPProtocol
+	SrcPort  uint16
+	DstPort  uint16
+}
+
+// XnfvFlowSample is the collector's normalized view of one sampled packet.
+// It carries the header fields the analytics modules need together with
+// the sampling context (rate, interfaces) and the switch / port the
+// packet entered on, when that port is already known from counter samples.
+type XnfvFlowSample struct {
+	Timestamp       time.Time
+	AgentAddress    net.IP
+	SwitchDataPath  []byte
+	PortName        string
+	OfPort          uint32
+	InputInterface  uint32
+	OutputInterface uint32
+	SamplingRate    uint32
+	FrameLength     uint32
+
+	SrcMac     net.HardwareAddr
+	DstMac     net.HardwareAddr
+	VLAN       uint16
+	EtherType  layers.EthernetType
+	SrcIP      net.IP
+	DstIP      net.IP
+	IPProtocol layers.IPProtocol
+	SrcPort    uint16
+	DstPort    uint16
+	TCPFlags   uint8
+	ICMPType   uint8
+	VNI        uint32
+
+	// Fields taken from the extended switch, VLAN tunnel, MPLS and NAT
+	// flow records that accompany the sampled header
+	InVLAN              uint32
+	InVLANPriority      uint32
+	OutVLAN             uint32
+	OutVLANPriority     uint32
+	VLANStack           []sflow.SFlowVLANTag
+	MPLSNextHop         net.IP
+	MPLSInLabels        []uint32
+	MPLSOutLabels       []uint32
+	MPLSTunnelName      string
+	MPLSTunnelID        uint32
+	MPLSVCName          string
+	MPLSVCID            uint32
+	MPLSFTNDescr        string
+	MPLSFECPrefixLength uint32
+	NATSrcIP            net.IP
+	NATDstIP            net.IP
+
+	// Fields taken from the extended socket and TCP info records hsflowd
+	// attaches to the packets of the host's own sockets
+	SocketProtocol   uint32
+	SocketLocalIP    net.IP
+	SocketRemoteIP   net.IP
+	SocketLocalPort  uint32
+	SocketRemotePort uint32
+	TCPInfo          *sflow.SFlowExtendedTCPInfoFlowRecord
+
+	// Fields taken from the egress queue, transit delay and queue depth
+	// records of switch ASICs, the Has flags tell which were reported
+	EgressQueue     uint32
+	TransitDelayNs  uint32
+	QueueDepthBytes uint32
+	HasEgressQueue  bool
+	HasTransitDelay bool
+	HasQueueDepth   bool
+
+	// Header is the decoded sampled header the fields above were taken from
+	Header gopacket.Packet `json:"-"`
+}
+
+// newXnfvFlowSample builds an XnfvFlowSample from a decoded raw packet
+// header. When the header carries a VXLAN encapsulation the inner
+// (tenant) headers win over the outer ones and the VNI is recorded.
+func newXnfvFlowSample(header gopacket.Packet, samplingRate uint32, inputInterface uint32, outputInterface uint32, frameLength uint32) XnfvFlowSample {
+	s := XnfvFlowSample{
+		Timestamp:       time.Now(),
+		InputInterface:  inputInterface,
+		OutputInterface: outputInterface,
+		SamplingRate:    samplingRate,
+		FrameLength:     frameLength,
+		Header:          header,
+	}
+	if header == nil {
+		return s
+	}
+	for _, layer := range header.Layers() {
+		switch l := layer.(type) {
+		case *layers.Ethernet:
+			s.SrcMac, s.DstMac, s.EtherType = l.SrcMAC, l.DstMAC, l.EthernetType
+		case *layers.Dot11:
+			s.SrcMac, s.DstMac = l.Address2, l.Address1
+		case *layers.Dot1Q:
+			s.VLAN, s.EtherType = l.VLANIdentifier, l.Type
+		case *layers.IPv4:
+			s.SrcIP, s.DstIP, s.IPProtocol = l.SrcIP, l.DstIP, l.Protocol
+		case *layers.IPv6:
+			s.SrcIP, s.DstIP, s.IPProtocol = l.SrcIP, l.DstIP, l.NextHeader
+		case *layers.TCP:
+			s.SrcPort, s.DstPort = uint16(l.SrcPort), uint16(l.DstPort)
+			s.TCPFlags = tcpFlags(l)
+		case *layers.UDP:
+			s.SrcPort, s.DstPort = uint16(l.SrcPort), uint16(l.DstPort)
+		case *layers.ICMPv4:
+			s.ICMPType = l.TypeCode.Type()
+		case *layers.ICMPv6:
+			s.ICMPType = l.TypeCode.Type()
+		case *layers.VXLAN:
+			// everything decoded after this point belongs to the tenant frame
+			s.VNI, s.VLAN = l.VNI, 0
+			s.SrcPort, s.DstPort, s.TCPFlags = 0, 0, 0
+		}
+	}
+	if s.FrameLength == 0 {
+		s.FrameLength = uint32(len(header.Data()))
+	}
+	return s
+}
+
+// xnfvFlowSampleFromGeneric builds an XnfvFlowSample out of a flow
+// sample decoded by decodeFlowSample. The raw packet header is preferred;
+// agents that only export the Ethernet frame and IPv4 / IPv6 summary
+// records are normalized into the same fields. ok is false when the
+// sample has no record the flow model can be built from.
+func xnfvFlowSampleFromGeneric(sample sflow.SFlowFlowSample) (s XnfvFlowSample, ok bool) {
+	if rawPacket, isRaw := sample.RawPacket(); isRaw {
+		s = newXnfvFlowSample(rawPacket.Header, sample.SamplingRate, sample.InputInterface, sample.OutputInterface, rawPacket.FrameLength)
+		ok = true
+	} else {
+		s = newXnfvFlowSample(nil, sample.SamplingRate, sample.InputInterface, sample.OutputInterface, 0)
+		for _, record := range sample.Records {
+			if s.applySummaryRecord(record) {
+				ok = true
+			}
+		}
+	}
+	if !ok {
+		return s, false
+	}
+	for _, record := range sample.Records {
+		s.applyRecord(record)
+	}
+	return s, true
+}
+
+// applySummaryRecord copies the fields of an Ethernet frame, IPv4 or IPv6
+// record into the sample and reports whether the record was one of them.
+// For ICMP, agents report the type and code in the port fields; they are
+// moved to ICMPType so the flow key matches a sample decoded from a raw
+// header.
+func (s *XnfvFlowSample) applySummaryRecord(record sflow.SFlowRecord) bool {
+	switch r := record.(type) {
+	case sflow.SFlowEthernetFrameFlowRecord:
+		s.SrcMac, s.DstMac, s.EtherType = r.SrcMac, r.DstMac, layers.EthernetType(r.Type)
+		s.FrameLength = r.FrameLength
+	case sflow.SFlowIpv4FlowRecord:
+		s.applyIPSummary(r.IPSrc, r.IPDst, r.Protocol, r.PortSrc, r.PortDst, r.TCPFlags, r.Length)
+		if s.EtherType == 0 {
+			s.EtherType = layers.EthernetTypeIPv4
+		}
+	case sflow.SFlowIpv6FlowRecord:
+		s.applyIPSummary(r.IPSrc, r.IPDst, r.Protocol, r.PortSrc, r.PortDst, r.TCPFlags, r.Length)
+		if s.EtherType == 0 {
+			s.EtherType = layers.EthernetTypeIPv6
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *XnfvFlowSample) applyIPSummary(srcIP net.IP, dstIP net.IP, protocol uint32, srcPort uint32, dstPort uint32, tcpFlags uint32, length uint32) {
+	s.SrcIP, s.DstIP, s.IPProtocol = srcIP, dstIP, layers.IPProtocol(protocol)
+	switch s.IPProtocol {
+	case layers.IPProtocolICMPv4, layers.IPProtocolICMPv6:
+		s.ICMPType = uint8(srcPort)
+	case layers.IPProtocolTCP:
+		s.SrcPort, s.DstPort, s.TCPFlags = uint16(srcPort), uint16(dstPort), uint8(tcpFlags)
+	default:
+		s.SrcPort, s.DstPort = uint16(srcPort), uint16(dstPort)
+	}
+	// the IP length is the best estimate when no Ethernet frame record came first
+	if s.FrameLength == 0 {
+		s.FrameLength = length
+	}
+}
+
+// applyRecord copies the fields of an extended flow record into the sample
+func (s *XnfvFlowSample) applyRecord(record sflow.SFlowRecord) {
+	switch r := record.(type) {
+	case sflow.SFlowExtendedSwitchFlowRecord:
+		s.InVLAN, s.InVLANPriority = r.IncomingVLAN, r.IncomingVLANPriority
+		s.OutVLAN, s.OutVLANPriority = r.OutgoingVLAN, r.OutgoingVLANPriority
+	case sflow.SFlowExtendedVLANTunnelFlowRecord:
+		s.VLANStack = r.Stack
+	case sflow.SFlowExtendedMPLSFlowRecord:
+		s.MPLSNextHop, s.MPLSInLabels, s.MPLSOutLabels = r.NextHop, r.InLabelStack, r.OutLabelStack
+	case sflow.SFlowExtendedMPLSTunnelFlowRecord:
+		s.MPLSTunnelName, s.MPLSTunnelID = r.TunnelLSPName, r.TunnelID
+	case sflow.SFlowExtendedMPLSVCFlowRecord:
+		s.MPLSVCName, s.MPLSVCID = r.VCInstanceName, r.VLLVCID
+	case sflow.SFlowExtendedMPLSFECFlowRecord:
+		s.MPLSFTNDescr = r.FTNDescr
+	case sflow.SFlowExtendedMPLSLVPFECFlowRecord:
+		s.MPLSFECPrefixLength = r.FECAddrPrefixLength
+	case sflow.SFlowExtendedNATFlowRecord:
+		s.NATSrcIP, s.NATDstIP = r.SourceAddress, r.DestinationAddress
+	case sflow.SFlowExtendedVniIngressRecord:
+		s.VNI = r.VNI
+	case sflow.SFlowExtendedVniEgressRecord:
+		if s.VNI == 0 {
+			s.VNI = r.VNI
+		}
+	case sflow.SFlowExtendedSocketFlowRecord:
+		s.SocketProtocol, s.SocketLocalIP, s.SocketRemoteIP = r.Protocol, r.LocalIP, r.RemoteIP
+		s.SocketLocalPort, s.SocketRemotePort = r.LocalPort, r.RemotePort
+	case sflow.SFlowExtendedTCPInfoFlowRecord:
+		tcpInfo := r
+		s.TCPInfo = &tcpInfo
+	case sflow.SFlowExtendedEgressQueueRecord:
+		s.EgressQueue, s.HasEgressQueue = r.Queue, true
+	case sflow.SFlowExtendedTransitRecord:
+		s.TransitDelayNs, s.HasTransitDelay = r.Delay, true
+	case sflow.SFlowExtendedQueueRecord:
+		s.QueueDepthBytes, s.HasQueueDepth = r.Depth, true
+	}
+}
+
+func tcpFlags(tcp *layers.TCP) uint8 {
+	var flags uint8
+	if tcp.FIN {
+		flags |= xnfvTCPFlagFIN
+	}
+	if tcp.SYN {
+		flags |= xnfvTCPFlagSYN
+	}
+	if tcp.RST {
+		flags |= xnfvTCPFlagRST
+	}
+	if tcp.PSH {
+		flags |= xnfvTCPFlagPSH
+	}
+	if tcp.ACK {
+		flags |= xnfvTCPFlagACK
+	}
+	if tcp.URG {
+		flags |= xnfvTCPFlagURG
+	}
+	if tcp.ECE {
+		flags |= xnfvTCPFlagECE
+	}
+	if tcp.CWR {
+		flags |= xnfvTCPFlagCWR
+	}
+	return flags
+}
+
+// Key returns the flow key of the sampled packet
+func (s XnfvFlowSample) Key() XnfvFlowKey {
+	return XnfvFlowKey{
+		SrcIP:    ipString(s.SrcIP),
+		DstIP:    ipString(s.DstIP),
+		Protocol: s.IPProtocol,
+		SrcPort:  s.SrcPort,
+		DstPort:  s.DstPort,
+	}
+}
+
+// EstimatedPackets scales the single sampled packet by the sampling rate
+func (s XnfvFlowSample) EstimatedPackets() float64 {
+	if s.SamplingRate == 0 {
+		return 1
+	}
+	return float64(s.SamplingRate)
+}
+
+// EstimatedBytes scales the sampled frame length by the sampling rate
+func (s XnfvFlowSample) EstimatedBytes() float64 {
+	return float64(s.FrameLength) * s.EstimatedPackets()
+}
+
+// SwitchDataPathString returns the datapath ID as a hex string
+func (s XnfvFlowSample) SwitchDataPathString() string {
+	return dataPathString(s.SwitchDataPath)
+}
+
+// resolveSwitchPort fills in the switch, port name and OpenFlow port of
+// the sample from the inventory built out of counter samples.
+func (s *XnfvFlowSample) resolveSwitchPort(xnfvAllSwitches *XnfvAllSwitches) {
+	if xnfvSwitch, port := xnfvAllSwitches.lookupSwitchPort(s.AgentAddress, s.InputInterface); port != nil {
+		s.SwitchDataPath = xnfvSwitch.switchDataPath
+		s.PortName = port.interfacePortName
+		s.OfPort = port.ofPort()
+	}
+}
+
+// lookupSwitchPort finds the switch port whose counter samples were
+// reported by the agent with the given interface index. ifIndexes are
+// only unique within an agent, the same index names different ports on
+// different hosts.
+func (xas *XnfvAllSwitches) lookupSwitchPort(agent net.IP, ifIndex uint32) (*XnfSwitchSflow, *XnfvSwitchPort) {
+	if agent == nil {
+		return nil, nil
+	}
+	for j := 0; j < len(xas.allAvailableSwitches); j++ {
+		for i := 0; i < len(xas.allAvailableSwitches[j].switchPortsStatistics); i++ {
+			port := &xas.allAvailableSwitches[j].switchPortsStatistics[i]
+			if port.interfacePortIndex == sflow.SFlowSourceValue(ifIndex) && port.interfaceSflowDatagram.AgentAddress.Equal(agent) {
+				return &xas.allAvailableSwitches[j], port
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ofPort returns the OpenFlow port number reported next to the port name
+// in the counter sample that created this port, or 0 when unknown.
+func (p *XnfvSwitchPort) ofPort() uint32 {
+	for _, counterSample := range p.interfaceSflowDatagram.CounterSamples {
+		if counterSample.SourceIDIndex != p.interfacePortIndex {
+			continue
+		}
+		for _, record := range counterSample.Records {
+			if ofPortCounter, ok := record.(sflow.SFlowOFPortCounters); ok {
+				return ofPortCounter.OfPort
+			}
+		}
+	}
+	return 0
+}
+
+func dataPathString(dataPath []byte) string {
+	return hex.EncodeToString(dataPath)
+}
+
+func parseDataPath(dataPath string) ([]byte, error) {
+	return hex.DecodeString(dataPath)
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+func xnfvTestSwitchPort(agent string, name string, ifIndex uint32) XnfvSwitchPort {
+	return XnfvSwitchPort{
+		interfacePortName:      name,
+		interfacePortIndex:     sflow.SFlowSourceValue(ifIndex),
+		interfaceSflowDatagram: GenericSFlowDatagram{AgentAddress: net.ParseIP(agent)},
+	}
+}
+
+func TestXnfvLookupSwitchPort(t *testing.T) {
+	xnfvAllSwitches := XnfvAllSwitches{allAvailableSwitches: []XnfSwitchSflow{
+		{switchDataPath: []byte{0, 0, 0, 0, 0, 0, 0, 1}, switchPortsStatistics: []XnfvSwitchPort{
+			xnfvTestSwitchPort("192.0.2.1", "vnet0", 7),
+		}},
+		{switchDataPath: []byte{0, 0, 0, 0, 0, 0, 0, 2}, switchPortsStatistics: []XnfvSwitchPort{
+			xnfvTestSwitchPort("192.0.2.2", "vnet5", 7),
+			xnfvTestSwitchPort("192.0.2.2", "vnet6", 8),
+		}},
+	}}
+	tests := []struct {
+		agent    string
+		ifIndex  uint32
+		dataPath string
+		port     string
+	}{
+		{"192.0.2.1", 7, "0000000000000001", "vnet0"},
+		{"192.0.2.2", 7, "0000000000000002", "vnet5"},
+		{"192.0.2.2", 8, "0000000000000002", "vnet6"},
+		{"192.0.2.1", 8, "", ""},
+		{"192.0.2.3", 7, "", ""},
+		{"", 7, "", ""},
+	}
+	for _, test := range tests {
+		s := XnfvFlowSample{AgentAddress: net.ParseIP(test.agent), InputInterface: test.ifIndex}
+		s.resolveSwitchPort(&xnfvAllSwitches)
+		if s.SwitchDataPathString() != test.dataPath || s.PortName != test.port {
+			t.Errorf("%s ifIndex %d resolved to %q %q, want %q %q", test.agent, test.ifIndex, s.SwitchDataPathString(), s.PortName, test.dataPath, test.port)
+		}
+	}
+}
+
+// Agents without the raw header export summary records, the sample built
+// from them matches one decoded from the header
+func TestXnfvFlowSampleFromSummaryRecords(t *testing.T) {
+	src, dst := net.ParseIP("10.0.0.1").To4(), net.ParseIP("10.0.0.2").To4()
+	src6, dst6 := net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")
+	ipv4 := func(protocol layers.IPProtocol, srcPort, dstPort, tcpFlags uint32) sflow.SFlowIpv4FlowRecord {
+		return sflow.SFlowIpv4FlowRecord{SFlowIpv4Record: sflow.SFlowIpv4Record{
+			Length: 100, Protocol: uint32(protocol), IPSrc: src, IPDst: dst, PortSrc: srcPort, PortDst: dstPort, TCPFlags: tcpFlags}}
+	}
+	ipv6 := func(protocol layers.IPProtocol, srcPort, dstPort uint32) sflow.SFlowIpv6FlowRecord {
+		return sflow.SFlowIpv6FlowRecord{SFlowIpv6Record: sflow.SFlowIpv6Record{
+			Length: 120, Protocol: uint32(protocol), IPSrc: src6, IPDst: dst6, PortSrc: srcPort, PortDst: dstPort}}
+	}
+	ethernet := sflow.SFlowEthernetFrameFlowRecord{FrameLength: 1518, SrcMac: xnfvTestMacA, DstMac: xnfvTestMacB, Type: uint32(layers.EthernetTypeDot1Q)}
+	tests := []struct {
+		name      string
+		records   []sflow.SFlowRecord
+		etherType layers.EthernetType
+		srcPort   uint16
+		dstPort   uint16
+		tcpFlags  uint8
+		icmpType  uint8
+		length    uint32
+	}{
+		{"tcp", []sflow.SFlowRecord{ipv4(layers.IPProtocolTCP, 40000, 80, 0x12)}, layers.EthernetTypeIPv4, 40000, 80, 0x12, 0, 100},
+		{"udp", []sflow.SFlowRecord{ipv6(layers.IPProtocolUDP, 53, 40000)}, layers.EthernetTypeIPv6, 53, 40000, 0, 0, 120},
+		// the type (echo request) and code are in the port fields
+		{"icmp", []sflow.SFlowRecord{ipv4(layers.IPProtocolICMPv4, 8, 0, 0)}, layers.EthernetTypeIPv4, 0, 0, 0, 8, 100},
+		{"icmpv6", []sflow.SFlowRecord{ipv6(layers.IPProtocolICMPv6, 135, 0)}, layers.EthernetTypeIPv6, 0, 0, 0, 135, 120},
+		// the frame record keeps its EtherType and length
+		{"ethernet first", []sflow.SFlowRecord{ethernet, ipv4(layers.IPProtocolUDP, 1, 2, 0)}, layers.EthernetTypeDot1Q, 1, 2, 0, 0, 1518},
+		{"ethernet last", []sflow.SFlowRecord{ipv4(layers.IPProtocolUDP, 1, 2, 0), ethernet}, layers.EthernetTypeDot1Q, 1, 2, 0, 0, 1518},
+	}
+	for _, test := range tests {
+		s, ok := xnfvFlowSampleFromGeneric(sflow.SFlowFlowSample{SamplingRate: 100, InputInterface: 1, Records: test.records})
+		if !ok {
+			t.Errorf("%s: no sample", test.name)
+			continue
+		}
+		if s.EtherType != test.etherType || s.SrcPort != test.srcPort || s.DstPort != test.dstPort ||
+			s.TCPFlags != test.tcpFlags || s.ICMPType != test.icmpType || s.FrameLength != test.length || s.SamplingRate != 100 {
+			t.Errorf("%s: sample %+v", test.name, s)
+		}
+		if s.SrcIP == nil || s.DstIP == nil || s.IPProtocol == 0 {
+			t.Errorf("%s: addresses %v %v %v", test.name, s.SrcIP, s.DstIP, s.IPProtocol)
+		}
+	}
+
+	// the extended records alone do not make a sample
+	if _, ok := xnfvFlowSampleFromGeneric(sflow.SFlowFlowSample{Records: []sflow.SFlowRecord{sflow.SFlowExtendedSwitchFlowRecord{}}}); ok {
+		t.Fatal("a sample was built without a summary record")
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/nephilimboy/xnfv-SflowCollector
+
+go 1.16
+
+require github.com/google/gopacket v1.1.19
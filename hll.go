@@ -0,0 +1,92 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// ****************************************************************************************************
+//  HyperLogLog Cardinality Sketch
+// ****************************************************************************************************
+
+// xnfvHLL is a small HyperLogLog sketch used to estimate the number of
+// distinct values (destination IPs, ports, ...) seen by a host without
+// keeping the values themselves. With precision p it uses 2^p one-byte
+// registers and has a standard error of about 1.04 / sqrt(2^p).
+type xnfvHLL struct {
+	precision uint8
+	registers []uint8
+}
+
+func newXnfvHLL(precision uint8) *xnfvHLL {
+	if precision < 4 {
+		precision = 4
+	} else if precision > 16 {
+		precision = 16
+	}
+	return &xnfvHLL{precision: precision, registers: make([]uint8, 1<<precision)}
+}
+
+// Add inserts a value into the sketch
+func (h *xnfvHLL) Add(value []byte) {
+	hash := hllHash(value)
+	index := hash >> (64 - h.precision)
+	// rank of the first set bit in the remaining 64-p bits
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// Estimate returns the estimated number of distinct values added
+func (h *xnfvHLL) Estimate() float64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	// small range correction: linear counting is more accurate here
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return estimate
+}
+
+// Reset clears the sketch
+func (h *xnfvHLL) Reset() {
+	for i := range h.registers {
+		h.registers[i] = 0
+	}
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// hllHash is FNV-1a followed by the splitmix64 finalizer, which spreads
+// the short, similar keys we insert (IPs, ports) over all 64 bits.
+func hllHash(value []byte) uint64 {
+	f := fnv.New64a()
+	f.Write(value)
+	x := f.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
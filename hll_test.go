@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestXnfvHLLEstimate(t *testing.T) {
+	tests := []struct {
+		precision uint8
+		distinct  int
+	}{
+		{10, 0},
+		{10, 1},
+		{10, 8},
+		{10, 100},
+		{10, 5000},
+		{10, 100000},
+		{14, 100000},
+		{4, 1000},
+	}
+	for _, test := range tests {
+		h := newXnfvHLL(test.precision)
+		value := make([]byte, 4)
+		for i := 0; i < test.distinct; i++ {
+			binary.BigEndian.PutUint32(value, uint32(i))
+			// duplicates do not count
+			h.Add(value)
+			h.Add(value)
+		}
+		estimate := h.Estimate()
+		// three standard errors
+		tolerance := 3 * 1.04 / math.Sqrt(float64(len(h.registers))) * float64(test.distinct)
+		if math.Abs(estimate-float64(test.distinct)) > math.Max(tolerance, 0.5) {
+			t.Errorf("precision %d, %d distinct values: estimate %.1f", test.precision, test.distinct, estimate)
+		}
+	}
+}
+
+func TestXnfvHLLPrecision(t *testing.T) {
+	for precision, registers := range map[uint8]int{0: 16, 4: 16, 10: 1024, 16: 65536, 20: 65536} {
+		if h := newXnfvHLL(precision); len(h.registers) != registers {
+			t.Errorf("precision %d: %d registers, want %d", precision, len(h.registers), registers)
+		}
+	}
+}
+
+func TestXnfvHLLReset(t *testing.T) {
+	h := newXnfvHLL(10)
+	h.Add([]byte("10.0.0.1"))
+	h.Reset()
+	if estimate := h.Estimate(); estimate != 0 {
+		t.Errorf("estimate after reset %.1f", estimate)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ****************************************************************************************************
+//  Host Location Tracking
+// ****************************************************************************************************
+
+type XnfvHostTableConfig struct {
+	// IgnorePortPrefixes lists port names that are not host facing (OVS
+	// patch and tunnel ports, uplinks) and must not be learned from
+	IgnorePortPrefixes []string
+	// MoveConfirmations is how many samples must place a MAC on a new port
+	// before a move is reported, so one stray sample on a trunk port is not
+	// taken for a VM migration
+	MoveConfirmations int
+	// MaxAge removes hosts that have not been sampled for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvHostTableConfig() XnfvHostTableConfig {
+	return XnfvHostTableConfig{
+		IgnorePortPrefixes: []string{"patch-", "vxlan", "gre", "geneve", "int-", "phy-"},
+		MoveConfirmations:  3,
+		MaxAge:             time.Hour,
+	}
+}
+
+// XnfvHostPort is the switch port a host was seen on
+type XnfvHostPort struct {
+	SwitchDataPath string `json:"switchDataPath"`
+	PortName       string `json:"portName"`
+	OfPort         uint32 `json:"ofPort"`
+	InputInterface uint32 `json:"inputInterface"`
+}
+
+// XnfvHostLocation is one entry of the host location table
+type XnfvHostLocation struct {
+	Mac       string       `json:"mac"`
+	Port      XnfvHostPort `json:"port"`
+	VLAN      uint16       `json:"vlan"`
+	VNI       uint32       `json:"vni"`
+	IPs       []string     `json:"ips"`
+	FirstSeen time.Time    `json:"firstSeen"`
+	LastSeen  time.Time    `json:"lastSeen"`
+	Moves     int          `json:"moves"`
+}
+
+// XnfvHostMovedEvent is emitted when a MAC is confirmed on a new port
+type XnfvHostMovedEvent struct {
+	Type string       `json:"type"`
+	Mac  string       `json:"mac"`
+	VNI  uint32       `json:"vni"`
+	IPs  []string     `json:"ips"`
+	From XnfvHostPort `json:"from"`
+	To   XnfvHostPort `json:"to"`
+	Time time.Time    `json:"time"`
+}
+
+type xnfvHostEntry struct {
+	location      XnfvHostLocation
+	ips           map[string]bool
+	candidate     XnfvHostPort
+	candidateSeen int
+}
+
+// XnfvHostTable learns where source MACs live (MAC -> switch, port,
+// VLAN/VNI, IPs) from the Ethernet headers of flow samples. It is safe
+// for concurrent use so it can be queried while samples are processed.
+type XnfvHostTable struct {
+	config XnfvHostTableConfig
+	mutex  sync.RWMutex
+	hosts  map[string]*xnfvHostEntry
+}
+
+func NewXnfvHostTable(config XnfvHostTableConfig) *XnfvHostTable {
+	return &XnfvHostTable{config: config, hosts: map[string]*xnfvHostEntry{}}
+}
+
+func (t *XnfvHostTable) ignorePort(portName string) bool {
+	for _, prefix := range t.config.IgnorePortPrefixes {
+		if strings.HasPrefix(portName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Observe learns the source MAC of a flow sample. Samples whose ingress
+// port is not known yet, or is not host facing, are ignored.
+func (t *XnfvHostTable) Observe(s XnfvFlowSample) *XnfvHostMovedEvent {
+	if len(s.SrcMac) == 0 || len(s.SwitchDataPath) == 0 || t.ignorePort(s.PortName) {
+		return nil
+	}
+	// multicast / broadcast source MACs are never a host
+	if s.SrcMac[0]&0x01 != 0 {
+		return nil
+	}
+	mac := s.SrcMac.String()
+	port := XnfvHostPort{s.SwitchDataPathString(), s.PortName, s.OfPort, s.InputInterface}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entry, ok := t.hosts[mac]
+	if !ok {
+		entry = &xnfvHostEntry{
+			location: XnfvHostLocation{Mac: mac, Port: port, FirstSeen: s.Timestamp},
+			ips:      map[string]bool{},
+		}
+		t.hosts[mac] = entry
+	}
+	entry.location.LastSeen = s.Timestamp
+	entry.location.VLAN, entry.location.VNI = s.VLAN, s.VNI
+	if s.SrcIP != nil && !s.SrcIP.IsUnspecified() && !entry.ips[s.SrcIP.String()] {
+		entry.ips[s.SrcIP.String()] = true
+		entry.location.IPs = append(entry.location.IPs, s.SrcIP.String())
+	}
+
+	if entry.location.Port.SwitchDataPath == port.SwitchDataPath && entry.location.Port.PortName == port.PortName {
+		entry.candidateSeen = 0
+		return nil
+	}
+	if entry.candidate != port {
+		entry.candidate, entry.candidateSeen = port, 0
+	}
+	entry.candidateSeen++
+	if entry.candidateSeen < t.config.MoveConfirmations {
+		return nil
+	}
+
+	event := &XnfvHostMovedEvent{
+		Type: "host-moved",
+		Mac:  mac,
+		VNI:  s.VNI,
+		IPs:  append([]string(nil), entry.location.IPs...),
+		From: entry.location.Port,
+		To:   port,
+		Time: s.Timestamp,
+	}
+	entry.location.Port = port
+	entry.location.Moves++
+	entry.candidateSeen = 0
+	return event
+}
+
+// AgeOut removes the hosts not seen since MaxAge before now
+func (t *XnfvHostTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for mac, entry := range t.hosts {
+		if now.Sub(entry.location.LastSeen) > t.config.MaxAge {
+			delete(t.hosts, mac)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Host Table Query API
+// ****************************************************************************************************
+
+func (t *XnfvHostTable) copyLocation(entry *xnfvHostEntry) XnfvHostLocation {
+	location := entry.location
+	location.IPs = append([]string(nil), entry.location.IPs...)
+	return location
+}
+
+// Lookup returns the location of a MAC address
+func (t *XnfvHostTable) Lookup(mac string) (XnfvHostLocation, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if entry, ok := t.hosts[strings.ToLower(mac)]; ok {
+		return t.copyLocation(entry), true
+	}
+	return XnfvHostLocation{}, false
+}
+
+// Find returns the hosts matching every non empty filter: an IP the host
+// used, the switch datapath (hex) and the port name.
+func (t *XnfvHostTable) Find(ip string, switchDataPath string, portName string) []XnfvHostLocation {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var locations []XnfvHostLocation
+	for _, entry := range t.hosts {
+		if ip != "" && !entry.ips[ip] {
+			continue
+		}
+		if switchDataPath != "" && entry.location.Port.SwitchDataPath != switchDataPath {
+			continue
+		}
+		if portName != "" && entry.location.Port.PortName != portName {
+			continue
+		}
+		locations = append(locations, t.copyLocation(entry))
+	}
+	sort.Slice(locations, func(i, j int) bool { return locations[i].Mac < locations[j].Mac })
+	return locations
+}
+
+// All returns every known host
+func (t *XnfvHostTable) All() []XnfvHostLocation {
+	return t.Find("", "", "")
+}
+
+// ServeHTTP answers GET /hosts[?mac=|ip=|switch=|port=] with the matching
+// host locations as JSON
+func (t *XnfvHostTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var result interface{}
+	if mac := query.Get("mac"); mac != "" {
+		location, ok := t.Lookup(mac)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		result = location
+	} else {
+		result = t.Find(query.Get("ip"), query.Get("switch"), query.Get("port"))
+	}
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
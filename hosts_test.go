@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// xnfvTestHostFlowSample is a sample of 10.0.0.5 sent by mac and entering
+// the switch dataPath on portName
+func xnfvTestHostFlowSample(at time.Duration, mac net.HardwareAddr, dataPath byte, portName string) XnfvFlowSample {
+	return XnfvFlowSample{
+		Timestamp:      xnfvTestEpoch.Add(at),
+		SrcMac:         mac,
+		SrcIP:          net.ParseIP("10.0.0.5"),
+		SwitchDataPath: []byte{0, 0, 0, 0, 0, 0, 0, dataPath},
+		PortName:       portName,
+		OfPort:         uint32(len(portName)),
+		InputInterface: 7,
+	}
+}
+
+func TestXnfvHostTableObserve(t *testing.T) {
+	host := net.HardwareAddr{2, 0, 0, 0, 0, 5}
+	tests := []struct {
+		name    string
+		samples []XnfvFlowSample
+		events  int
+		// the port the host is located on, "" when it is not learned
+		portName string
+		moves    int
+	}{
+		{"learned", []XnfvFlowSample{xnfvTestHostFlowSample(0, host, 1, "vnet0")}, 0, "vnet0", 0},
+		{"move confirmed", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(2*time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(3*time.Second, host, 2, "vnet3"),
+		}, 1, "vnet3", 1},
+		{"move not confirmed", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(2*time.Second, host, 2, "vnet3"),
+		}, 0, "vnet0", 0},
+		// a sample on the old port starts the confirmations over
+		{"stray samples", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(2*time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(3*time.Second, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(4*time.Second, host, 2, "vnet3"),
+		}, 0, "vnet0", 0},
+		// so does a sample on a third port
+		{"changing candidate", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(2*time.Second, host, 2, "vnet3"),
+			xnfvTestHostFlowSample(3*time.Second, host, 2, "vnet4"),
+			xnfvTestHostFlowSample(4*time.Second, host, 2, "vnet3"),
+		}, 0, "vnet0", 0},
+		{"uplink ports", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "patch-int"),
+			xnfvTestHostFlowSample(0, host, 1, "vxlan0"),
+			xnfvTestHostFlowSample(0, host, 1, "gre1"),
+			xnfvTestHostFlowSample(0, host, 1, "phy-br-eth1"),
+		}, 0, "", 0},
+		// the host seen through an uplink did not move
+		{"uplink after the host port", []XnfvFlowSample{
+			xnfvTestHostFlowSample(0, host, 1, "vnet0"),
+			xnfvTestHostFlowSample(time.Second, host, 2, "vxlan0"),
+			xnfvTestHostFlowSample(2*time.Second, host, 2, "vxlan0"),
+			xnfvTestHostFlowSample(3*time.Second, host, 2, "vxlan0"),
+		}, 0, "vnet0", 0},
+		{"broadcast source", []XnfvFlowSample{xnfvTestHostFlowSample(0, net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1, "vnet0")}, 0, "", 0},
+		{"multicast source", []XnfvFlowSample{xnfvTestHostFlowSample(0, net.HardwareAddr{0x01, 0x00, 0x5e, 0, 0, 1}, 1, "vnet0")}, 0, "", 0},
+		{"port not in the inventory", []XnfvFlowSample{{Timestamp: xnfvTestEpoch, SrcMac: host, PortName: "vnet0"}}, 0, "", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := NewXnfvHostTable(DefaultXnfvHostTableConfig())
+			var events []*XnfvHostMovedEvent
+			for _, s := range test.samples {
+				if event := table.Observe(s); event != nil {
+					events = append(events, event)
+				}
+			}
+			if len(events) != test.events {
+				t.Fatalf("events %+v, want %d", events, test.events)
+			}
+			hosts := table.All()
+			if test.portName == "" {
+				if len(hosts) != 0 {
+					t.Fatalf("hosts %+v", hosts)
+				}
+				return
+			}
+			if len(hosts) != 1 || hosts[0].Port.PortName != test.portName || hosts[0].Moves != test.moves {
+				t.Fatalf("hosts %+v, want on %s after %d moves", hosts, test.portName, test.moves)
+			}
+			if len(hosts[0].IPs) != 1 || hosts[0].IPs[0] != "10.0.0.5" {
+				t.Errorf("ips %v", hosts[0].IPs)
+			}
+			for _, event := range events {
+				if event.Type != "host-moved" || event.Mac != "02:00:00:00:00:05" || event.From.PortName != "vnet0" ||
+					event.To != (XnfvHostPort{"0000000000000002", test.portName, uint32(len(test.portName)), 7}) {
+					t.Errorf("event %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestXnfvHostTableAgeOut(t *testing.T) {
+	table := NewXnfvHostTable(DefaultXnfvHostTableConfig())
+	table.Observe(xnfvTestHostFlowSample(0, net.HardwareAddr{2, 0, 0, 0, 0, 5}, 1, "vnet0"))
+	table.Observe(xnfvTestHostFlowSample(30*time.Minute, net.HardwareAddr{2, 0, 0, 0, 0, 6}, 1, "vnet1"))
+
+	table.AgeOut(xnfvTestEpoch.Add(time.Hour))
+	if hosts := table.All(); len(hosts) != 2 {
+		t.Fatalf("hosts %+v", hosts)
+	}
+	table.AgeOut(xnfvTestEpoch.Add(time.Hour + time.Second))
+	if _, ok := table.Lookup("02:00:00:00:00:05"); ok {
+		t.Fatalf("02:00:00:00:00:05 was not aged out")
+	}
+	if location, ok := table.Lookup("02:00:00:00:00:06"); !ok || location.Port.PortName != "vnet1" {
+		t.Fatalf("location %+v", location)
+	}
+	table.AgeOut(xnfvTestEpoch.Add(90*time.Minute + time.Second))
+	if hosts := table.All(); len(hosts) != 0 {
+		t.Fatalf("hosts after AgeOut %+v", hosts)
+	}
+}
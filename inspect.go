@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Datagram Inspection
+// ****************************************************************************************************
+
+// runXnfvInspect runs the inspect subcommand: it prints the annotated
+// decode of the sFlow datagrams of a capture file, or of the hex payload
+// read from stdin without a file (or with "-"). Only the UDP datagrams sent
+// to the sFlow port (-port, 0 for any) of the capture are inspected
+//
+//	xnfv-sflow inspect [-port 6343] capture.pcap
+//	echo 00000005 00000001 ... | xnfv-sflow inspect
+func runXnfvInspect(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	port := flags.Uint("port", 6343, "UDP port the sFlow datagrams are sent to, 0 for any")
+	flags.Parse(args)
+	args = flags.Args()
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if len(args) == 0 || args[0] == "-" {
+		payload, err := readXnfvHexPayload(os.Stdin)
+		if err != nil {
+			return err
+		}
+		writeXnfvInspection(out, payload)
+		return nil
+	}
+
+	packetSource, closeCapture, err := openXnfvCaptureFile(args[0])
+	if err != nil {
+		return err
+	}
+	defer closeCapture()
+	packets := 0
+	for packet := range packetSource.Packets() {
+		packets++
+		udp, ok := packet.Layer(layers.LayerTypeUDP).(*layers.UDP)
+		if !ok || packet.NetworkLayer() == nil || (*port != 0 && uint(udp.DstPort) != *port) {
+			continue
+		}
+		flow := packet.NetworkLayer().NetworkFlow()
+		fmt.Fprintf(out, "packet %d  %s:%d -> %s:%d  %s\n", packets, flow.Src(), udp.SrcPort, flow.Dst(), udp.DstPort,
+			packet.Metadata().Timestamp.Format("2006-01-02 15:04:05.000000"))
+		writeXnfvInspection(out, udp.Payload)
+		fmt.Fprintln(out)
+	}
+	return nil
+}
+
+// readXnfvHexPayload reads a hex dump, the spaces, new lines and the
+// ":" / "-" separators between the bytes are ignored, as is a "0x" prefix
+func readXnfvHexPayload(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', ':', '-':
+			return -1
+		}
+		return r
+	}, text)
+	return hex.DecodeString(text)
+}
+
+// writeXnfvInspection prints the fields of a datagram with their offset,
+// the samples and the records with their offset, length, enterprise and
+// format, the fields of the records with their offset, and what Decode
+// skips. The records whose fields are not all annotated are also printed
+// the sflowtool way
+func writeXnfvInspection(w *bufio.Writer, payload []byte) {
+	inspection := sflow.Inspect(payload)
+	fmt.Fprintf(w, "datagram, %d bytes\n", inspection.Length)
+	writeXnfvInspectedFields(w, "  ", inspection.Fields)
+	for i, s := range inspection.Samples {
+		fmt.Fprintf(w, "  %04x  sample %d: %d:%d %s, %d bytes\n", s.Offset, i+1, s.EnterpriseID, s.Format, sflow.SampleTypeName(s.Format), s.Length)
+		writeXnfvInspectedFields(w, "    ", s.Fields)
+		for j, r := range s.Records {
+			description := r.Description()
+			if description == "" {
+				description = "unknown"
+			}
+			fmt.Fprintf(w, "    %04x  record %d: %d:%d %s, %d bytes\n", r.Offset, j+1, r.EnterpriseID, r.Format, description, r.Length)
+			writeXnfvInspectedFields(w, "          ", r.Fields)
+			t := xnfvSFlowToolWriter{w: w, indent: "          "}
+			switch {
+			case r.Err != nil:
+				fmt.Fprintf(w, "          FAILED: %v\n", r.Err)
+				if end := r.Offset + r.Length; end <= len(payload) {
+					fmt.Fprintf(w, "          bytes %s\n", sflowToolHex(payload[r.Offset:end]))
+				}
+			case xnfvInspectedFieldsEnd(r.Fields) >= r.Offset+r.Length-r.Unread:
+				// every field is annotated
+			case r.Counter:
+				t.counterRecord(r.Record)
+			default:
+				t.flowRecord(r.Record)
+				if raw, ok := r.Record.(sflow.SFlowRawPacketFlowRecord); ok {
+					writeXnfvInspectedHeader(w, raw.ParsedHeader)
+				}
+			}
+			if r.Unread > 0 {
+				fmt.Fprintf(w, "          WARNING: the decoder left %d bytes of the record\n", r.Unread)
+			}
+		}
+		switch {
+		case s.Skipped:
+			fmt.Fprintf(w, "    SKIPPED: %v\n", s.Err)
+		case s.Err != nil:
+			fmt.Fprintf(w, "    DROPPED: %v\n", s.Err)
+		}
+	}
+	if inspection.Err != nil {
+		fmt.Fprintf(w, "  ERROR: %v\n", inspection.Err)
+	}
+}
+
+// xnfvInspectedFieldsEnd is the offset after the last field
+func xnfvInspectedFieldsEnd(fields []sflow.InspectedField) int {
+	if len(fields) == 0 {
+		return 0
+	}
+	last := fields[len(fields)-1]
+	return last.Offset + last.Length
+}
+
+func writeXnfvInspectedFields(w *bufio.Writer, indent string, fields []sflow.InspectedField) {
+	for _, field := range fields {
+		value := field.Value
+		if address, ok := value.([]byte); ok {
+			value = net.IP(address)
+		}
+		fmt.Fprintf(w, "%s%04x  %-24s %v\n", indent, field.Offset, field.Name, value)
+	}
+}
+
+// writeXnfvInspectedHeader names the protocol and the layers of a sampled
+// header, the headers gopacket could not decode entirely are flagged
+func writeXnfvInspectedHeader(w *bufio.Writer, header sflow.SFlowRawHeader) {
+	fmt.Fprintf(w, "          header protocol %s", header.Protocol)
+	if !header.Supported {
+		fmt.Fprintf(w, " (not decoded)\n")
+		return
+	}
+	var names []string
+	if header.Packet != nil {
+		for _, layer := range header.Packet.Layers() {
+			names = append(names, layer.LayerType().String())
+		}
+		if failure := header.Packet.ErrorLayer(); failure != nil {
+			names = append(names, fmt.Sprintf("error: %v", failure.Error()))
+		}
+	}
+	fmt.Fprintf(w, ", layers %s\n", strings.Join(names, " / "))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteXnfvInspection(t *testing.T) {
+	payload, err := readXnfvHexPayload(strings.NewReader(`0x00000005 00000001 c0000201 00000000 00000001 000003e8 00000001
+		00000001 00000038 00000001 0000000a 00000400 00000800 00000000 0000000a 0000000b 00000001
+		000003e9:00000010 0000000a-00000001 00000014 00000002`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	writeXnfvInspection(w, payload)
+	w.Flush()
+	for _, line := range []string{
+		"datagram, 92 bytes",
+		"  0008  agent address            192.0.2.1",
+		"    0044  record 1: 0:1001 Extended Switch Flow Record, 24 bytes",
+		"          0048  record length            16",
+		"          0054  OutgoingVLAN             20",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("no line %q in\n%s", line, out.String())
+		}
+	}
+	// every field of the record is annotated, it is not printed twice
+	if strings.Contains(out.String(), "in_vlan") || strings.Contains(out.String(), "FAILED") {
+		t.Errorf("output\n%s", out.String())
+	}
+}
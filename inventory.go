@@ -0,0 +1,31 @@
+package main
+
+import (
+	"github.com/google/gopacket"
+)
+
+// ****************************************************************************************************
+//  Switch Inventory
+// ****************************************************************************************************
+
+// XnfvAllSwitches is the inventory of the OVS switches, learned from the
+// OpenFlow port (1004) and port name (1005) counter records
+type XnfvAllSwitches struct {
+	allAvailableSwitches []XnfSwitchSflow
+}
+
+// XnfSwitchSflow is a switch by its OpenFlow datapath ID and its ports
+type XnfSwitchSflow struct {
+	switchDataPath        []byte
+	switchPortsStatistics []XnfvSwitchPort
+}
+
+// XnfvSwitchPort is a named port of a switch, the ifIndex of its counter
+// samples, the layers of the last header sampled on it and the datagram
+// that reported it
+type XnfvSwitchPort struct {
+	interfacePortName      string
+	interfacePortIndex     SFlowSourceValue
+	PacketHeader           []gopacket.Layer
+	interfaceSflowDatagram GenericSFlowDatagram
+}
@@ -0,0 +1,513 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Mitigation Through The SDN Controller REST API
+// ****************************************************************************************************
+
+// XnfvMitigationAction is what the installed OpenFlow rule does with
+// the matched traffic
+type XnfvMitigationAction string
+
+const (
+	XnfvMitigationDrop      XnfvMitigationAction = "drop"
+	XnfvMitigationRateLimit XnfvMitigationAction = "rate-limit"
+	XnfvMitigationRedirect  XnfvMitigationAction = "redirect"
+)
+
+func (a *XnfvMitigationAction) String() string { return string(*a) }
+
+// Set parses the -mitigation-action flag
+func (a *XnfvMitigationAction) Set(value string) error {
+	switch XnfvMitigationAction(value) {
+	case XnfvMitigationDrop, XnfvMitigationRateLimit, XnfvMitigationRedirect:
+		*a = XnfvMitigationAction(value)
+		return nil
+	}
+	return fmt.Errorf("unknown mitigation action %q", value)
+}
+
+// XnfvControllerAPI selects the flavour of controller REST API
+type XnfvControllerAPI string
+
+const (
+	XnfvControllerRyu  XnfvControllerAPI = "ryu"  // ryu.app.ofctl_rest
+	XnfvControllerONOS XnfvControllerAPI = "onos" // ONOS /onos/v1 REST API
+)
+
+func (a *XnfvControllerAPI) String() string { return string(*a) }
+
+// Set parses the -controller-api flag
+func (a *XnfvControllerAPI) Set(value string) error {
+	switch XnfvControllerAPI(value) {
+	case XnfvControllerRyu, XnfvControllerONOS:
+		*a = XnfvControllerAPI(value)
+		return nil
+	}
+	return fmt.Errorf("unknown controller API %q", value)
+}
+
+type XnfvMitigationConfig struct {
+	ControllerURL string // e.g. http://127.0.0.1:8080 or http://onos:8181
+	ControllerAPI XnfvControllerAPI
+	Username      string // basic auth, used by ONOS
+	Password      string
+	ONOSAppID     string
+
+	Action        XnfvMitigationAction
+	Priority      uint16
+	MeterID       uint32 // meter used by rate-limit rules
+	RateLimitKbps uint32 // meter rate, installed on Ryu switches on first use
+	RedirectPort  uint32 // OpenFlow port redirect rules output to (e.g. a scrubber VNF)
+
+	TTL     time.Duration // rules are withdrawn automatically after TTL
+	DryRun  bool          // log the controller requests instead of sending them
+	Timeout time.Duration // HTTP timeout for controller requests
+}
+
+func DefaultXnfvMitigationConfig() XnfvMitigationConfig {
+	return XnfvMitigationConfig{
+		ControllerURL: "http://127.0.0.1:8080",
+		ControllerAPI: XnfvControllerRyu,
+		ONOSAppID:     "org.xnfv.sflow",
+		Action:        XnfvMitigationDrop,
+		Priority:      40000,
+		MeterID:       1,
+		RateLimitKbps: 10000,
+		TTL:           5 * time.Minute,
+		DryRun:        true,
+		Timeout:       5 * time.Second,
+	}
+}
+
+// registerXnfvMitigationFlags adds the flags of the controller and of the
+// mitigation rules, their defaults are those of config
+func registerXnfvMitigationFlags(flags *flag.FlagSet, config *XnfvMitigationConfig) {
+	flags.StringVar(&config.ControllerURL, "controller", config.ControllerURL, "SDN controller REST API URL")
+	flags.Var(&config.ControllerAPI, "controller-api", "controller REST API: ryu (ofctl_rest) or onos")
+	flags.StringVar(&config.Username, "controller-user", config.Username, "controller basic auth user (ONOS)")
+	flags.StringVar(&config.Password, "controller-password", config.Password, "controller basic auth password (ONOS)")
+	flags.Var(&config.Action, "mitigation-action", "mitigation rule action: drop, rate-limit or redirect")
+	flags.DurationVar(&config.TTL, "mitigation-ttl", config.TTL, "mitigation rules are withdrawn after this long")
+	flags.BoolVar(&config.DryRun, "mitigation-dry-run", config.DryRun, "log the controller requests instead of sending them")
+	flags.Var(xnfvUint32Flag{&config.RateLimitKbps}, "rate-limit-kbps", "meter rate of the rate-limit rules")
+	flags.Var(xnfvUint32Flag{&config.RedirectPort}, "redirect-port", "OpenFlow port the redirect rules output to")
+}
+
+// xnfvUint32Flag is a flag setting an uint32 field
+type xnfvUint32Flag struct {
+	value *uint32
+}
+
+func (f xnfvUint32Flag) String() string {
+	if f.value == nil {
+		return "0"
+	}
+	return strconv.FormatUint(uint64(*f.value), 10)
+}
+
+func (f xnfvUint32Flag) Set(value string) error {
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	*f.value = uint32(parsed)
+	return nil
+}
+
+// XnfvMitigationMatch holds the OpenFlow match fields of a rule. Zero
+// values are left out of the match.
+type XnfvMitigationMatch struct {
+	DataPath []byte            `json:"-"`
+	InPort   uint32            `json:"inPort,omitempty"`
+	SrcIP    string            `json:"srcIp,omitempty"`
+	DstIP    string            `json:"dstIp,omitempty"`
+	Protocol layers.IPProtocol `json:"protocol,omitempty"`
+	SrcPort  uint16            `json:"srcPort,omitempty"`
+	DstPort  uint16            `json:"dstPort,omitempty"`
+}
+
+// key identifies the rule so repeated detections refresh it instead of
+// installing duplicates
+func (m XnfvMitigationMatch) key() string {
+	return fmt.Sprintf("%x/%d/%s/%s/%d/%d/%d", m.DataPath, m.InPort, m.SrcIP, m.DstIP, m.Protocol, m.SrcPort, m.DstPort)
+}
+
+func (m XnfvMitigationMatch) isIPv6() bool {
+	return strings.Contains(m.SrcIP, ":") || strings.Contains(m.DstIP, ":")
+}
+
+// XnfvMitigation is a rule installed (or, in dry-run, that would have
+// been installed) on a switch
+type XnfvMitigation struct {
+	Match          XnfvMitigationMatch  `json:"match"`
+	SwitchDataPath string               `json:"switchDataPath"`
+	Action         XnfvMitigationAction `json:"action"`
+	Reason         string               `json:"reason"`
+	Installed      time.Time            `json:"installed"`
+	Expires        time.Time            `json:"expires"`
+	DryRun         bool                 `json:"dryRun"`
+	flowID         string               // ONOS flow id needed to withdraw the rule
+}
+
+// XnfvMitigator installs and withdraws mitigation rules through the
+// controller REST API
+type XnfvMitigator struct {
+	config      XnfvMitigationConfig
+	client      *http.Client
+	active      map[string]*XnfvMitigation
+	meteredDPID map[string]bool
+}
+
+func NewXnfvMitigator(config XnfvMitigationConfig) *XnfvMitigator {
+	return &XnfvMitigator{
+		config:      config,
+		client:      &http.Client{Timeout: config.Timeout},
+		active:      map[string]*XnfvMitigation{},
+		meteredDPID: map[string]bool{},
+	}
+}
+
+// Active returns the rules currently installed
+func (m *XnfvMitigator) Active() []XnfvMitigation {
+	mitigations := make([]XnfvMitigation, 0, len(m.active))
+	for _, mitigation := range m.active {
+		mitigations = append(mitigations, *mitigation)
+	}
+	return mitigations
+}
+
+// Mitigate installs a rule for match, or extends the TTL of the rule
+// already installed for it.
+func (m *XnfvMitigator) Mitigate(match XnfvMitigationMatch, action XnfvMitigationAction, reason string, now time.Time) (*XnfvMitigation, error) {
+	if len(match.DataPath) == 0 {
+		return nil, fmt.Errorf("Mitigation for %s has no switch datapath", reason)
+	}
+	if mitigation, ok := m.active[match.key()]; ok {
+		mitigation.Expires = now.Add(m.config.TTL)
+		return mitigation, nil
+	}
+
+	mitigation := &XnfvMitigation{
+		Match:          match,
+		SwitchDataPath: dataPathString(match.DataPath),
+		Action:         action,
+		Reason:         reason,
+		Installed:      now,
+		Expires:        now.Add(m.config.TTL),
+		DryRun:         m.config.DryRun,
+	}
+	var err error
+	switch m.config.ControllerAPI {
+	case XnfvControllerRyu:
+		err = m.installRyu(mitigation)
+	case XnfvControllerONOS:
+		err = m.installONOS(mitigation)
+	default:
+		err = fmt.Errorf("Unsupported controller API: %s", m.config.ControllerAPI)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.active[match.key()] = mitigation
+	return mitigation, nil
+}
+
+// MitigateAttack installs one rule per ingress switch port of an attack,
+// matching the victim and the attack protocol.
+func (m *XnfvMitigator) MitigateAttack(event XnfvAttackEvent, now time.Time) ([]*XnfvMitigation, []error) {
+	var mitigations []*XnfvMitigation
+	var errs []error
+	for _, ingress := range event.Ingress {
+		dataPath, err := parseDataPath(ingress.SwitchDataPath)
+		if err != nil || len(dataPath) == 0 {
+			continue
+		}
+		match := XnfvMitigationMatch{DataPath: dataPath, InPort: ingress.OfPort, DstIP: event.Victim}
+		switch event.Type {
+		case XnfvAttackSynFlood:
+			match.Protocol = layers.IPProtocolTCP
+		case XnfvAttackUDPReflection:
+			match.Protocol = layers.IPProtocolUDP
+		case XnfvAttackICMPFlood:
+			match.Protocol = layers.IPProtocolICMPv4
+			if match.isIPv6() {
+				match.Protocol = layers.IPProtocolICMPv6
+			}
+		}
+		if mitigation, err := m.Mitigate(match, m.config.Action, string(event.Type), now); err == nil {
+			mitigations = append(mitigations, mitigation)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	return mitigations, errs
+}
+
+// MitigateElephant installs a rule matching the 5-tuple of an elephant
+// flow on the switch port it entered on
+func (m *XnfvMitigator) MitigateElephant(event XnfvElephantEvent, now time.Time) (*XnfvMitigation, error) {
+	dataPath, err := parseDataPath(event.SwitchDataPath)
+	if err != nil {
+		return nil, err
+	}
+	match := XnfvMitigationMatch{
+		DataPath: dataPath,
+		InPort:   event.OfPort,
+		SrcIP:    event.SrcIP,
+		DstIP:    event.DstIP,
+		Protocol: event.IPProtocol,
+		SrcPort:  event.SrcPort,
+		DstPort:  event.DstPort,
+	}
+	return m.Mitigate(match, m.config.Action, event.Type, now)
+}
+
+// Expire withdraws every rule whose TTL has passed
+func (m *XnfvMitigator) Expire(now time.Time) []error {
+	var errs []error
+	for key, mitigation := range m.active {
+		if now.Before(mitigation.Expires) {
+			continue
+		}
+		if err := m.withdraw(mitigation); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(m.active, key)
+	}
+	return errs
+}
+
+// WithdrawAll removes every installed rule, e.g. on shutdown
+func (m *XnfvMitigator) WithdrawAll() []error {
+	var errs []error
+	for key, mitigation := range m.active {
+		if err := m.withdraw(mitigation); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(m.active, key)
+	}
+	return errs
+}
+
+func (m *XnfvMitigator) withdraw(mitigation *XnfvMitigation) error {
+	switch m.config.ControllerAPI {
+	case XnfvControllerRyu:
+		return m.send(http.MethodPost, "/stats/flowentry/delete_strict", m.ryuFlowEntry(mitigation), nil)
+	case XnfvControllerONOS:
+		if mitigation.flowID == "" && !mitigation.DryRun {
+			return fmt.Errorf("ONOS flow for %s has no flow id", mitigation.Match.key())
+		}
+		return m.send(http.MethodDelete, "/onos/v1/flows/"+onosDeviceID(mitigation.Match.DataPath)+"/"+mitigation.flowID, nil, nil)
+	}
+	return fmt.Errorf("Unsupported controller API: %s", m.config.ControllerAPI)
+}
+
+// ****************************************************************************************************
+//  Ryu ofctl_rest
+// ****************************************************************************************************
+
+func (m *XnfvMitigator) installRyu(mitigation *XnfvMitigation) error {
+	if mitigation.Action == XnfvMitigationRateLimit && !m.meteredDPID[mitigation.SwitchDataPath] {
+		meter := map[string]interface{}{
+			"dpid":     ryuDPID(mitigation.Match.DataPath),
+			"flags":    "KBPS",
+			"meter_id": m.config.MeterID,
+			"bands":    []map[string]interface{}{{"type": "DROP", "rate": m.config.RateLimitKbps}},
+		}
+		if err := m.send(http.MethodPost, "/stats/meterentry/add", meter, nil); err != nil {
+			return err
+		}
+		m.meteredDPID[mitigation.SwitchDataPath] = true
+	}
+	return m.send(http.MethodPost, "/stats/flowentry/add", m.ryuFlowEntry(mitigation), nil)
+}
+
+func (m *XnfvMitigator) ryuFlowEntry(mitigation *XnfvMitigation) map[string]interface{} {
+	match := map[string]interface{}{}
+	if mitigation.Match.InPort != 0 {
+		match["in_port"] = mitigation.Match.InPort
+	}
+	if mitigation.Match.isIPv6() {
+		match["eth_type"] = uint16(layers.EthernetTypeIPv6)
+		setIfNotEmpty(match, "ipv6_src", mitigation.Match.SrcIP)
+		setIfNotEmpty(match, "ipv6_dst", mitigation.Match.DstIP)
+	} else {
+		match["eth_type"] = uint16(layers.EthernetTypeIPv4)
+		setIfNotEmpty(match, "ipv4_src", mitigation.Match.SrcIP)
+		setIfNotEmpty(match, "ipv4_dst", mitigation.Match.DstIP)
+	}
+	if mitigation.Match.Protocol != 0 {
+		match["ip_proto"] = uint8(mitigation.Match.Protocol)
+		l4 := strings.ToLower(mitigation.Match.Protocol.String())
+		if mitigation.Match.SrcPort != 0 {
+			match[l4+"_src"] = mitigation.Match.SrcPort
+		}
+		if mitigation.Match.DstPort != 0 {
+			match[l4+"_dst"] = mitigation.Match.DstPort
+		}
+	}
+
+	actions := []map[string]interface{}{}
+	switch mitigation.Action {
+	case XnfvMitigationRateLimit:
+		actions = append(actions, map[string]interface{}{"type": "METER", "meter_id": m.config.MeterID})
+		actions = append(actions, map[string]interface{}{"type": "OUTPUT", "port": "NORMAL"})
+	case XnfvMitigationRedirect:
+		actions = append(actions, map[string]interface{}{"type": "OUTPUT", "port": m.config.RedirectPort})
+	}
+	return map[string]interface{}{
+		"dpid":     ryuDPID(mitigation.Match.DataPath),
+		"priority": m.config.Priority,
+		"match":    match,
+		"actions":  actions,
+	}
+}
+
+func ryuDPID(dataPath []byte) uint64 {
+	if len(dataPath) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(dataPath)
+}
+
+// ****************************************************************************************************
+//  ONOS REST
+// ****************************************************************************************************
+
+func (m *XnfvMitigator) installONOS(mitigation *XnfvMitigation) error {
+	deviceID := onosDeviceID(mitigation.Match.DataPath)
+	criteria := []map[string]interface{}{}
+	if mitigation.Match.InPort != 0 {
+		criteria = append(criteria, map[string]interface{}{"type": "IN_PORT", "port": mitigation.Match.InPort})
+	}
+	if mitigation.Match.isIPv6() {
+		criteria = append(criteria, map[string]interface{}{"type": "ETH_TYPE", "ethType": "0x86dd"})
+		if mitigation.Match.SrcIP != "" {
+			criteria = append(criteria, map[string]interface{}{"type": "IPV6_SRC", "ip": mitigation.Match.SrcIP + "/128"})
+		}
+		if mitigation.Match.DstIP != "" {
+			criteria = append(criteria, map[string]interface{}{"type": "IPV6_DST", "ip": mitigation.Match.DstIP + "/128"})
+		}
+	} else {
+		criteria = append(criteria, map[string]interface{}{"type": "ETH_TYPE", "ethType": "0x800"})
+		if mitigation.Match.SrcIP != "" {
+			criteria = append(criteria, map[string]interface{}{"type": "IPV4_SRC", "ip": mitigation.Match.SrcIP + "/32"})
+		}
+		if mitigation.Match.DstIP != "" {
+			criteria = append(criteria, map[string]interface{}{"type": "IPV4_DST", "ip": mitigation.Match.DstIP + "/32"})
+		}
+	}
+	if mitigation.Match.Protocol != 0 {
+		criteria = append(criteria, map[string]interface{}{"type": "IP_PROTO", "protocol": uint8(mitigation.Match.Protocol)})
+		l4 := strings.ToUpper(mitigation.Match.Protocol.String())
+		if mitigation.Match.SrcPort != 0 {
+			criteria = append(criteria, map[string]interface{}{"type": l4 + "_SRC", strings.ToLower(l4) + "Port": mitigation.Match.SrcPort})
+		}
+		if mitigation.Match.DstPort != 0 {
+			criteria = append(criteria, map[string]interface{}{"type": l4 + "_DST", strings.ToLower(l4) + "Port": mitigation.Match.DstPort})
+		}
+	}
+
+	instructions := []map[string]interface{}{}
+	switch mitigation.Action {
+	case XnfvMitigationRateLimit:
+		instructions = append(instructions, map[string]interface{}{"type": "METER", "meterId": m.config.MeterID})
+		instructions = append(instructions, map[string]interface{}{"type": "OUTPUT", "port": "NORMAL"})
+	case XnfvMitigationRedirect:
+		instructions = append(instructions, map[string]interface{}{"type": "OUTPUT", "port": fmt.Sprint(m.config.RedirectPort)})
+	}
+	flow := map[string]interface{}{
+		"priority":    m.config.Priority,
+		"isPermanent": true,
+		"deviceId":    deviceID,
+		"selector":    map[string]interface{}{"criteria": criteria},
+		"treatment":   map[string]interface{}{"instructions": instructions},
+	}
+
+	var location string
+	if err := m.send(http.MethodPost, "/onos/v1/flows/"+deviceID+"?appId="+m.config.ONOSAppID, flow, &location); err != nil {
+		return err
+	}
+	// ONOS answers with the new flow in the Location header: .../flows/{deviceId}/{flowId}
+	if idx := strings.LastIndex(location, "/"); idx >= 0 {
+		mitigation.flowID = location[idx+1:]
+	}
+	return nil
+}
+
+func onosDeviceID(dataPath []byte) string {
+	return "of:" + dataPathString(dataPath)
+}
+
+// ****************************************************************************************************
+//  HTTP
+// ****************************************************************************************************
+
+// send issues a controller request. In dry-run mode the request is only
+// logged. When location is not nil it receives the Location header.
+func (m *XnfvMitigator) send(method string, path string, body interface{}, location *string) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	if m.config.DryRun {
+		log.Printf("mitigation dry-run: %s %s%s %s", method, m.config.ControllerURL, path, payload)
+		return nil
+	}
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(m.config.ControllerURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if m.config.Username != "" {
+		req.SetBasicAuth(m.config.Username, m.config.Password)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Controller %s %s failed: %s %s", method, path, resp.Status, respBody)
+	}
+	if location != nil {
+		*location = resp.Header.Get("Location")
+	}
+	return nil
+}
+
+func setIfNotEmpty(m map[string]interface{}, key string, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// xnfvControllerRequest is a request received by the controller stand-in
+type xnfvControllerRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// xnfvControllerStandIn records the requests of the mitigator, the ONOS
+// flows it creates are answered with the Location header of ONOS
+type xnfvControllerStandIn struct {
+	mutex    sync.Mutex
+	requests []xnfvControllerRequest
+	server   *httptest.Server
+}
+
+func newXnfvControllerStandIn(t *testing.T) *xnfvControllerStandIn {
+	c := &xnfvControllerStandIn{}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := xnfvControllerRequest{Method: r.Method, Path: r.URL.RequestURI()}
+		if data, _ := ioutil.ReadAll(r.Body); len(data) > 0 {
+			if err := json.Unmarshal(data, &request.Body); err != nil {
+				t.Errorf("%s %s: %v", r.Method, r.URL, err)
+			}
+		}
+		c.mutex.Lock()
+		c.requests = append(c.requests, request)
+		c.mutex.Unlock()
+		if r.Method == http.MethodPost && r.URL.Path == "/onos/v1/flows/of:0000000000000001" {
+			w.Header().Set("Location", c.server.URL+"/onos/v1/flows/of:0000000000000001/123456")
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *xnfvControllerStandIn) received() []xnfvControllerRequest {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]xnfvControllerRequest(nil), c.requests...)
+}
+
+func xnfvTestAttack() XnfvAttackEvent {
+	return XnfvAttackEvent{
+		Type:   XnfvAttackSynFlood,
+		Victim: "10.0.0.1",
+		Ingress: []XnfvAttackIngress{
+			{SwitchDataPath: "0000000000000001", PortName: "vnet0", OfPort: 3},
+		},
+	}
+}
+
+func xnfvTestMitigationConfig(url string, api XnfvControllerAPI) XnfvMitigationConfig {
+	config := DefaultXnfvMitigationConfig()
+	config.ControllerURL, config.ControllerAPI, config.DryRun = url, api, false
+	return config
+}
+
+func TestXnfvMitigatorRyu(t *testing.T) {
+	controller := newXnfvControllerStandIn(t)
+	m := NewXnfvMitigator(xnfvTestMitigationConfig(controller.server.URL, XnfvControllerRyu))
+
+	now := xnfvTestEpoch
+	mitigations, errs := m.MitigateAttack(xnfvTestAttack(), now)
+	if len(errs) != 0 || len(mitigations) != 1 {
+		t.Fatalf("mitigations %v, errors %v", mitigations, errs)
+	}
+	requests := controller.received()
+	if len(requests) != 1 || requests[0].Method != http.MethodPost || requests[0].Path != "/stats/flowentry/add" {
+		t.Fatalf("install requests %+v", requests)
+	}
+	entry := requests[0].Body
+	match := entry["match"].(map[string]interface{})
+	if entry["dpid"] != float64(1) || match["in_port"] != float64(3) || match["ipv4_dst"] != "10.0.0.1" ||
+		match["ip_proto"] != float64(layers.IPProtocolTCP) || len(entry["actions"].([]interface{})) != 0 {
+		t.Errorf("flow entry %v", entry)
+	}
+
+	// a new detection refreshes the rule instead of installing it again
+	m.MitigateAttack(xnfvTestAttack(), now.Add(time.Minute))
+	if requests := controller.received(); len(requests) != 1 {
+		t.Fatalf("the rule was installed again: %+v", requests)
+	}
+	if errs := m.Expire(now.Add(m.config.TTL)); len(errs) != 0 || len(m.Active()) != 1 {
+		t.Fatalf("the refreshed rule expired: %v", errs)
+	}
+
+	if errs := m.Expire(now.Add(time.Minute + m.config.TTL)); len(errs) != 0 || len(m.Active()) != 0 {
+		t.Fatalf("the rule did not expire: %v, %v", errs, m.Active())
+	}
+	requests = controller.received()
+	if len(requests) != 2 || requests[1].Path != "/stats/flowentry/delete_strict" || requests[1].Body["dpid"] != float64(1) {
+		t.Fatalf("withdraw requests %+v", requests)
+	}
+}
+
+func TestXnfvMitigatorRyuRateLimit(t *testing.T) {
+	controller := newXnfvControllerStandIn(t)
+	config := xnfvTestMitigationConfig(controller.server.URL, XnfvControllerRyu)
+	config.Action = XnfvMitigationRateLimit
+	m := NewXnfvMitigator(config)
+
+	attack := xnfvTestAttack()
+	attack.Ingress = append(attack.Ingress, XnfvAttackIngress{SwitchDataPath: "0000000000000001", OfPort: 4})
+	if _, errs := m.MitigateAttack(attack, xnfvTestEpoch); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	var paths []string
+	for _, request := range controller.received() {
+		paths = append(paths, request.Path)
+	}
+	// the meter is added once per switch
+	want := []string{"/stats/meterentry/add", "/stats/flowentry/add", "/stats/flowentry/add"}
+	if len(paths) != len(want) {
+		t.Fatalf("requests %v, want %v", paths, want)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("requests %v, want %v", paths, want)
+		}
+	}
+}
+
+func TestXnfvMitigatorONOS(t *testing.T) {
+	controller := newXnfvControllerStandIn(t)
+	m := NewXnfvMitigator(xnfvTestMitigationConfig(controller.server.URL, XnfvControllerONOS))
+
+	mitigations, errs := m.MitigateAttack(xnfvTestAttack(), xnfvTestEpoch)
+	if len(errs) != 0 || len(mitigations) != 1 || mitigations[0].flowID != "123456" {
+		t.Fatalf("mitigations %+v, errors %v", mitigations, errs)
+	}
+	requests := controller.received()
+	if len(requests) != 1 || requests[0].Path != "/onos/v1/flows/of:0000000000000001?appId=org.xnfv.sflow" {
+		t.Fatalf("install requests %+v", requests)
+	}
+	if requests[0].Body["deviceId"] != "of:0000000000000001" {
+		t.Errorf("flow %v", requests[0].Body)
+	}
+
+	if errs := m.Expire(xnfvTestEpoch.Add(m.config.TTL)); len(errs) != 0 || len(m.Active()) != 0 {
+		t.Fatalf("the rule did not expire: %v", errs)
+	}
+	requests = controller.received()
+	if len(requests) != 2 || requests[1].Method != http.MethodDelete || requests[1].Path != "/onos/v1/flows/of:0000000000000001/123456" {
+		t.Fatalf("withdraw requests %+v", requests)
+	}
+}
+
+func TestXnfvMitigatorDryRun(t *testing.T) {
+	for _, api := range []XnfvControllerAPI{XnfvControllerRyu, XnfvControllerONOS} {
+		controller := newXnfvControllerStandIn(t)
+		config := xnfvTestMitigationConfig(controller.server.URL, api)
+		config.DryRun = true
+		m := NewXnfvMitigator(config)
+
+		mitigations, errs := m.MitigateAttack(xnfvTestAttack(), xnfvTestEpoch)
+		if len(errs) != 0 || len(mitigations) != 1 || !mitigations[0].DryRun {
+			t.Fatalf("%s: mitigations %+v, errors %v", api, mitigations, errs)
+		}
+		if errs := m.Expire(xnfvTestEpoch.Add(config.TTL)); len(errs) != 0 || len(m.Active()) != 0 {
+			t.Fatalf("%s: the dry-run rule did not expire: %v", api, errs)
+		}
+		if requests := controller.received(); len(requests) != 0 {
+			t.Errorf("%s: dry-run sent %+v", api, requests)
+		}
+	}
+}
+
+func TestXnfvMitigatorControllerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such switch", http.StatusNotFound)
+	}))
+	defer server.Close()
+	m := NewXnfvMitigator(xnfvTestMitigationConfig(server.URL, XnfvControllerRyu))
+	if mitigations, errs := m.MitigateAttack(xnfvTestAttack(), xnfvTestEpoch); len(errs) != 1 || len(mitigations) != 0 || len(m.Active()) != 0 {
+		t.Fatalf("mitigations %v, errors %v", mitigations, errs)
+	}
+}
+
+func TestXnfvMitigationFlags(t *testing.T) {
+	config := DefaultXnfvMitigationConfig()
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerXnfvMitigationFlags(flags, &config)
+	err := flags.Parse([]string{"-controller", "http://onos:8181", "-controller-api", "onos", "-mitigation-action", "redirect",
+		"-redirect-port", "7", "-mitigation-ttl", "1m", "-mitigation-dry-run=false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ControllerURL != "http://onos:8181" || config.ControllerAPI != XnfvControllerONOS || config.Action != XnfvMitigationRedirect ||
+		config.RedirectPort != 7 || config.TTL != time.Minute || config.DryRun {
+		t.Errorf("config %+v", config)
+	}
+	flags.SetOutput(ioutil.Discard)
+	if err := flags.Parse([]string{"-mitigation-action", "block"}); err == nil {
+		t.Error("unknown action accepted")
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Open vSwitch Datapath Cache Monitoring
+// ****************************************************************************************************
+
+// XnfvDatapathEventType names the kind of XnfvDatapathEvent
+type XnfvDatapathEventType string
+
+const (
+	// the datapath loses packets before they reach ovs-vswitchd
+	XnfvDatapathLoss XnfvDatapathEventType = "ovs-datapath-loss"
+	// too many packets miss the flow cache, a sign of cache thrashing
+	XnfvDatapathCacheThrashing XnfvDatapathEventType = "ovs-datapath-cache-thrashing"
+)
+
+// XnfvOVSDatapath is the latest OVS datapath counters of an agent
+// together with the rates derived from the two last records
+type XnfvOVSDatapath struct {
+	Counters sflow.SFlowOVSDPCounters `json:"counters"`
+	// per second rates over the last counter interval
+	HitRate  float64 `json:"hitRate"`
+	MissRate float64 `json:"missRate"` // every miss is an upcall to ovs-vswitchd
+	LostRate float64 `json:"lostRate"`
+	// MissRatio is misses / (hits + misses) over the last interval
+	MissRatio float64 `json:"missRatio"`
+	// MaskHitsPerPacket is the average number of masks looked up per
+	// packet, high values make every lookup, hit or miss, slower
+	MaskHitsPerPacket float64   `json:"maskHitsPerPacket"`
+	Interval          float64   `json:"interval"`
+	LastUpdate        time.Time `json:"lastUpdate"`
+}
+
+// XnfvDatapathEvent reports datapath loss or cache thrashing on the OVS
+// instance of an agent
+type XnfvDatapathEvent struct {
+	Type            XnfvDatapathEventType `json:"type"`
+	Agent           string                `json:"agent"`
+	SwitchDataPaths []string              `json:"switchDataPaths"`
+	Datapath        XnfvOVSDatapath       `json:"datapath"`
+	Time            time.Time             `json:"time"`
+}
+
+// observeOVSDatapath stores a datapath record and derives the rates from
+// the previous one. After an OVS restart the record only becomes the
+// reference of the next one.
+func (a *XnfvAgent) observeOVSDatapath(counters sflow.SFlowOVSDPCounters, config XnfvAgentRegistryConfig, now time.Time) []XnfvDatapathEvent {
+	previous := a.Datapath
+	datapath := &XnfvOVSDatapath{Counters: counters, LastUpdate: now}
+	a.Datapath = datapath
+	if previous == nil {
+		return nil
+	}
+	interval := now.Sub(previous.LastUpdate).Seconds()
+	if interval <= 0 {
+		return nil
+	}
+	var deltas xnfvCounterDeltas
+	hits := float64(deltas.delta(previous.Counters.Hits, counters.Hits))
+	misses := float64(deltas.delta(previous.Counters.Misses, counters.Misses))
+	lost := float64(deltas.delta(previous.Counters.Lost, counters.Lost))
+	maskHits := float64(deltas.delta(previous.Counters.MaskHits, counters.MaskHits))
+	if deltas.reset {
+		return nil
+	}
+
+	datapath.Interval = interval
+	datapath.HitRate, datapath.MissRate, datapath.LostRate = hits/interval, misses/interval, lost/interval
+	if packets := hits + misses; packets > 0 {
+		datapath.MissRatio = misses / packets
+		datapath.MaskHitsPerPacket = maskHits / packets
+	}
+
+	var events []XnfvDatapathEvent
+	newEvent := func(eventType XnfvDatapathEventType) XnfvDatapathEvent {
+		return XnfvDatapathEvent{
+			Type:            eventType,
+			Agent:           a.Address,
+			SwitchDataPaths: append([]string(nil), a.SwitchDataPaths...),
+			Datapath:        *datapath,
+			Time:            now,
+		}
+	}
+	if datapath.LostRate >= config.DatapathLostPps && datapath.LostRate > 0 {
+		events = append(events, newEvent(XnfvDatapathLoss))
+	}
+	if datapath.MissRate >= config.DatapathMissPps && datapath.MissRatio >= config.DatapathMissRatio {
+		events = append(events, newEvent(XnfvDatapathCacheThrashing))
+	}
+	return events
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+func TestXnfvObserveOVSDatapath(t *testing.T) {
+	config := DefaultXnfvAgentRegistryConfig()
+	agent := &XnfvAgent{Address: "192.0.2.1", SwitchDataPaths: []string{"0000000000000001"}}
+	observe := func(at time.Duration, hits uint32, misses uint32, lost uint32) []XnfvDatapathEvent {
+		counters := sflow.SFlowOVSDPCounters{Hits: hits, Misses: misses, Lost: lost, MaskHits: 2 * (hits + misses)}
+		return agent.observeOVSDatapath(counters, config, xnfvTestEpoch.Add(at))
+	}
+
+	if events := observe(0, 1000, 100, 0xFFFFFF00); events != nil || agent.Datapath.Interval != 0 {
+		t.Fatalf("first record: %v %+v", events, agent.Datapath)
+	}
+	if events := observe(10*time.Second, 101000, 1100, 0xFFFFFF00); len(events) != 0 {
+		t.Fatalf("healthy datapath: %v", events)
+	}
+	if d := agent.Datapath; d.HitRate != 10000 || d.MissRate != 100 || d.Interval != 10 || d.MaskHitsPerPacket != 2 {
+		t.Errorf("rates %+v", d)
+	}
+
+	// the lost counter wraps
+	events := observe(20*time.Second, 201000, 2100, 0x63)
+	if len(events) != 1 || events[0].Type != XnfvDatapathLoss || agent.Datapath.LostRate != 35.5 {
+		t.Fatalf("loss over the wrap: %v %+v", events, agent.Datapath)
+	}
+
+	// OVS restarts, the counters start over
+	if events := observe(40*time.Second, 50, 5, 0); events != nil || agent.Datapath.HitRate != 0 || agent.Datapath.Interval != 0 {
+		t.Fatalf("restart: %v %+v", events, agent.Datapath)
+	}
+	if events := observe(50*time.Second, 1050, 15, 0); len(events) != 0 || agent.Datapath.HitRate != 100 {
+		t.Fatalf("after the restart: %v %+v", events, agent.Datapath)
+	}
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Port Health (LACP / SFP) and VLAN Traffic
+// ****************************************************************************************************
+
+// XnfvPortEventType names the kind of XnfvPortEvent
+type XnfvPortEventType string
+
+const (
+	// the port joined / left its aggregator or lost sync too often
+	XnfvLAGMemberChurn XnfvPortEventType = "lag-member-churn"
+	// the port is attached to an aggregator but not synchronized,
+	// collecting and distributing on the actor or the partner side
+	XnfvLACPOutOfSync XnfvPortEventType = "lacp-out-of-sync"
+	// a lane's optical power crossed the alarm threshold of the module
+	XnfvOpticalThreshold XnfvPortEventType = "optical-threshold-crossing"
+	// a lane's optical power drifted away from the level first seen
+	XnfvOpticalDrift XnfvPortEventType = "optical-power-drift"
+)
+
+type XnfvPortTableConfig struct {
+	// LAGChurnChanges membership or sync changes within LAGChurnWindow
+	// are reported as churn
+	LAGChurnWindow  time.Duration
+	LAGChurnChanges int
+	// OpticalDriftDb is how far (dB) a lane's power may move from its
+	// baseline before a drift event
+	OpticalDriftDb float64
+	// MaxAge removes ports that have not been reported for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvPortTableConfig() XnfvPortTableConfig {
+	return XnfvPortTableConfig{
+		LAGChurnWindow:  10 * time.Minute,
+		LAGChurnChanges: 4,
+		OpticalDriftDb:  2,
+		MaxAge:          10 * time.Minute,
+	}
+}
+
+// XnfvPortKey identifies a port by the agent reporting it and the
+// ifIndex of its counter samples
+type XnfvPortKey struct {
+	Agent   string `json:"agent"`
+	IfIndex uint32 `json:"ifIndex"`
+}
+
+// XnfvOpticalLane is the state of one optical lane of a port
+type XnfvOpticalLane struct {
+	LaneIndex  uint32  `json:"laneIndex"`
+	TxPowerDbm float64 `json:"txPowerDbm"`
+	RxPowerDbm float64 `json:"rxPowerDbm"`
+	// baselines are the levels first seen on the lane
+	TxBaselineDbm float64 `json:"txBaselineDbm"`
+	RxBaselineDbm float64 `json:"rxBaselineDbm"`
+	// alarm states, an event is reported when they are entered
+	TxOutOfRange bool `json:"txOutOfRange"`
+	RxOutOfRange bool `json:"rxOutOfRange"`
+	TxDrifted    bool `json:"txDrifted"`
+	RxDrifted    bool `json:"rxDrifted"`
+}
+
+// XnfvPortStatus is the health of one port
+type XnfvPortStatus struct {
+	XnfvPortKey
+	LACP         *sflow.SFlowLACPCounters `json:"lacp,omitempty"`
+	LACPInSync   bool                     `json:"lacpInSync"`
+	LAGChanges   int                      `json:"lagChanges"`
+	SFP          *sflow.SFlowSFPCounters  `json:"sfp,omitempty"`
+	Temperature  float64                  `json:"temperature,omitempty"` // Celsius
+	OpticalLanes []XnfvOpticalLane        `json:"opticalLanes,omitempty"`
+	LastUpdate   time.Time                `json:"lastUpdate"`
+}
+
+// XnfvPortEvent reports a LAG or optical health problem on a port
+type XnfvPortEvent struct {
+	Type      XnfvPortEventType `json:"type"`
+	Port      XnfvPortKey       `json:"port"`
+	LaneIndex uint32            `json:"laneIndex,omitempty"`
+	Direction string            `json:"direction,omitempty"` // "tx" or "rx"
+	PowerDbm  float64           `json:"powerDbm,omitempty"`
+	Detail    string            `json:"detail,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// XnfvVLANKey identifies a VLAN by the agent reporting its counters
+type XnfvVLANKey struct {
+	Agent  string `json:"agent"`
+	VlanID uint32 `json:"vlanId"`
+}
+
+// XnfvVLANStatus is the latest VLAN counters of an agent together with
+// the rates derived from the two last records
+type XnfvVLANStatus struct {
+	XnfvVLANKey
+	Counters sflow.SFlowVLANCounters `json:"counters"`
+	// per second rates over the last counter interval
+	BitRate       float64   `json:"bitRate"`
+	UcastRate     float64   `json:"ucastRate"`
+	MulticastRate float64   `json:"multicastRate"`
+	BroadcastRate float64   `json:"broadcastRate"`
+	DiscardRate   float64   `json:"discardRate"`
+	Interval      float64   `json:"interval"`
+	LastUpdate    time.Time `json:"lastUpdate"`
+}
+
+type xnfvPortEntry struct {
+	status      XnfvPortStatus
+	lagChanges  []time.Time
+	churnReport time.Time
+}
+
+// XnfvPortTable keeps the LACP and optical state of the ports reported
+// in counter samples and derives health signals from them, along with
+// the VLAN traffic rates of each agent. It is safe for concurrent use.
+type XnfvPortTable struct {
+	config XnfvPortTableConfig
+	mutex  sync.RWMutex
+	ports  map[XnfvPortKey]*xnfvPortEntry
+	vlans  map[XnfvVLANKey]*XnfvVLANStatus
+}
+
+func NewXnfvPortTable(config XnfvPortTableConfig) *XnfvPortTable {
+	return &XnfvPortTable{
+		config: config,
+		ports:  map[XnfvPortKey]*xnfvPortEntry{},
+		vlans:  map[XnfvVLANKey]*XnfvVLANStatus{},
+	}
+}
+
+// ObserveDatagram updates the ports with the LACP and SFP records and the
+// VLANs with the VLAN records of a datagram, and returns the health events
+// they raise
+func (t *XnfvPortTable) ObserveDatagram(datagram sflow.Datagram, now time.Time) []XnfvPortEvent {
+	if datagram.AgentAddress == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var events []XnfvPortEvent
+	for _, counterSample := range datagram.CounterSamples {
+		key := XnfvPortKey{datagram.AgentAddress.String(), uint32(counterSample.SourceIDIndex)}
+		for _, record := range counterSample.Records {
+			switch r := record.(type) {
+			case sflow.SFlowLACPCounters:
+				events = append(events, t.entry(key).observeLACP(r, t.config, now)...)
+			case sflow.SFlowSFPCounters:
+				events = append(events, t.entry(key).observeSFP(r, t.config, now)...)
+			case sflow.SFlowVLANCounters:
+				t.observeVLAN(XnfvVLANKey{key.Agent, r.VlanID}, r, now)
+			}
+		}
+	}
+	return events
+}
+
+func (t *XnfvPortTable) entry(key XnfvPortKey) *xnfvPortEntry {
+	entry, ok := t.ports[key]
+	if !ok {
+		entry = &xnfvPortEntry{status: XnfvPortStatus{XnfvPortKey: key}}
+		t.ports[key] = entry
+	}
+	return entry
+}
+
+func (e *xnfvPortEntry) observeLACP(lacp sflow.SFlowLACPCounters, config XnfvPortTableConfig, now time.Time) []XnfvPortEvent {
+	var events []XnfvPortEvent
+	previous := e.status.LACP
+	inSync := lacp.AttachedAggID != 0 && lacp.ActorOperState.InSync() && lacp.PartnerOperState.InSync()
+
+	if previous != nil && (previous.AttachedAggID != lacp.AttachedAggID || e.status.LACPInSync != inSync) {
+		e.lagChanges = append(e.lagChanges, now)
+	}
+	for len(e.lagChanges) > 0 && now.Sub(e.lagChanges[0]) > config.LAGChurnWindow {
+		e.lagChanges = e.lagChanges[1:]
+	}
+	if len(e.lagChanges) >= config.LAGChurnChanges && now.Sub(e.churnReport) > config.LAGChurnWindow {
+		e.churnReport = now
+		events = append(events, XnfvPortEvent{
+			Type:   XnfvLAGMemberChurn,
+			Port:   e.status.XnfvPortKey,
+			Detail: strconv.Itoa(len(e.lagChanges)) + " changes within " + config.LAGChurnWindow.String(),
+			Time:   now,
+		})
+	}
+	// report the transition into the out of sync state, once
+	if !inSync && lacp.AttachedAggID != 0 && (previous == nil || e.status.LACPInSync) {
+		events = append(events, XnfvPortEvent{
+			Type:   XnfvLACPOutOfSync,
+			Port:   e.status.XnfvPortKey,
+			Detail: "actor " + lacpStateString(lacp.ActorOperState) + ", partner " + lacpStateString(lacp.PartnerOperState),
+			Time:   now,
+		})
+	}
+
+	e.status.LACP, e.status.LACPInSync = &lacp, inSync
+	e.status.LAGChanges = len(e.lagChanges)
+	e.status.LastUpdate = now
+	return events
+}
+
+func lacpStateString(state sflow.SFlowLACPState) string {
+	names := []string{"activity", "timeout", "aggregation", "sync", "collecting", "distributing", "defaulted", "expired"}
+	result := ""
+	for bit, name := range names {
+		if state&(1<<uint(bit)) != 0 {
+			if result != "" {
+				result += "|"
+			}
+			result += name
+		}
+	}
+	if result == "" {
+		return "none"
+	}
+	return result
+}
+
+func (e *xnfvPortEntry) observeSFP(sfp sflow.SFlowSFPCounters, config XnfvPortTableConfig, now time.Time) []XnfvPortEvent {
+	var events []XnfvPortEvent
+	// a new module starts new baselines
+	if e.status.SFP != nil && e.status.SFP.ModuleID != sfp.ModuleID {
+		e.status.OpticalLanes = nil
+	}
+	lanes := make([]XnfvOpticalLane, 0, len(sfp.Lanes))
+	for _, lane := range sfp.Lanes {
+		state := XnfvOpticalLane{LaneIndex: lane.LaneIndex}
+		first := true
+		for _, previous := range e.status.OpticalLanes {
+			if previous.LaneIndex == lane.LaneIndex {
+				state, first = previous, false
+				break
+			}
+		}
+		state.TxPowerDbm, state.RxPowerDbm = microwattsToDbm(lane.TxPower), microwattsToDbm(lane.RxPower)
+		if first {
+			state.TxBaselineDbm, state.RxBaselineDbm = state.TxPowerDbm, state.RxPowerDbm
+		}
+
+		newEvent := func(eventType XnfvPortEventType, direction string, power float64, detail string) XnfvPortEvent {
+			return XnfvPortEvent{eventType, e.status.XnfvPortKey, lane.LaneIndex, direction, power, detail, now}
+		}
+		txOutOfRange := outOfRange(lane.TxPower, lane.TxPowerMin, lane.TxPowerMax)
+		if txOutOfRange && !state.TxOutOfRange {
+			events = append(events, newEvent(XnfvOpticalThreshold, "tx", state.TxPowerDbm, powerRangeString(lane.TxPowerMin, lane.TxPowerMax)))
+		}
+		rxOutOfRange := outOfRange(lane.RxPower, lane.RxPowerMin, lane.RxPowerMax)
+		if rxOutOfRange && !state.RxOutOfRange {
+			events = append(events, newEvent(XnfvOpticalThreshold, "rx", state.RxPowerDbm, powerRangeString(lane.RxPowerMin, lane.RxPowerMax)))
+		}
+		txDrifted := math.Abs(state.TxPowerDbm-state.TxBaselineDbm) >= config.OpticalDriftDb
+		if txDrifted && !state.TxDrifted {
+			events = append(events, newEvent(XnfvOpticalDrift, "tx", state.TxPowerDbm, "baseline "+strconv.FormatFloat(state.TxBaselineDbm, 'f', 2, 64)+" dBm"))
+		}
+		rxDrifted := math.Abs(state.RxPowerDbm-state.RxBaselineDbm) >= config.OpticalDriftDb
+		if rxDrifted && !state.RxDrifted {
+			events = append(events, newEvent(XnfvOpticalDrift, "rx", state.RxPowerDbm, "baseline "+strconv.FormatFloat(state.RxBaselineDbm, 'f', 2, 64)+" dBm"))
+		}
+		state.TxOutOfRange, state.RxOutOfRange = txOutOfRange, rxOutOfRange
+		state.TxDrifted, state.RxDrifted = txDrifted, rxDrifted
+		lanes = append(lanes, state)
+	}
+
+	e.status.SFP = &sfp
+	e.status.Temperature = float64(sfp.ModuleTemperature) / 1000
+	e.status.OpticalLanes = lanes
+	e.status.LastUpdate = now
+	return events
+}
+
+// microwattsToDbm converts an optical power to dBm, no light is reported
+// as -40 dBm, below what any receiver detects
+func microwattsToDbm(microwatts uint32) float64 {
+	if microwatts == 0 {
+		return -40
+	}
+	return 10 * math.Log10(float64(microwatts)/1000)
+}
+
+// outOfRange compares a power with the module thresholds, a zero
+// threshold is not reported by the module
+func outOfRange(power uint32, min uint32, max uint32) bool {
+	return (min != 0 && power < min) || (max != 0 && power > max)
+}
+
+func powerRangeString(min uint32, max uint32) string {
+	return "thresholds " + strconv.FormatFloat(microwattsToDbm(min), 'f', 2, 64) + " / " +
+		strconv.FormatFloat(microwattsToDbm(max), 'f', 2, 64) + " dBm"
+}
+
+// observeVLAN stores a VLAN record and derives the rates from the previous
+// one. After a restart of the agent the record only becomes the reference
+// of the next one.
+func (t *XnfvPortTable) observeVLAN(key XnfvVLANKey, counters sflow.SFlowVLANCounters, now time.Time) {
+	previous := t.vlans[key]
+	vlan := &XnfvVLANStatus{XnfvVLANKey: key, Counters: counters, LastUpdate: now}
+	t.vlans[key] = vlan
+	if previous == nil {
+		return
+	}
+	interval := now.Sub(previous.LastUpdate).Seconds()
+	if interval <= 0 {
+		return
+	}
+	var deltas xnfvCounterDeltas
+	octets := deltas.delta64(previous.Counters.Octets, counters.Octets)
+	ucast := deltas.delta(previous.Counters.UcastPkts, counters.UcastPkts)
+	multicast := deltas.delta(previous.Counters.MulticastPkts, counters.MulticastPkts)
+	broadcast := deltas.delta(previous.Counters.BroadcastPkts, counters.BroadcastPkts)
+	discards := deltas.delta(previous.Counters.Discards, counters.Discards)
+	if deltas.reset {
+		return
+	}
+	vlan.Interval = interval
+	vlan.BitRate = float64(octets) * 8 / interval
+	vlan.UcastRate = float64(ucast) / interval
+	vlan.MulticastRate = float64(multicast) / interval
+	vlan.BroadcastRate = float64(broadcast) / interval
+	vlan.DiscardRate = float64(discards) / interval
+}
+
+// AgeOut removes the ports and VLANs not reported since MaxAge before now
+func (t *XnfvPortTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, entry := range t.ports {
+		if now.Sub(entry.status.LastUpdate) > t.config.MaxAge {
+			delete(t.ports, key)
+		}
+	}
+	for key, vlan := range t.vlans {
+		if now.Sub(vlan.LastUpdate) > t.config.MaxAge {
+			delete(t.vlans, key)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Port Query API
+// ****************************************************************************************************
+
+// Ports returns the status of the ports, optionally of one agent only
+func (t *XnfvPortTable) Ports(agent string) []XnfvPortStatus {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var ports []XnfvPortStatus
+	for key, entry := range t.ports {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		status := entry.status
+		status.OpticalLanes = append([]XnfvOpticalLane(nil), entry.status.OpticalLanes...)
+		ports = append(ports, status)
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Agent != ports[j].Agent {
+			return ports[i].Agent < ports[j].Agent
+		}
+		return ports[i].IfIndex < ports[j].IfIndex
+	})
+	return ports
+}
+
+// ServeHTTP answers GET /ports[?agent=] with the port status as JSON
+func (t *XnfvPortTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.Ports(r.URL.Query().Get("agent")))
+}
+
+// VLANs returns the VLAN rates, optionally of one agent only
+func (t *XnfvPortTable) VLANs(agent string) []XnfvVLANStatus {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	var vlans []XnfvVLANStatus
+	for key, vlan := range t.vlans {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		vlans = append(vlans, *vlan)
+	}
+	sort.Slice(vlans, func(i, j int) bool {
+		if vlans[i].Agent != vlans[j].Agent {
+			return vlans[i].Agent < vlans[j].Agent
+		}
+		return vlans[i].VlanID < vlans[j].VlanID
+	})
+	return vlans
+}
+
+// ServeVLANs answers GET /vlans[?agent=] with the VLAN rates as JSON
+func (t *XnfvPortTable) ServeVLANs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.VLANs(r.URL.Query().Get("agent")))
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// xnfvTestCounterDatagram wraps the counter records of one data source in
+// a datagram of the agent 192.0.2.1
+func xnfvTestCounterDatagram(ifIndex uint32, records ...sflow.SFlowRecord) sflow.Datagram {
+	return sflow.Datagram{
+		AgentAddress: net.ParseIP("192.0.2.1"),
+		CounterSamples: []sflow.SFlowCounterSample{
+			{SourceIDIndex: sflow.SFlowSourceValue(ifIndex), Records: records},
+		},
+	}
+}
+
+func xnfvTestEventTypes(events []XnfvPortEvent) []XnfvPortEventType {
+	var types []XnfvPortEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestXnfvPortTableLACP(t *testing.T) {
+	const inSync = sflow.SFlowLACPActivity | sflow.SFlowLACPAggregation | sflow.SFlowLACPSynchronization | sflow.SFlowLACPCollecting | sflow.SFlowLACPDistributing
+	lacp := func(aggregator uint32, partner sflow.SFlowLACPState) sflow.SFlowRecord {
+		return sflow.SFlowLACPCounters{AttachedAggID: aggregator, ActorOperState: inSync, PartnerOperState: partner}
+	}
+	table := NewXnfvPortTable(DefaultXnfvPortTableConfig())
+	steps := []struct {
+		record sflow.SFlowRecord
+		want   []XnfvPortEventType
+	}{
+		{lacp(1, inSync), nil},
+		{lacp(1, inSync), nil},
+		// the partner stops distributing
+		{lacp(1, inSync&^sflow.SFlowLACPDistributing), []XnfvPortEventType{XnfvLACPOutOfSync}},
+		// reported once
+		{lacp(1, inSync&^sflow.SFlowLACPDistributing), nil},
+		{lacp(1, inSync), nil},
+		{lacp(2, inSync), nil},
+		// the fourth change within the window is churn, out of sync again
+		{lacp(2, sflow.SFlowLACPActivity), []XnfvPortEventType{XnfvLAGMemberChurn, XnfvLACPOutOfSync}},
+		{lacp(2, inSync), nil},
+	}
+	for i, step := range steps {
+		events := table.ObserveDatagram(xnfvTestCounterDatagram(3, step.record), xnfvTestEpoch.Add(time.Duration(i)*time.Minute))
+		types := xnfvTestEventTypes(events)
+		if len(types) != len(step.want) {
+			t.Fatalf("step %d: events %v, want %v", i, types, step.want)
+		}
+		for j := range types {
+			if types[j] != step.want[j] || events[j].Port != (XnfvPortKey{"192.0.2.1", 3}) {
+				t.Fatalf("step %d: events %+v, want %v", i, events, step.want)
+			}
+		}
+	}
+	ports := table.Ports("192.0.2.1")
+	if len(ports) != 1 || !ports[0].LACPInSync || ports[0].LAGChanges != 5 {
+		t.Errorf("ports %+v", ports)
+	}
+}
+
+func TestXnfvPortTableSFP(t *testing.T) {
+	sfp := func(module uint32, txPower uint32, rxPower uint32) sflow.SFlowRecord {
+		return sflow.SFlowSFPCounters{ModuleID: module, ModuleTemperature: 41500, Lanes: []sflow.SFlowSFPLane{
+			{LaneIndex: 1, TxPower: txPower, TxPowerMin: 100, TxPowerMax: 2000, RxPower: rxPower, RxPowerMin: 50},
+		}}
+	}
+	table := NewXnfvPortTable(DefaultXnfvPortTableConfig())
+	steps := []struct {
+		record sflow.SFlowRecord
+		want   []XnfvPortEventType
+	}{
+		// 1 mW, 0 dBm
+		{sfp(1, 1000, 1000), nil},
+		// rx -3 dBm, a 3 dB drift
+		{sfp(1, 1000, 500), []XnfvPortEventType{XnfvOpticalDrift}},
+		{sfp(1, 1000, 500), nil},
+		// rx below its threshold, tx above it and drifted
+		{sfp(1, 2500, 40), []XnfvPortEventType{XnfvOpticalThreshold, XnfvOpticalThreshold, XnfvOpticalDrift}},
+		{sfp(1, 2500, 40), nil},
+		// a new module starts from new baselines
+		{sfp(2, 1500, 800), nil},
+	}
+	for i, step := range steps {
+		events := table.ObserveDatagram(xnfvTestCounterDatagram(3, step.record), xnfvTestEpoch.Add(time.Duration(i)*time.Minute))
+		types := xnfvTestEventTypes(events)
+		if len(types) != len(step.want) {
+			t.Fatalf("step %d: events %+v, want %v", i, events, step.want)
+		}
+		for j := range types {
+			if types[j] != step.want[j] {
+				t.Fatalf("step %d: events %+v, want %v", i, events, step.want)
+			}
+		}
+	}
+	ports := table.Ports("")
+	if len(ports) != 1 || ports[0].Temperature != 41.5 || len(ports[0].OpticalLanes) != 1 {
+		t.Fatalf("ports %+v", ports)
+	}
+	if lane := ports[0].OpticalLanes[0]; lane.TxDrifted || lane.RxOutOfRange || lane.TxBaselineDbm != lane.TxPowerDbm {
+		t.Errorf("lane of the new module %+v", lane)
+	}
+}
+
+func TestXnfvMicrowattsToDbm(t *testing.T) {
+	for microwatts, dbm := range map[uint32]float64{0: -40, 1: -30, 1000: 0, 10000: 10} {
+		if got := microwattsToDbm(microwatts); got != dbm {
+			t.Errorf("%d uW: %v dBm, want %v", microwatts, got, dbm)
+		}
+	}
+}
+
+func TestXnfvPortTableVLANRates(t *testing.T) {
+	vlan := func(octets uint64, ucast uint32, discards uint32) sflow.SFlowRecord {
+		return sflow.SFlowVLANCounters{VlanID: 100, Octets: octets, UcastPkts: ucast, MulticastPkts: 10, BroadcastPkts: 20, Discards: discards}
+	}
+	table := NewXnfvPortTable(DefaultXnfvPortTableConfig())
+	steps := []struct {
+		record    sflow.SFlowRecord
+		interval  float64
+		bitRate   float64
+		ucastRate float64
+	}{
+		{vlan(1000, 0xFFFFFFFF-1099, 0), 0, 0, 0},
+		{vlan(126000, 0xFFFFFFFF-99, 0), 10, 100000, 100},
+		// the unicast counter wraps
+		{vlan(251000, 900, 0), 10, 100000, 100},
+		// the agent restarted, the interval is skipped
+		{vlan(5000, 50, 0), 0, 0, 0},
+		{vlan(130000, 1050, 5), 20, 50000, 50},
+	}
+	at := time.Duration(0)
+	for i, step := range steps {
+		at += time.Duration(step.interval) * time.Second
+		if i == 3 {
+			at += 10 * time.Second
+		}
+		table.ObserveDatagram(xnfvTestCounterDatagram(3, step.record), xnfvTestEpoch.Add(at))
+		vlans := table.VLANs("192.0.2.1")
+		if len(vlans) != 1 || vlans[0].VlanID != 100 {
+			t.Fatalf("step %d: vlans %+v", i, vlans)
+		}
+		if v := vlans[0]; v.Interval != step.interval || v.BitRate != step.bitRate || v.UcastRate != step.ucastRate || v.MulticastRate != 0 {
+			t.Errorf("step %d: rates %+v, want interval %v, %v bps, %v unicast pps", i, v, step.interval, step.bitRate, step.ucastRate)
+		}
+	}
+	if discardRate := table.VLANs("")[0].DiscardRate; discardRate != 0.25 {
+		t.Errorf("discard rate %v", discardRate)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ****************************************************************************************************
+//  Histograms
+// ****************************************************************************************************
+
+// xnfvHistogram counts weighted values in buckets of growing upper bounds,
+// the values above the last bound go to an overflow bucket
+type xnfvHistogram struct {
+	bounds []uint64
+	counts []uint64
+	total  uint64
+	sum    float64
+	max    uint64
+}
+
+// exponentialBounds returns count bounds starting at first, each twice
+// the previous one
+func exponentialBounds(first uint64, count int) []uint64 {
+	bounds := make([]uint64, count)
+	for i := range bounds {
+		bounds[i] = first << uint(i)
+	}
+	return bounds
+}
+
+func newXnfvHistogram(bounds []uint64) *xnfvHistogram {
+	return &xnfvHistogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *xnfvHistogram) add(value uint64, weight uint64) {
+	bucket := sort.Search(len(h.bounds), func(i int) bool { return value <= h.bounds[i] })
+	h.counts[bucket] += weight
+	h.total += weight
+	h.sum += float64(value) * float64(weight)
+	if value > h.max {
+		h.max = value
+	}
+}
+
+func (h *xnfvHistogram) merge(other *xnfvHistogram) {
+	for i, count := range other.counts {
+		h.counts[i] += count
+	}
+	h.total += other.total
+	h.sum += other.sum
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the p-th
+// weighted value, or the largest value seen for the overflow bucket
+func (h *xnfvHistogram) percentile(p float64) uint64 {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(p * float64(h.total))
+	if rank == 0 {
+		rank = 1
+	}
+	var cumulative uint64
+	for i, count := range h.counts {
+		cumulative += count
+		if cumulative >= rank {
+			if i < len(h.bounds) && h.bounds[i] < h.max {
+				return h.bounds[i]
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// XnfvHistogramBucket is the weight of the values up to UpperBound (and
+// above the previous bound), the overflow bucket has no UpperBound
+type XnfvHistogramBucket struct {
+	UpperBound uint64 `json:"upperBound,omitempty"`
+	Count      uint64 `json:"count"`
+}
+
+// XnfvHistogramSummary is a histogram with its percentiles. The counts
+// are weighted by the sampling rate, they estimate packets.
+type XnfvHistogramSummary struct {
+	Packets uint64                `json:"packets"`
+	Mean    float64               `json:"mean"`
+	P50     uint64                `json:"p50"`
+	P90     uint64                `json:"p90"`
+	P99     uint64                `json:"p99"`
+	Max     uint64                `json:"max"`
+	Buckets []XnfvHistogramBucket `json:"buckets,omitempty"`
+}
+
+func (h *xnfvHistogram) summary() XnfvHistogramSummary {
+	summary := XnfvHistogramSummary{
+		Packets: h.total,
+		P50:     h.percentile(0.50),
+		P90:     h.percentile(0.90),
+		P99:     h.percentile(0.99),
+		Max:     h.max,
+	}
+	if h.total > 0 {
+		summary.Mean = h.sum / float64(h.total)
+	}
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		bucket := XnfvHistogramBucket{Count: count}
+		if i < len(h.bounds) {
+			bucket.UpperBound = h.bounds[i]
+		}
+		summary.Buckets = append(summary.Buckets, bucket)
+	}
+	return summary
+}
+
+// ****************************************************************************************************
+//  Egress Queue Depth and Transit Delay
+// ****************************************************************************************************
+
+type XnfvQueueTableConfig struct {
+	// the histograms cover the current and the previous Window
+	Window time.Duration
+	// MaxAge removes ports that have not been sampled for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvQueueTableConfig() XnfvQueueTableConfig {
+	return XnfvQueueTableConfig{
+		Window: time.Minute,
+		MaxAge: 10 * time.Minute,
+	}
+}
+
+var (
+	// 1 KiB ... 64 MiB
+	xnfvQueueDepthBounds = exponentialBounds(1024, 17)
+	// 1 us ... ~1 s
+	xnfvTransitDelayBounds = exponentialBounds(1000, 21)
+)
+
+// XnfvQueuePortKey identifies an egress port by the agent and the output
+// interface of the samples
+type XnfvQueuePortKey struct {
+	Agent   string `json:"agent"`
+	IfIndex uint32 `json:"ifIndex"`
+}
+
+// XnfvQueuePort is the queue depth (bytes) and transit delay (ns)
+// distribution of the packets sampled leaving a port
+type XnfvQueuePort struct {
+	XnfvQueuePortKey
+	SwitchDataPath string `json:"switchDataPath,omitempty"`
+	// QueuePackets estimates the packets sent by egress queue
+	QueuePackets map[uint32]uint64    `json:"queuePackets,omitempty"`
+	QueueDepth   XnfvHistogramSummary `json:"queueDepth"`
+	TransitDelay XnfvHistogramSummary `json:"transitDelay"`
+	LastUpdate   time.Time            `json:"lastUpdate"`
+}
+
+type xnfvQueueWindow struct {
+	start        time.Time
+	queueDepth   *xnfvHistogram
+	transitDelay *xnfvHistogram
+	queuePackets map[uint32]uint64
+}
+
+func newXnfvQueueWindow(start time.Time) *xnfvQueueWindow {
+	return &xnfvQueueWindow{
+		start:        start,
+		queueDepth:   newXnfvHistogram(xnfvQueueDepthBounds),
+		transitDelay: newXnfvHistogram(xnfvTransitDelayBounds),
+		queuePackets: map[uint32]uint64{},
+	}
+}
+
+type xnfvQueueEntry struct {
+	switchDataPath string
+	current        *xnfvQueueWindow
+	previous       *xnfvQueueWindow
+	lastUpdate     time.Time
+}
+
+// XnfvQueueTable builds per port histograms of the egress queue depth and
+// transit delay that switch ASICs report with the sampled packets. It is
+// safe for concurrent use.
+type XnfvQueueTable struct {
+	config XnfvQueueTableConfig
+	mutex  sync.RWMutex
+	ports  map[XnfvQueuePortKey]*xnfvQueueEntry
+}
+
+func NewXnfvQueueTable(config XnfvQueueTableConfig) *XnfvQueueTable {
+	return &XnfvQueueTable{config: config, ports: map[XnfvQueuePortKey]*xnfvQueueEntry{}}
+}
+
+// Observe adds the queue records of a flow sample to the histograms of its
+// output port
+func (t *XnfvQueueTable) Observe(flowSample XnfvFlowSample) {
+	if !flowSample.HasEgressQueue && !flowSample.HasTransitDelay && !flowSample.HasQueueDepth {
+		return
+	}
+	// the two high bits flag discarded or multiple output ports
+	if flowSample.OutputInterface == 0 || flowSample.OutputInterface>>30 != 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := XnfvQueuePortKey{ipString(flowSample.AgentAddress), flowSample.OutputInterface}
+	entry, ok := t.ports[key]
+	if !ok {
+		entry = &xnfvQueueEntry{current: newXnfvQueueWindow(flowSample.Timestamp)}
+		t.ports[key] = entry
+	}
+	if elapsed := flowSample.Timestamp.Sub(entry.current.start); elapsed >= t.config.Window {
+		entry.previous = entry.current
+		if elapsed >= 2*t.config.Window {
+			entry.previous = nil
+		}
+		entry.current = newXnfvQueueWindow(flowSample.Timestamp)
+	}
+	if flowSample.SwitchDataPath != nil {
+		entry.switchDataPath = flowSample.SwitchDataPathString()
+	}
+	entry.lastUpdate = flowSample.Timestamp
+
+	weight := uint64(flowSample.SamplingRate)
+	if weight == 0 {
+		weight = 1
+	}
+	if flowSample.HasEgressQueue {
+		entry.current.queuePackets[flowSample.EgressQueue] += weight
+	}
+	if flowSample.HasQueueDepth {
+		entry.current.queueDepth.add(uint64(flowSample.QueueDepthBytes), weight)
+	}
+	if flowSample.HasTransitDelay {
+		entry.current.transitDelay.add(uint64(flowSample.TransitDelayNs), weight)
+	}
+}
+
+// AgeOut removes the ports not sampled since MaxAge before now
+func (t *XnfvQueueTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, entry := range t.ports {
+		if now.Sub(entry.lastUpdate) > t.config.MaxAge {
+			delete(t.ports, key)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Queue Query API
+// ****************************************************************************************************
+
+// Ports returns the queue statistics of the ports, optionally of one agent
+// only, the most congested (by 99th percentile queue depth) first
+func (t *XnfvQueueTable) Ports(agent string) []XnfvQueuePort {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	ports := []XnfvQueuePort{}
+	for key, entry := range t.ports {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		queueDepth := newXnfvHistogram(xnfvQueueDepthBounds)
+		transitDelay := newXnfvHistogram(xnfvTransitDelayBounds)
+		queuePackets := map[uint32]uint64{}
+		for _, window := range []*xnfvQueueWindow{entry.previous, entry.current} {
+			if window == nil {
+				continue
+			}
+			queueDepth.merge(window.queueDepth)
+			transitDelay.merge(window.transitDelay)
+			for queue, packets := range window.queuePackets {
+				queuePackets[queue] += packets
+			}
+		}
+		ports = append(ports, XnfvQueuePort{
+			XnfvQueuePortKey: key,
+			SwitchDataPath:   entry.switchDataPath,
+			QueuePackets:     queuePackets,
+			QueueDepth:       queueDepth.summary(),
+			TransitDelay:     transitDelay.summary(),
+			LastUpdate:       entry.lastUpdate,
+		})
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].QueueDepth.P99 != ports[j].QueueDepth.P99 {
+			return ports[i].QueueDepth.P99 > ports[j].QueueDepth.P99
+		}
+		return ports[i].TransitDelay.P99 > ports[j].TransitDelay.P99
+	})
+	return ports
+}
+
+// ServeHTTP answers GET /queues[?agent=] with the queue statistics as JSON
+func (t *XnfvQueueTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.Ports(r.URL.Query().Get("agent")))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExponentialBounds(t *testing.T) {
+	if bounds := exponentialBounds(1000, 4); !reflect.DeepEqual(bounds, []uint64{1000, 2000, 4000, 8000}) {
+		t.Fatalf("bounds %v", bounds)
+	}
+}
+
+func TestXnfvHistogram(t *testing.T) {
+	histogram := newXnfvHistogram([]uint64{10, 20, 40})
+	histogram.add(5, 1)
+	histogram.add(15, 2)
+	histogram.add(20, 1)
+	histogram.add(30, 5)
+	histogram.add(100, 1)
+
+	percentiles := []struct {
+		p    float64
+		want uint64
+	}{
+		{0, 10},
+		{0.1, 10},
+		{0.4, 20},
+		{0.5, 40},
+		{0.9, 40},
+		// the overflow bucket reports the largest value
+		{1, 100},
+	}
+	for _, percentile := range percentiles {
+		if got := histogram.percentile(percentile.p); got != percentile.want {
+			t.Errorf("percentile(%v) = %d, want %d", percentile.p, got, percentile.want)
+		}
+	}
+
+	want := XnfvHistogramSummary{
+		Packets: 10,
+		Mean:    (5 + 30 + 20 + 150 + 100) / 10.0,
+		P50:     40,
+		P90:     40,
+		P99:     40,
+		Max:     100,
+		Buckets: []XnfvHistogramBucket{{10, 1}, {20, 3}, {40, 5}, {0, 1}},
+	}
+	if summary := histogram.summary(); !reflect.DeepEqual(summary, want) {
+		t.Fatalf("summary %+v, want %+v", summary, want)
+	}
+
+	// the bound of a bucket is capped to the largest value it holds
+	small := newXnfvHistogram([]uint64{10, 20, 40})
+	small.add(12, 3)
+	if p := small.percentile(0.5); p != 12 {
+		t.Fatalf("percentile %d, want 12", p)
+	}
+	histogram.merge(small)
+	if histogram.total != 13 || histogram.counts[1] != 6 || histogram.max != 100 {
+		t.Fatalf("merged %+v", histogram)
+	}
+	if summary := newXnfvHistogram([]uint64{10}).summary(); !reflect.DeepEqual(summary, XnfvHistogramSummary{}) {
+		t.Fatalf("empty summary %+v", summary)
+	}
+}
+
+// xnfvTestQueueSample is a packet sampled 1 in 100 leaving the port 5 of
+// the agent 192.0.2.1
+func xnfvTestQueueSample(at time.Duration, queue uint32, depth uint32, delay uint32) XnfvFlowSample {
+	return XnfvFlowSample{
+		Timestamp:       xnfvTestEpoch.Add(at),
+		AgentAddress:    net.ParseIP("192.0.2.1"),
+		OutputInterface: 5,
+		SamplingRate:    100,
+		EgressQueue:     queue,
+		QueueDepthBytes: depth,
+		TransitDelayNs:  delay,
+		HasEgressQueue:  true,
+		HasQueueDepth:   true,
+		HasTransitDelay: true,
+	}
+}
+
+func TestXnfvQueueTableWindows(t *testing.T) {
+	table := NewXnfvQueueTable(DefaultXnfvQueueTableConfig())
+	table.Observe(xnfvTestQueueSample(0, 0, 1000, 900))
+	table.Observe(xnfvTestQueueSample(30*time.Second, 1, 3000, 5000))
+	// a new window, the previous one is still reported
+	table.Observe(xnfvTestQueueSample(70*time.Second, 1, 3000, 5000))
+
+	ports := table.Ports("192.0.2.1")
+	if len(ports) != 1 || ports[0].XnfvQueuePortKey != (XnfvQueuePortKey{"192.0.2.1", 5}) {
+		t.Fatalf("ports %+v", ports)
+	}
+	port := ports[0]
+	if !reflect.DeepEqual(port.QueuePackets, map[uint32]uint64{0: 100, 1: 200}) {
+		t.Fatalf("queue packets %v", port.QueuePackets)
+	}
+	if port.QueueDepth.Packets != 300 || port.QueueDepth.P50 != 3000 || port.QueueDepth.Max != 3000 {
+		t.Fatalf("queue depth %+v", port.QueueDepth)
+	}
+	if port.TransitDelay.Packets != 300 || port.TransitDelay.P50 != 5000 || port.TransitDelay.Buckets[0] != (XnfvHistogramBucket{1000, 100}) {
+		t.Fatalf("transit delay %+v", port.TransitDelay)
+	}
+
+	// after an idle window only the current one is left
+	table.Observe(xnfvTestQueueSample(200*time.Second, 2, 500, 800))
+	port = table.Ports("")[0]
+	if port.QueueDepth.Packets != 100 || !reflect.DeepEqual(port.QueuePackets, map[uint32]uint64{2: 100}) {
+		t.Fatalf("port after the idle window %+v", port)
+	}
+}
+
+func TestXnfvQueueTableQuery(t *testing.T) {
+	table := NewXnfvQueueTable(DefaultXnfvQueueTableConfig())
+	// without queue records, discarded or sent to multiple ports
+	table.Observe(XnfvFlowSample{Timestamp: xnfvTestEpoch, OutputInterface: 5})
+	discarded := xnfvTestQueueSample(0, 0, 1000, 1000)
+	discarded.OutputInterface = 1 << 30
+	table.Observe(discarded)
+	if ports := table.Ports(""); len(ports) != 0 {
+		t.Fatalf("ports %+v", ports)
+	}
+
+	table.Observe(xnfvTestQueueSample(0, 0, 1000, 1000))
+	congested := xnfvTestQueueSample(time.Minute, 0, 500000, 1000)
+	congested.OutputInterface = 6
+	table.Observe(congested)
+	ports := table.Ports("")
+	if len(ports) != 2 || ports[0].IfIndex != 6 || ports[1].IfIndex != 5 {
+		t.Fatalf("ports %+v, want the most congested first", ports)
+	}
+	if len(table.Ports("192.0.2.2")) != 0 {
+		t.Fatalf("ports of another agent")
+	}
+
+	table.AgeOut(xnfvTestEpoch.Add(10*time.Minute + time.Second))
+	if ports := table.Ports(""); len(ports) != 1 || ports[0].IfIndex != 6 {
+		t.Fatalf("ports after AgeOut %+v", ports)
+	}
+}
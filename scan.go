@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Port Scan And Host Sweep Detection
+// ****************************************************************************************************
+
+// XnfvScanType names the kind of scan an XnfvScanEvent reports
+type XnfvScanType string
+
+const (
+	XnfvScanHostSweep XnfvScanType = "host-sweep" // horizontal: one port, many hosts
+	XnfvScanPortScan  XnfvScanType = "port-scan"  // vertical: one host, many ports
+)
+
+type XnfvScanConfig struct {
+	Window time.Duration
+
+	// HostSweepTargets is the estimated number of distinct destination IPs
+	// a source must probe within a window to be reported as a sweep
+	HostSweepTargets float64
+	// PortScanPorts is the estimated number of distinct destination ports
+	// per destination a source must probe to be reported as a port scan
+	PortScanPorts float64
+
+	// ProbesPerTarget is how many packets a scanner is assumed to send to
+	// each target. Distinct counts seen in samples are scaled by
+	// SamplingRate / ProbesPerTarget to estimate the real target count.
+	ProbesPerTarget float64
+	// MinSampledTargets is the number of distinct targets that must be
+	// seen in the samples themselves before they are scaled, at 1:1024 a
+	// single sampled packet would otherwise stand for 1024 targets
+	MinSampledTargets float64
+
+	// Precision of the HyperLogLog sketches kept per source
+	Precision uint8
+	// MaxSources bounds the number of sources tracked per window
+	MaxSources int
+}
+
+func DefaultXnfvScanConfig() XnfvScanConfig {
+	return XnfvScanConfig{
+		Window:            60 * time.Second,
+		HostSweepTargets:  256,
+		PortScanPorts:     512,
+		ProbesPerTarget:   1,
+		MinSampledTargets: 8,
+		Precision:         10,
+		MaxSources:        100000,
+	}
+}
+
+// XnfvScanEvent names the scanning host, where it is attached and how
+// many targets it probed
+type XnfvScanEvent struct {
+	Type             XnfvScanType `json:"type"`
+	Scanner          string       `json:"scanner"`
+	ScannerMac       string       `json:"scannerMac"`
+	VNI              uint32       `json:"vni"`
+	SwitchDataPath   string       `json:"switchDataPath"`
+	PortName         string       `json:"portName"`
+	OfPort           uint32       `json:"ofPort"`
+	TopTarget        string       `json:"topTarget,omitempty"`
+	SampledTargets   float64      `json:"sampledTargets"`
+	EstimatedTargets float64      `json:"estimatedTargets"`
+	WindowStart      time.Time    `json:"windowStart"`
+	WindowEnd        time.Time    `json:"windowEnd"`
+}
+
+// Sources are tracked per VNI since tenant networks may reuse addresses
+type xnfvScanSourceKey struct {
+	ip  string
+	vni uint32
+}
+
+type xnfvScanSource struct {
+	dstIPs       *xnfvHLL // distinct destination IPs
+	dstIPPorts   *xnfvHLL // distinct (destination IP, destination port) pairs
+	samplingRate float64
+	mac          string
+	dataPath     string
+	portName     string
+	ofPort       uint32
+	topTargets   map[string]int
+}
+
+// XnfvScanDetector keeps per-source cardinality sketches of the probes
+// seen in flow samples and reports horizontal sweeps and vertical scans.
+type XnfvScanDetector struct {
+	config      XnfvScanConfig
+	windowStart time.Time
+	sources     map[xnfvScanSourceKey]*xnfvScanSource
+}
+
+func NewXnfvScanDetector(config XnfvScanConfig) *XnfvScanDetector {
+	return &XnfvScanDetector{config: config, sources: map[xnfvScanSourceKey]*xnfvScanSource{}}
+}
+
+// isProbe reports whether a sampled packet looks like a connection
+// attempt: a bare TCP SYN, an empty UDP datagram or an ICMP echo request.
+// UDP requests carry a payload (a DNS query, an NTP poll), scanners send
+// the ports they have no protocol probe for empty datagrams.
+func isProbe(s XnfvFlowSample) bool {
+	switch s.IPProtocol {
+	case layers.IPProtocolTCP:
+		return s.TCPFlags&xnfvTCPFlagSYN != 0 && s.TCPFlags&xnfvTCPFlagACK == 0
+	case layers.IPProtocolUDP:
+		return isEmptyUDP(s)
+	case layers.IPProtocolICMPv4:
+		return s.ICMPType == layers.ICMPv4TypeEchoRequest
+	case layers.IPProtocolICMPv6:
+		return s.ICMPType == layers.ICMPv6TypeEchoRequest
+	}
+	return false
+}
+
+// isEmptyUDP reports whether the sampled header holds a UDP datagram
+// without payload, the inner one of a VXLAN encapsulation. The samples
+// without a header do not tell.
+func isEmptyUDP(s XnfvFlowSample) bool {
+	if s.Header == nil {
+		return false
+	}
+	var udp *layers.UDP
+	for _, layer := range s.Header.Layers() {
+		if l, ok := layer.(*layers.UDP); ok {
+			udp = l
+		}
+	}
+	return udp != nil && udp.Length == 8
+}
+
+// Observe accounts one flow sample and returns the events of the previous
+// window when the sample starts a new one.
+func (d *XnfvScanDetector) Observe(s XnfvFlowSample) []XnfvScanEvent {
+	var events []XnfvScanEvent
+	if d.windowStart.IsZero() {
+		d.windowStart = s.Timestamp
+	} else if s.Timestamp.Sub(d.windowStart) >= d.config.Window {
+		events = d.Flush(s.Timestamp)
+	}
+	if s.SrcIP == nil || s.DstIP == nil || !isProbe(s) {
+		return events
+	}
+
+	key := xnfvScanSourceKey{s.SrcIP.String(), s.VNI}
+	source, ok := d.sources[key]
+	if !ok {
+		if len(d.sources) >= d.config.MaxSources {
+			return events
+		}
+		source = &xnfvScanSource{
+			dstIPs:     newXnfvHLL(d.config.Precision),
+			dstIPPorts: newXnfvHLL(d.config.Precision),
+			topTargets: map[string]int{},
+		}
+		d.sources[key] = source
+	}
+	source.samplingRate = s.EstimatedPackets()
+	source.mac = s.SrcMac.String()
+	if len(s.SwitchDataPath) > 0 {
+		source.dataPath, source.portName, source.ofPort = s.SwitchDataPathString(), s.PortName, s.OfPort
+	}
+
+	dst := s.DstIP.To16()
+	source.dstIPs.Add(dst)
+	pair := make([]byte, len(dst)+2)
+	copy(pair, dst)
+	binary.BigEndian.PutUint16(pair[len(dst):], s.DstPort)
+	source.dstIPPorts.Add(pair)
+	if len(source.topTargets) < 64 || source.topTargets[s.DstIP.String()] > 0 {
+		source.topTargets[s.DstIP.String()]++
+	}
+	return events
+}
+
+// Tick closes the current window once it is Window long, so that a scan
+// followed by a quiet period is reported with the window it happened in.
+// It is called on a timer.
+func (d *XnfvScanDetector) Tick(now time.Time) []XnfvScanEvent {
+	if d.windowStart.IsZero() || now.Sub(d.windowStart) < d.config.Window {
+		return nil
+	}
+	return d.Flush(now)
+}
+
+// Flush evaluates the current window and starts a new one at now
+func (d *XnfvScanDetector) Flush(now time.Time) []XnfvScanEvent {
+	var events []XnfvScanEvent
+	for key, source := range d.sources {
+		scale := source.samplingRate / d.config.ProbesPerTarget
+		if scale < 1 {
+			scale = 1
+		}
+		sampledHosts := source.dstIPs.Estimate()
+		sampledPairs := source.dstIPPorts.Estimate()
+
+		newEvent := func(scanType XnfvScanType, sampled float64) XnfvScanEvent {
+			return XnfvScanEvent{
+				Type:             scanType,
+				Scanner:          key.ip,
+				ScannerMac:       source.mac,
+				VNI:              key.vni,
+				SwitchDataPath:   source.dataPath,
+				PortName:         source.portName,
+				OfPort:           source.ofPort,
+				SampledTargets:   sampled,
+				EstimatedTargets: sampled * scale,
+				WindowStart:      d.windowStart,
+				WindowEnd:        now,
+			}
+		}
+
+		if sampledHosts >= d.config.MinSampledTargets && sampledHosts*scale >= d.config.HostSweepTargets {
+			events = append(events, newEvent(XnfvScanHostSweep, sampledHosts))
+		}
+		// ports per destination, pairs spread over few hosts is a vertical scan
+		portsPerHost := sampledPairs / maxFloat(sampledHosts, 1)
+		if portsPerHost >= d.config.MinSampledTargets && portsPerHost*scale >= d.config.PortScanPorts {
+			event := newEvent(XnfvScanPortScan, portsPerHost)
+			event.TopTarget = topTarget(source.topTargets)
+			events = append(events, event)
+		}
+	}
+	d.windowStart = now
+	d.sources = map[xnfvScanSourceKey]*xnfvScanSource{}
+	return events
+}
+
+func topTarget(targets map[string]int) string {
+	top, topCount := "", 0
+	for target, count := range targets {
+		if count > topCount {
+			top, topCount = target, count
+		}
+	}
+	return top
+}
+
+func maxFloat(a float64, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// xnfvTestUDPHeader builds the sampled header of a UDP datagram
+func xnfvTestUDPHeader(t *testing.T, src net.IP, dst net.IP, dstPort uint16, payload []byte) gopacket.Packet {
+	ethernet := &layers.Ethernet{SrcMAC: net.HardwareAddr{2, 0, 0, 0, 0, 1}, DstMAC: net.HardwareAddr{2, 0, 0, 0, 0, 2}, EthernetType: layers.EthernetTypeIPv4}
+	ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: src, DstIP: dst}
+	udp := &layers.UDP{SrcPort: 53000, DstPort: layers.UDPPort(dstPort)}
+	udp.SetNetworkLayerForChecksum(ip)
+	buffer := gopacket.NewSerializeBuffer()
+	options := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buffer, options, ethernet, ip, udp, gopacket.Payload(payload)); err != nil {
+		t.Fatal(err)
+	}
+	return gopacket.NewPacket(buffer.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+}
+
+func xnfvTestUDPSample(t *testing.T, at time.Duration, dst string, dstPort uint16, payload []byte) XnfvFlowSample {
+	header := xnfvTestUDPHeader(t, net.ParseIP("192.0.2.1").To4(), net.ParseIP(dst).To4(), dstPort, payload)
+	s := newXnfvFlowSample(header, 1024, 1, 2, 0)
+	s.Timestamp = xnfvTestEpoch.Add(at)
+	return s
+}
+
+func TestXnfvIsProbe(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     XnfvFlowSample
+		probe bool
+	}{
+		{"dns query", xnfvTestUDPSample(t, 0, "10.0.0.1", 53, make([]byte, 32)), false},
+		{"empty udp", xnfvTestUDPSample(t, 0, "10.0.0.1", 161, nil), true},
+		{"udp summary record", XnfvFlowSample{IPProtocol: layers.IPProtocolUDP}, false},
+		{"syn", XnfvFlowSample{IPProtocol: layers.IPProtocolTCP, TCPFlags: xnfvTCPFlagSYN}, true},
+		{"syn-ack", XnfvFlowSample{IPProtocol: layers.IPProtocolTCP, TCPFlags: xnfvTCPFlagSYN | xnfvTCPFlagACK}, false},
+		{"echo request", XnfvFlowSample{IPProtocol: layers.IPProtocolICMPv4, ICMPType: layers.ICMPv4TypeEchoRequest}, true},
+		{"echo reply", XnfvFlowSample{IPProtocol: layers.IPProtocolICMPv4, ICMPType: layers.ICMPv4TypeEchoReply}, false},
+	}
+	for _, test := range tests {
+		if probe := isProbe(test.s); probe != test.probe {
+			t.Errorf("%s: probe %v, want %v", test.name, probe, test.probe)
+		}
+	}
+}
+
+// A single sampled DNS query at 1:1024 stands for neither a host sweep of
+// 1024 hosts nor a scan of 1024 ports
+func TestXnfvScanDetectorSingleSample(t *testing.T) {
+	for _, payload := range [][]byte{make([]byte, 32), nil} {
+		d := NewXnfvScanDetector(DefaultXnfvScanConfig())
+		d.Observe(xnfvTestUDPSample(t, 0, "10.0.0.53", 53, payload))
+		if events := d.Flush(xnfvTestEpoch.Add(time.Minute)); len(events) != 0 {
+			t.Errorf("payload %d bytes: %+v", len(payload), events)
+		}
+	}
+}
+
+func xnfvTestSynSample(at time.Duration, dst string, dstPort uint16, samplingRate uint32) XnfvFlowSample {
+	s := xnfvTestTCPSample(at, "192.0.2.1", dst, xnfvTCPFlagSYN)
+	s.DstPort, s.SamplingRate = dstPort, samplingRate
+	return s
+}
+
+func TestXnfvScanDetector(t *testing.T) {
+	tests := []struct {
+		name         string
+		hosts        int
+		ports        int
+		samplingRate uint32
+		want         []XnfvScanType
+	}{
+		{"host sweep", 300, 1, 1, []XnfvScanType{XnfvScanHostSweep}},
+		{"sampled host sweep", 40, 1, 1024, []XnfvScanType{XnfvScanHostSweep}},
+		{"few sampled hosts", 4, 1, 1024, nil},
+		{"port scan", 1, 600, 1, []XnfvScanType{XnfvScanPortScan}},
+		{"sampled port scan", 1, 40, 1024, []XnfvScanType{XnfvScanPortScan}},
+		{"few sampled ports", 1, 4, 1024, nil},
+		{"below the thresholds", 20, 20, 1, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := NewXnfvScanDetector(DefaultXnfvScanConfig())
+			for host := 0; host < test.hosts; host++ {
+				for port := 0; port < test.ports; port++ {
+					d.Observe(xnfvTestSynSample(0, fmt.Sprintf("10.0.%d.%d", host/256, host%256), uint16(1000+port), test.samplingRate))
+				}
+			}
+			events := d.Flush(xnfvTestEpoch.Add(time.Minute))
+			if len(events) != len(test.want) {
+				t.Fatalf("events %+v, want %v", events, test.want)
+			}
+			for i, event := range events {
+				if event.Type != test.want[i] || event.Scanner != "192.0.2.1" {
+					t.Errorf("event %+v, want %s", event, test.want[i])
+				}
+			}
+			if len(test.want) == 1 && test.want[0] == XnfvScanPortScan && events[0].TopTarget != "10.0.0.0" {
+				t.Errorf("top target %q", events[0].TopTarget)
+			}
+		})
+	}
+}
+
+func TestXnfvScanDetectorWindow(t *testing.T) {
+	d := NewXnfvScanDetector(DefaultXnfvScanConfig())
+	for host := 0; host < 300; host++ {
+		d.Observe(xnfvTestSynSample(time.Duration(host)*time.Millisecond, fmt.Sprintf("10.0.%d.%d", host/256, host%256), 80, 1))
+	}
+	events := d.Observe(xnfvTestSynSample(time.Minute, "10.9.9.9", 80, 1))
+	if len(events) != 1 || events[0].Type != XnfvScanHostSweep {
+		t.Fatalf("events %+v", events)
+	}
+	if len(d.sources) != 1 {
+		t.Errorf("the sample after the window was not kept: %d sources", len(d.sources))
+	}
+}
+
+// A sweep followed by a quiet period is reported by the timer with the
+// window it happened in
+func TestXnfvScanDetectorTick(t *testing.T) {
+	d := NewXnfvScanDetector(DefaultXnfvScanConfig())
+	if events := d.Tick(xnfvTestEpoch); events != nil {
+		t.Fatalf("tick without samples: %v", events)
+	}
+	for host := 0; host < 300; host++ {
+		d.Observe(xnfvTestSynSample(time.Duration(host)*time.Millisecond, fmt.Sprintf("10.0.%d.%d", host/256, host%256), 80, 1))
+	}
+	if events := d.Tick(xnfvTestEpoch.Add(59 * time.Second)); events != nil {
+		t.Fatalf("tick before the end of the window: %v", events)
+	}
+	events := d.Tick(xnfvTestEpoch.Add(61 * time.Second))
+	if len(events) != 1 || events[0].Type != XnfvScanHostSweep {
+		t.Fatalf("tick at the end of the window: %+v", events)
+	}
+	if !events[0].WindowStart.Equal(xnfvTestEpoch) || !events[0].WindowEnd.Equal(xnfvTestEpoch.Add(61*time.Second)) {
+		t.Errorf("window %v - %v", events[0].WindowStart, events[0].WindowEnd)
+	}
+	// the sweep was flushed, the next sample does not report it again
+	if events := d.Observe(xnfvTestSynSample(10*time.Minute, "10.9.9.9", 80, 1)); len(events) != 0 {
+		t.Fatalf("the window was not reset: %+v", events)
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/hex"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  JSON Schema
+// ****************************************************************************************************
+
+// XnfvSchemaVersion is the version of the JSON documents of the collector,
+// the datagrams printed with -output json and the switch inventory served
+// at /switches. Within a version fields are only added, renaming or
+// removing a field or changing its type increments it. The schema is
+// documented in README.md.
+//
+// Datapath IDs and other binary fields are hex strings, IP addresses
+// dotted (or IPv6) strings, MAC addresses colon separated strings and the
+// sampled headers the fields parsed from them.
+const XnfvSchemaVersion = 1
+
+// XnfvDatagramDocument is a decoded datagram
+type XnfvDatagramDocument struct {
+	SchemaVersion  int                         `json:"schemaVersion"`
+	Received       time.Time                   `json:"received"`
+	Source         string                      `json:"source"`
+	Version        uint32                      `json:"version"`
+	Agent          string                      `json:"agent"`
+	SubAgentID     uint32                      `json:"subAgentId"`
+	SequenceNumber uint32                      `json:"sequenceNumber"`
+	UptimeMs       uint32                      `json:"uptimeMs"`
+	FlowSamples    []XnfvFlowSampleDocument    `json:"flowSamples"`
+	CounterSamples []XnfvCounterSampleDocument `json:"counterSamples"`
+	DiscardSamples []XnfvDiscardSampleDocument `json:"discardSamples"`
+}
+
+type XnfvFlowSampleDocument struct {
+	SequenceNumber  uint32               `json:"sequenceNumber"`
+	SourceIDClass   uint32               `json:"sourceIdClass"`
+	SourceIDIndex   uint32               `json:"sourceIdIndex"`
+	SamplingRate    uint32               `json:"samplingRate"`
+	SamplePool      uint32               `json:"samplePool"`
+	Drops           uint32               `json:"drops"`
+	InputInterface  uint32               `json:"inputInterface"`
+	OutputInterface uint32               `json:"outputInterface"`
+	Records         []XnfvRecordDocument `json:"records"`
+}
+
+type XnfvCounterSampleDocument struct {
+	SequenceNumber uint32               `json:"sequenceNumber"`
+	SourceIDClass  uint32               `json:"sourceIdClass"`
+	SourceIDIndex  uint32               `json:"sourceIdIndex"`
+	Records        []XnfvRecordDocument `json:"records"`
+}
+
+type XnfvDiscardSampleDocument struct {
+	SequenceNumber  uint32               `json:"sequenceNumber"`
+	SourceIDClass   uint32               `json:"sourceIdClass"`
+	SourceIDIndex   uint32               `json:"sourceIdIndex"`
+	Drops           uint32               `json:"drops"`
+	InputInterface  uint32               `json:"inputInterface"`
+	OutputInterface uint32               `json:"outputInterface"`
+	Reason          uint32               `json:"reason"`
+	ReasonName      string               `json:"reasonName"`
+	Records         []XnfvRecordDocument `json:"records"`
+}
+
+// XnfvRecordDocument is a flow or counter record: its "recordType",
+// "enterprise" and "format", and its fields keyed by their lower camel
+// case name (IfInOctets is "ifInOctets", OfDataPathId "ofDataPathId")
+type XnfvRecordDocument map[string]interface{}
+
+// XnfvHeaderDocument holds the fields parsed from a sampled header, of
+// the inner headers of a VXLAN encapsulation, the fields missing from the
+// header are left out
+type XnfvHeaderDocument struct {
+	Layers     []string `json:"layers"`
+	SrcMac     string   `json:"srcMac,omitempty"`
+	DstMac     string   `json:"dstMac,omitempty"`
+	EtherType  uint16   `json:"etherType,omitempty"`
+	VLAN       uint16   `json:"vlan,omitempty"`
+	SrcIP      string   `json:"srcIp,omitempty"`
+	DstIP      string   `json:"dstIp,omitempty"`
+	IPProtocol uint8    `json:"ipProtocol,omitempty"`
+	TOS        uint8    `json:"tos,omitempty"`
+	TTL        uint8    `json:"ttl,omitempty"`
+	SrcPort    uint16   `json:"srcPort,omitempty"`
+	DstPort    uint16   `json:"dstPort,omitempty"`
+	TCPFlags   uint8    `json:"tcpFlags,omitempty"`
+	ICMPType   *uint8   `json:"icmpType,omitempty"`
+	ICMPCode   *uint8   `json:"icmpCode,omitempty"`
+	VNI        uint32   `json:"vni,omitempty"`
+	// Error is why the rest of the header could not be parsed
+	Error string `json:"error,omitempty"`
+}
+
+func newXnfvDatagramDocument(source net.IP, received time.Time, datagram *sflow.Datagram) XnfvDatagramDocument {
+	document := XnfvDatagramDocument{
+		SchemaVersion:  XnfvSchemaVersion,
+		Received:       received,
+		Source:         xnfvAddressString(source),
+		Version:        datagram.DatagramVersion,
+		Agent:          xnfvAddressString(datagram.AgentAddress),
+		SubAgentID:     datagram.SubAgentID,
+		SequenceNumber: datagram.SequenceNumber,
+		UptimeMs:       datagram.AgentUptime,
+		FlowSamples:    []XnfvFlowSampleDocument{},
+		CounterSamples: []XnfvCounterSampleDocument{},
+		DiscardSamples: []XnfvDiscardSampleDocument{},
+	}
+	for _, s := range datagram.FlowSamples {
+		document.FlowSamples = append(document.FlowSamples, XnfvFlowSampleDocument{
+			SequenceNumber:  s.SequenceNumber,
+			SourceIDClass:   uint32(s.SourceIDClass),
+			SourceIDIndex:   uint32(s.SourceIDIndex),
+			SamplingRate:    s.SamplingRate,
+			SamplePool:      s.SamplePool,
+			Drops:           s.Dropped,
+			InputInterface:  s.InputInterface,
+			OutputInterface: s.OutputInterface,
+			Records:         newXnfvRecordDocuments(s.Records),
+		})
+	}
+	for _, s := range datagram.CounterSamples {
+		document.CounterSamples = append(document.CounterSamples, XnfvCounterSampleDocument{
+			SequenceNumber: s.SequenceNumber,
+			SourceIDClass:  uint32(s.SourceIDClass),
+			SourceIDIndex:  uint32(s.SourceIDIndex),
+			Records:        newXnfvRecordDocuments(s.Records),
+		})
+	}
+	for _, s := range datagram.DiscardSamples {
+		document.DiscardSamples = append(document.DiscardSamples, XnfvDiscardSampleDocument{
+			SequenceNumber:  s.SequenceNumber,
+			SourceIDClass:   uint32(s.SourceIDClass),
+			SourceIDIndex:   uint32(s.SourceIDIndex),
+			Drops:           s.Drops,
+			InputInterface:  s.InputInterface,
+			OutputInterface: s.OutputInterface,
+			Reason:          uint32(s.Reason),
+			ReasonName:      s.Reason.String(),
+			Records:         newXnfvRecordDocuments(s.Records),
+		})
+	}
+	return document
+}
+
+func newXnfvRecordDocuments(records sflow.SFlowRecords) []XnfvRecordDocument {
+	documents := []XnfvRecordDocument{}
+	for _, record := range records {
+		documents = append(documents, newXnfvRecordDocument(record))
+	}
+	return documents
+}
+
+func newXnfvRecordDocument(record sflow.SFlowRecord) XnfvRecordDocument {
+	document := XnfvRecordDocument{
+		"recordType": record.RecordType(),
+		"enterprise": uint32(record.RecordEnterprise()),
+		"format":     record.RecordFormat(),
+	}
+	if raw, ok := record.(sflow.SFlowRawPacketFlowRecord); ok {
+		document["headerProtocol"] = uint32(raw.HeaderProtocol)
+		document["headerProtocolName"] = raw.HeaderProtocol.String()
+		document["frameLength"] = raw.FrameLength
+		document["payloadRemoved"] = raw.PayloadRemoved
+		document["headerLength"] = raw.HeaderLength
+		document["headerBytes"] = hex.EncodeToString(raw.ParsedHeader.Data)
+		if raw.ParsedHeader.Packet != nil {
+			document["header"] = newXnfvHeaderDocument(raw.ParsedHeader.Packet.Layers())
+		}
+		return document
+	}
+	xnfvDocumentFields(document, reflect.ValueOf(record))
+	return document
+}
+
+// xnfvDocumentFields adds the exported fields of a record, but those of
+// its base record, to the document
+func xnfvDocumentFields(document map[string]interface{}, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous && (field.Type == reflect.TypeOf(sflow.SFlowBaseFlowRecord{}) || field.Type == reflect.TypeOf(sflow.SFlowBaseCounterRecord{})) {
+			continue
+		}
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			xnfvDocumentFields(document, value)
+			continue
+		}
+		document[sflowToolKey(field.Name)] = xnfvDocumentValue(value)
+	}
+}
+
+func xnfvDocumentValue(value reflect.Value) interface{} {
+	switch v := value.Interface().(type) {
+	case net.IP:
+		return xnfvAddressString(v)
+	case net.HardwareAddr:
+		return v.String()
+	case []byte:
+		return hex.EncodeToString(v)
+	case sflow.SFlowRecords:
+		return newXnfvRecordDocuments(v)
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		document := map[string]interface{}{}
+		xnfvDocumentFields(document, value)
+		return document
+	case reflect.Slice:
+		values := make([]interface{}, value.Len())
+		for i := range values {
+			values[i] = xnfvDocumentValue(value.Index(i))
+		}
+		return values
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return xnfvDocumentValue(value.Elem())
+	}
+	// the named numbers and strings encode as their underlying value
+	return value.Interface()
+}
+
+// newXnfvHeaderDocument parses the fields of the layers of a sampled
+// header, the inner headers of a VXLAN encapsulation overwrite the outer
+func newXnfvHeaderDocument(headerLayers []gopacket.Layer) XnfvHeaderDocument {
+	document := XnfvHeaderDocument{Layers: []string{}}
+	for _, layer := range headerLayers {
+		document.Layers = append(document.Layers, layer.LayerType().String())
+		switch l := layer.(type) {
+		case *layers.Ethernet:
+			document.SrcMac, document.DstMac = l.SrcMAC.String(), l.DstMAC.String()
+			document.EtherType = uint16(l.EthernetType)
+		case *layers.Dot1Q:
+			document.VLAN, document.EtherType = l.VLANIdentifier, uint16(l.Type)
+		case *layers.IPv4:
+			document.SrcIP, document.DstIP = l.SrcIP.String(), l.DstIP.String()
+			document.IPProtocol, document.TOS, document.TTL = uint8(l.Protocol), l.TOS, l.TTL
+		case *layers.IPv6:
+			document.SrcIP, document.DstIP = l.SrcIP.String(), l.DstIP.String()
+			document.IPProtocol, document.TOS, document.TTL = uint8(l.NextHeader), l.TrafficClass, l.HopLimit
+		case *layers.TCP:
+			document.SrcPort, document.DstPort = uint16(l.SrcPort), uint16(l.DstPort)
+			document.TCPFlags = uint8(sflowToolTCPFlags(l))
+		case *layers.UDP:
+			document.SrcPort, document.DstPort = uint16(l.SrcPort), uint16(l.DstPort)
+		case *layers.ICMPv4:
+			icmpType, icmpCode := l.TypeCode.Type(), l.TypeCode.Code()
+			document.ICMPType, document.ICMPCode = &icmpType, &icmpCode
+		case *layers.ICMPv6:
+			icmpType, icmpCode := l.TypeCode.Type(), l.TypeCode.Code()
+			document.ICMPType, document.ICMPCode = &icmpType, &icmpCode
+		case *layers.VXLAN:
+			document.VNI = l.VNI
+			// the inner frame starts over
+			document.VLAN, document.SrcPort, document.DstPort, document.TCPFlags = 0, 0, 0, 0
+			document.ICMPType, document.ICMPCode = nil, nil
+		case gopacket.ErrorLayer:
+			document.Error = l.Error().Error()
+		}
+	}
+	return document
+}
+
+func xnfvAddressString(ip net.IP) string {
+	if len(ip) == 0 {
+		return ""
+	}
+	return ip.String()
+}
+
+// ****************************************************************************************************
+//  Switch Inventory
+// ****************************************************************************************************
+
+// XnfvInventoryDocument is the inventory of the OVS switches and ports
+// learned from the OpenFlow port and port name counters
+type XnfvInventoryDocument struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	Updated       time.Time            `json:"updated"`
+	Switches      []XnfvSwitchDocument `json:"switches"`
+}
+
+type XnfvSwitchDocument struct {
+	DataPath string             `json:"dataPath"`
+	Ports    []XnfvPortDocument `json:"ports"`
+}
+
+type XnfvPortDocument struct {
+	Name       string `json:"name"`
+	IfIndex    uint32 `json:"ifIndex"`
+	OfPort     uint32 `json:"ofPort"`
+	SubAgentID uint32 `json:"subAgentId"`
+	// LastHeader is the last header sampled on the port
+	LastHeader *XnfvHeaderDocument `json:"lastHeader,omitempty"`
+}
+
+func newXnfvInventoryDocument(xnfvAllSwitches *XnfvAllSwitches, now time.Time) XnfvInventoryDocument {
+	document := XnfvInventoryDocument{SchemaVersion: XnfvSchemaVersion, Updated: now, Switches: []XnfvSwitchDocument{}}
+	for i := range xnfvAllSwitches.allAvailableSwitches {
+		xnfvSwitch := &xnfvAllSwitches.allAvailableSwitches[i]
+		switchDocument := XnfvSwitchDocument{DataPath: dataPathString(xnfvSwitch.switchDataPath), Ports: []XnfvPortDocument{}}
+		for j := range xnfvSwitch.switchPortsStatistics {
+			port := &xnfvSwitch.switchPortsStatistics[j]
+			portDocument := XnfvPortDocument{
+				Name:       port.interfacePortName,
+				IfIndex:    uint32(port.interfacePortIndex),
+				OfPort:     port.ofPort(),
+				SubAgentID: port.interfaceSflowDatagram.SubAgentID,
+			}
+			if len(port.PacketHeader) > 0 {
+				header := newXnfvHeaderDocument(port.PacketHeader)
+				portDocument.LastHeader = &header
+			}
+			switchDocument.Ports = append(switchDocument.Ports, portDocument)
+		}
+		document.Switches = append(document.Switches, switchDocument)
+	}
+	return document
+}
+
+// XnfvInventoryView serves the last inventory document, the packet loop
+// updates it as the inventory itself is not safe for concurrent use
+type XnfvInventoryView struct {
+	mutex    sync.Mutex
+	document XnfvInventoryDocument
+}
+
+func NewXnfvInventoryView() *XnfvInventoryView {
+	return &XnfvInventoryView{document: XnfvInventoryDocument{SchemaVersion: XnfvSchemaVersion, Switches: []XnfvSwitchDocument{}}}
+}
+
+func (v *XnfvInventoryView) Update(xnfvAllSwitches *XnfvAllSwitches, now time.Time) {
+	document := newXnfvInventoryDocument(xnfvAllSwitches, now)
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.document = document
+}
+
+// ServeHTTP lists the switches and their ports, GET /switches
+func (v *XnfvInventoryView) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mutex.Lock()
+	document := v.document
+	v.mutex.Unlock()
+	writeJSON(w, document)
+}
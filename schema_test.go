@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+var xnfvTestReceived = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// xnfvTestJSON indents a JSON document like the golden files
+func xnfvTestJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(data, '\n')
+}
+
+// xnfvTestShape decodes a JSON document and returns its sorted keys
+func xnfvTestShape(t *testing.T, data []byte) (map[string]interface{}, []string) {
+	document := map[string]interface{}{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{}
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return document, keys
+}
+
+// xnfvTestInventory is the inventory learned from the OpenFlow port
+// records of the sflowtool test datagram, with the header sampled on s1-eth3
+func xnfvTestInventory(t *testing.T) *XnfvAllSwitches {
+	xnfvAllSwitches := &XnfvAllSwitches{}
+	addXnfvSwitchPorts(xnfvTestSFlowToolDatagram(t), xnfvAllSwitches)
+	header := xnfvTestUDPHeader(t, net.ParseIP("10.0.0.1").To4(), net.ParseIP("10.0.0.2").To4(), 53, nil)
+	xnfvAllSwitches.allAvailableSwitches[0].switchPortsStatistics[0].PacketHeader = header.Layers()
+	return xnfvAllSwitches
+}
+
+func TestXnfvDatagramDocumentGolden(t *testing.T) {
+	document := newXnfvDatagramDocument(net.ParseIP("192.0.2.1").To4(), xnfvTestReceived, xnfvTestSFlowToolDatagram(t))
+	xnfvTestGolden(t, "datagram.golden.json", xnfvTestJSON(t, document))
+}
+
+func TestXnfvDatagramDocumentShape(t *testing.T) {
+	var out bytes.Buffer
+	// an empty datagram, as printed by -output json
+	writeXnfvDatagram(&out, xnfvOutputJSON, nil, 0, xnfvTestReceived, &sflow.Datagram{DatagramVersion: 5})
+	document, keys := xnfvTestShape(t, out.Bytes())
+	want := []string{"agent", "counterSamples", "discardSamples", "flowSamples", "received", "schemaVersion",
+		"sequenceNumber", "source", "subAgentId", "uptimeMs", "version"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys %v, want %v", keys, want)
+	}
+	if document["schemaVersion"] != float64(XnfvSchemaVersion) || XnfvSchemaVersion != 1 {
+		t.Fatalf("schema version %v", document["schemaVersion"])
+	}
+	// no samples are empty lists, not null
+	for _, samples := range []string{"flowSamples", "counterSamples", "discardSamples"} {
+		if list, ok := document[samples].([]interface{}); !ok || len(list) != 0 {
+			t.Fatalf("%s %#v", samples, document[samples])
+		}
+	}
+	if document["agent"] != "" || document["received"] != "2021-03-01T12:00:00Z" {
+		t.Fatalf("document %v", document)
+	}
+}
+
+func TestXnfvRecordDocumentShape(t *testing.T) {
+	records := []struct {
+		record sflow.SFlowRecord
+		keys   []string
+	}{
+		{
+			sflow.SFlowOFPortCounters{OfDataPathId: []byte{0, 0, 0, 0, 0, 0, 0, 1}, OfPort: 3},
+			[]string{"enterprise", "format", "ofDataPathId", "ofPort", "recordType"},
+		},
+		{
+			sflow.SFlowExtendedSwitchFlowRecord{IncomingVLAN: 10},
+			[]string{"enterprise", "format", "incomingVLAN", "incomingVLANPriority", "outgoingVLAN", "outgoingVLANPriority", "recordType"},
+		},
+		{
+			sflow.SFlowRawPacketFlowRecord{HeaderProtocol: sflow.SFlowProtoEthernet, ParsedHeader: sflow.SFlowRawHeader{Data: []byte{1, 2}}},
+			[]string{"enterprise", "format", "frameLength", "headerBytes", "headerLength", "headerProtocol", "headerProtocolName", "payloadRemoved", "recordType"},
+		},
+	}
+	for _, r := range records {
+		document, keys := xnfvTestShape(t, xnfvTestJSON(t, newXnfvRecordDocument(r.record)))
+		if !reflect.DeepEqual(keys, r.keys) {
+			t.Errorf("%T: keys %v, want %v", r.record, keys, r.keys)
+		}
+		if document["recordType"] != r.record.RecordType() || document["format"] != float64(r.record.RecordFormat()) || document["enterprise"] != float64(0) {
+			t.Errorf("%T: document %v", r.record, document)
+		}
+	}
+
+	document, _ := xnfvTestShape(t, xnfvTestJSON(t, newXnfvRecordDocument(sflow.SFlowOFPortCounters{OfDataPathId: []byte{0, 0, 0, 0, 0, 0, 0, 1}})))
+	if document["ofDataPathId"] != "0000000000000001" {
+		t.Fatalf("data path %v, want a hex string", document["ofDataPathId"])
+	}
+}
+
+func TestXnfvInventoryViewGolden(t *testing.T) {
+	view := NewXnfvInventoryView()
+	view.Update(xnfvTestInventory(t), xnfvTestReceived)
+	recorder := httptest.NewRecorder()
+	view.ServeHTTP(recorder, httptest.NewRequest("GET", "/switches", nil))
+	var out bytes.Buffer
+	if err := json.Indent(&out, recorder.Body.Bytes(), "", "\t"); err != nil {
+		t.Fatal(err)
+	}
+	xnfvTestGolden(t, "inventory.golden.json", out.Bytes())
+}
+
+func TestXnfvInventoryDocumentShape(t *testing.T) {
+	// before the first datagram
+	recorder := httptest.NewRecorder()
+	NewXnfvInventoryView().ServeHTTP(recorder, httptest.NewRequest("GET", "/switches", nil))
+	document, keys := xnfvTestShape(t, recorder.Body.Bytes())
+	if !reflect.DeepEqual(keys, []string{"schemaVersion", "switches", "updated"}) {
+		t.Fatalf("keys %v", keys)
+	}
+	if document["schemaVersion"] != float64(XnfvSchemaVersion) {
+		t.Fatalf("schema version %v", document["schemaVersion"])
+	}
+	if switches, ok := document["switches"].([]interface{}); !ok || len(switches) != 0 {
+		t.Fatalf("switches %#v", document["switches"])
+	}
+
+	var inventory struct {
+		Switches []struct {
+			DataPath string                   `json:"dataPath"`
+			Ports    []map[string]interface{} `json:"ports"`
+		} `json:"switches"`
+	}
+	if err := json.Unmarshal(xnfvTestJSON(t, newXnfvInventoryDocument(xnfvTestInventory(t), xnfvTestReceived)), &inventory); err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Switches) != 1 || inventory.Switches[0].DataPath != "0000000000000001" || len(inventory.Switches[0].Ports) != 1 {
+		t.Fatalf("inventory %+v", inventory)
+	}
+	port := inventory.Switches[0].Ports[0]
+	if port["name"] != "s1-eth3" || port["ifIndex"] != float64(3) || port["subAgentId"] != float64(1) {
+		t.Fatalf("port %v", port)
+	}
+	header, ok := port["lastHeader"].(map[string]interface{})
+	if !ok || header["srcIp"] != "10.0.0.1" || header["dstPort"] != float64(53) || header["srcMac"] != "02:00:00:00:00:01" {
+		t.Fatalf("last header %v", port["lastHeader"])
+	}
+	// a port without a sampled header leaves it out
+	xnfvAllSwitches := &XnfvAllSwitches{}
+	addXnfvSwitchPorts(xnfvTestSFlowToolDatagram(t), xnfvAllSwitches)
+	ports := newXnfvInventoryDocument(xnfvAllSwitches, xnfvTestReceived).Switches[0].Ports
+	if _, keys := xnfvTestShape(t, xnfvTestJSON(t, ports[0])); !reflect.DeepEqual(keys, []string{"ifIndex", "name", "ofPort", "subAgentId"}) {
+		t.Fatalf("port keys %v", keys)
+	}
+}
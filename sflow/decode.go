@@ -0,0 +1,2291 @@
+// Package sflow decodes sFlow version 5 datagrams, including the OpenFlow,
+// host, virtual machine, application, wireless and drop monitoring
+// structures that gopacket does not decode.
+package sflow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Register Custom SFlow Layer
+// ****************************************************************************************************
+
+// layerTypeDatagramNumber is the number of LayerTypeDatagram, gopacket
+// reserves 0-999 and looks the numbers 1000-1999 up in an array
+const layerTypeDatagramNumber = 1343
+
+// LayerTypeDatagram is the gopacket layer type of a Datagram decoded from
+// the payload of an sFlow UDP packet
+var LayerTypeDatagram = gopacket.RegisterLayerType(layerTypeDatagramNumber, gopacket.LayerTypeMetadata{Name: "SFlowDatagram", Decoder: gopacket.DecodeFunc(decodeDatagramLayer)})
+
+func (m Datagram) LayerType() gopacket.LayerType { return LayerTypeDatagram }
+
+func (m Datagram) LayerContents() []byte { return []byte{} }
+
+func (d *Datagram) Payload() []byte { return nil }
+
+func (m Datagram) LayerPayload() []byte { return []byte{} }
+
+func (d *Datagram) CanDecode() gopacket.LayerClass { return LayerTypeDatagram }
+
+func (d *Datagram) NextLayerType() gopacket.LayerType { return gopacket.LayerTypePayload }
+
+func decodeDatagramLayer(data []byte, p gopacket.PacketBuilder) error {
+	datagram, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	p.AddLayer(datagram)
+	p.SetApplicationLayer(datagram)
+	return nil
+}
+
+// ****************************************************************************************************
+//  Datagram Decoding
+// ****************************************************************************************************
+
+// Decode decodes an sFlow v5 datagram, the payload of its UDP packet.
+// Samples of unknown types are skipped, so are the flow and counter
+// samples with a record that cannot be decoded. A truncated datagram, a
+// version other than 5 or an unknown agent address type returns an error.
+func Decode(payload []byte) (datagram *Datagram, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			datagram, err = nil, fmt.Errorf("malformed sFlow datagram: %v", r)
+		}
+	}()
+	datagram = &Datagram{}
+	if err := decodeDatagram(payload, datagram); err != nil {
+		return nil, err
+	}
+	return datagram, nil
+}
+
+// checkDatagramHeader checks the version and the agent address type of a
+// datagram, the rest of the datagram cannot be read without them
+func checkDatagramHeader(version uint32, agentAddressType layers.SFlowIPType) error {
+	if version != 5 {
+		return fmt.Errorf("unsupported sFlow version %d", version)
+	}
+	if agentAddressType != layers.SFlowIPv4 && agentAddressType != layers.SFlowIPv6 {
+		return fmt.Errorf("unknown agent address type %d", agentAddressType)
+	}
+	return nil
+}
+
+func decodeDatagram(data []byte, myl *Datagram) error {
+	var agentAddressType layers.SFlowIPType
+
+	data, myl.DatagramVersion = data[4:], binary.BigEndian.Uint32(data[:4])
+	data, agentAddressType = data[4:], layers.SFlowIPType(binary.BigEndian.Uint32(data[:4]))
+	if err := checkDatagramHeader(myl.DatagramVersion, agentAddressType); err != nil {
+		return err
+	}
+	data, myl.AgentAddress = data[agentAddressType.Length():], data[:agentAddressType.Length()]
+	data, myl.SubAgentID = data[4:], binary.BigEndian.Uint32(data[:4])
+	data, myl.SequenceNumber = data[4:], binary.BigEndian.Uint32(data[:4])
+	data, myl.AgentUptime = data[4:], binary.BigEndian.Uint32(data[:4])
+	data, myl.SampleCount = data[4:], binary.BigEndian.Uint32(data[:4])
+
+	for i := uint32(0); i < myl.SampleCount; i++ {
+		sdf := SFlowDataFormat(binary.BigEndian.Uint32(data[:4]))
+		_, sampleType := sdf.decode()
+		// a sample may carry records we cannot decode, continue with the
+		// next sample in any case
+		next := data
+		skipRecord(&next)
+		switch sampleType {
+		case SFlowTypeFlowSample:
+			if flowSample, err := decodeFlowSample(&data, false); err == nil {
+				myl.FlowSamples = append(myl.FlowSamples, flowSample)
+				myl.SampleOrder = append(myl.SampleOrder, SFlowTypeFlowSample)
+			}
+		case SFlowTypeCounterSample:
+			if counterSample, err := decodeCounterSample(&data, false); err == nil {
+				myl.CounterSamples = append(myl.CounterSamples, counterSample)
+				myl.SampleOrder = append(myl.SampleOrder, SFlowTypeCounterSample)
+			}
+		case SFlowTypeDiscardSample:
+			if discardSample, err := decodeDiscardSample(&data); err == nil {
+				myl.DiscardSamples = append(myl.DiscardSamples, discardSample)
+				myl.SampleOrder = append(myl.SampleOrder, SFlowTypeDiscardSample)
+			}
+		default:
+			// unsupported sample type
+		}
+		data = next
+	}
+	return nil
+}
+
+// ****************************************************************************************************
+// Generic Flow Sample Decoding
+// ****************************************************************************************************
+
+func decodeFlowSample(data *[]byte, expanded bool) (SFlowFlowSample, error) {
+	s := SFlowFlowSample{}
+	var sdf SFlowDataFormat
+	*data, sdf = (*data)[4:], SFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	var sdc SFlowDataSource
+
+	s.EnterpriseID, s.Format = sdf.decode()
+	*data, s.SampleLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.SequenceNumber = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	if expanded {
+		*data, s.SourceIDClass = (*data)[4:], SFlowSourceFormat(binary.BigEndian.Uint32((*data)[:4]))
+		*data, s.SourceIDIndex = (*data)[4:], SFlowSourceValue(binary.BigEndian.Uint32((*data)[:4]))
+	} else {
+		*data, sdc = (*data)[4:], SFlowDataSource(binary.BigEndian.Uint32((*data)[:4]))
+		s.SourceIDClass, s.SourceIDIndex = sdc.decode()
+	}
+	*data, s.SamplingRate = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.SamplePool = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.Dropped = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	if expanded {
+		*data, s.InputInterfaceFormat = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, s.InputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, s.OutputInterfaceFormat = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, s.OutputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	} else {
+		*data, s.InputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, s.OutputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	}
+	*data, s.RecordCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	var err error
+	s.Records, err = decodeFlowRecords(data, s.RecordCount)
+	return s, err
+}
+
+// decodeFlowRecords decodes the flow records of a flow or discard sample
+func decodeFlowRecords(data *[]byte, count uint32) (SFlowRecords, error) {
+	var records SFlowRecords
+
+	for i := uint32(0); i < count; i++ {
+		rdf := SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+		_, flowRecordType := rdf.decode()
+
+		switch flowRecordType {
+		case SFlowTypeRawPacketFlow:
+			if record, err := decodeRawPacketFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedUserFlow:
+			if record, err := decodeExtendedUserFlow(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedUrlFlow:
+			if record, err := decodeExtendedURLRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedSwitchFlow:
+			if record, err := decodeExtendedSwitchFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedRouterFlow:
+			if record, err := decodeExtendedRouterFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedGatewayFlow:
+			if record, err := decodeExtendedGatewayFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeEthernetFrameFlow:
+			if record, err := decodeEthernetFrameFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeIpv4Flow:
+			if record, err := decodeIpv4FlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeIpv6Flow:
+			if record, err := decodeIpv6FlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedMlpsFlow:
+			if record, err := decodeExtendedMPLSFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedNatFlow:
+			if record, err := decodeExtendedNATFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedMlpsTunnelFlow:
+			if record, err := decodeExtendedMPLSTunnelFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedMlpsVcFlow:
+			if record, err := decodeExtendedMPLSVCFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedMlpsFecFlow:
+			if record, err := decodeExtendedMPLSFECFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedMlpsLvpFecFlow:
+			if record, err := decodeExtendedMPLSLVPFECFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedVlanFlow:
+			if record, err := decodeExtendedVLANTunnelFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtended80211PayloadFlow:
+			if record, err := decodeExtended80211PayloadFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtended80211RxFlow:
+			if record, err := decodeExtended80211RxFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtended80211TxFlow:
+			if record, err := decodeExtended80211TxFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtended80211AggregationFlow:
+			if record, err := decodeExtended80211AggregationFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedIpv4TunnelEgressFlow:
+			if record, err := decodeExtendedIpv4TunnelEgress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedIpv4TunnelIngressFlow:
+			if record, err := decodeExtendedIpv4TunnelIngress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedIpv6TunnelEgressFlow:
+			if record, err := decodeExtendedIpv6TunnelEgress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedIpv6TunnelIngressFlow:
+			if record, err := decodeExtendedIpv6TunnelIngress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedDecapsulateEgressFlow:
+			if record, err := decodeExtendedDecapsulateEgress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedDecapsulateIngressFlow:
+			if record, err := decodeExtendedDecapsulateIngress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedVniEgressFlow:
+			if record, err := decodeExtendedVniEgress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedVniIngressFlow:
+			if record, err := decodeExtendedVniIngress(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedSocketIpv4Flow, SFlowTypeExtendedSocketIpv6Flow:
+			if record, err := decodeExtendedSocketFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeMemcacheFlow:
+			if record, err := decodeMemcacheFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHTTPFlow, SFlowTypeHTTP2Flow:
+			if record, err := decodeHTTPRequestFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppOperationFlow:
+			if record, err := decodeAppOperationFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppParentContextFlow:
+			if record, err := decodeAppParentContextFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppInitiatorFlow, SFlowTypeAppTargetFlow:
+			if record, err := decodeAppActorFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedEgressQueueFlow:
+			if record, err := decodeExtendedEgressQueueRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedTransitFlow:
+			if record, err := decodeExtendedTransitRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedQueueFlow:
+			if record, err := decodeExtendedQueueRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedFunctionFlow:
+			if record, err := decodeExtendedFunctionFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeExtendedTCPInfoFlow:
+			if record, err := decodeExtendedTCPInfoFlowRecord(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		default:
+			return records, fmt.Errorf("Unsupported flow record type: %d", flowRecordType)
+		}
+	}
+	return records, nil
+}
+
+// ****************************************************************************************************
+// Discard Sample Decoding
+// ****************************************************************************************************
+
+func decodeDiscardSample(data *[]byte) (SFlowDiscardSample, error) {
+	s := SFlowDiscardSample{}
+	var sdf SFlowDataFormat
+
+	*data, sdf = (*data)[4:], SFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	s.EnterpriseID, s.Format = sdf.decode()
+	*data, s.SampleLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.SequenceNumber = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.SourceIDClass = (*data)[4:], SFlowSourceFormat(binary.BigEndian.Uint32((*data)[:4]))
+	*data, s.SourceIDIndex = (*data)[4:], SFlowSourceValue(binary.BigEndian.Uint32((*data)[:4]))
+	*data, s.Drops = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.InputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.OutputInterface = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.Reason = (*data)[4:], SFlowDropReason(binary.BigEndian.Uint32((*data)[:4]))
+	*data, s.RecordCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	var err error
+	s.Records, err = decodeFlowRecords(data, s.RecordCount)
+	return s, err
+}
+
+func decodeRawPacketFlowRecord(data *[]byte) (SFlowRawPacketFlowRecord, error) {
+	rec := SFlowRawPacketFlowRecord{}
+	header := []byte{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.HeaderProtocol = (*data)[4:], SFlowRawHeaderProtocol(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.FrameLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.PayloadRemoved = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.HeaderLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	headerLenWithPadding := int(rec.HeaderLength + ((4 - rec.HeaderLength) % 4))
+	*data, header = (*data)[headerLenWithPadding:], (*data)[:headerLenWithPadding]
+	rec.ParsedHeader = DecodeRawHeader(rec.HeaderProtocol, header[:rec.HeaderLength])
+	rec.Header = rec.ParsedHeader.Packet
+	return rec, nil
+}
+
+// DecodeRawHeader decodes a sampled header with the gopacket decoder
+// matching its protocol. Headers of protocols gopacket cannot decode are
+// counted in UnsupportedHeaders and kept as bytes only.
+func DecodeRawHeader(protocol SFlowRawHeaderProtocol, header []byte) SFlowRawHeader {
+	parsed := SFlowRawHeader{Protocol: protocol, Data: header}
+	layerType, ok := protocol.LayerType()
+	if !ok {
+		UnsupportedHeaders.add(protocol)
+		return parsed
+	}
+	parsed.Supported = true
+	parsed.Packet = gopacket.NewPacket(header, layerType, gopacket.Default)
+	return parsed
+}
+
+// HeaderProtocolCounter counts sampled headers per header protocol
+type HeaderProtocolCounter struct {
+	mutex  sync.Mutex
+	counts map[SFlowRawHeaderProtocol]uint64
+}
+
+// UnsupportedHeaders counts the sampled headers DecodeRawHeader could not
+// decode, by header protocol
+var UnsupportedHeaders = &HeaderProtocolCounter{counts: map[SFlowRawHeaderProtocol]uint64{}}
+
+func (c *HeaderProtocolCounter) add(protocol SFlowRawHeaderProtocol) {
+	c.mutex.Lock()
+	c.counts[protocol]++
+	c.mutex.Unlock()
+}
+
+// Counts returns the counters keyed by protocol name
+func (c *HeaderProtocolCounter) Counts() map[string]uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts := map[string]uint64{}
+	for protocol, count := range c.counts {
+		counts[fmt.Sprintf("%s(%d)", protocol, uint32(protocol))] = count
+	}
+	return counts
+}
+
+func decodeExtendedUserFlow(data *[]byte) (SFlowExtendedUserFlow, error) {
+	eu := SFlowExtendedUserFlow{}
+	var fdf SFlowFlowDataFormat
+	var srcUserLen uint32
+	var srcUserLenWithPad int
+	var srcUserBytes []byte
+	var dstUserLen uint32
+	var dstUserLenWithPad int
+	var dstUserBytes []byte
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	eu.EnterpriseID, eu.Format = fdf.decode()
+	*data, eu.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, eu.SourceCharSet = (*data)[4:], SFlowCharSet(binary.BigEndian.Uint32((*data)[:4]))
+	*data, srcUserLen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	srcUserLenWithPad = int(srcUserLen + ((4 - srcUserLen) % 4))
+	*data, srcUserBytes = (*data)[srcUserLenWithPad:], (*data)[:srcUserLenWithPad]
+	eu.SourceUserID = string(srcUserBytes[:srcUserLen])
+	*data, eu.DestinationCharSet = (*data)[4:], SFlowCharSet(binary.BigEndian.Uint32((*data)[:4]))
+	*data, dstUserLen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	dstUserLenWithPad = int(dstUserLen + ((4 - dstUserLen) % 4))
+	*data, dstUserBytes = (*data)[dstUserLenWithPad:], (*data)[:dstUserLenWithPad]
+	eu.DestinationUserID = string(dstUserBytes[:dstUserLen])
+	return eu, nil
+}
+
+func decodeExtendedURLRecord(data *[]byte) (SFlowExtendedURLRecord, error) {
+	eur := SFlowExtendedURLRecord{}
+	var fdf SFlowFlowDataFormat
+	var urlLen uint32
+	var urlLenWithPad int
+	var hostLen uint32
+	var hostLenWithPad int
+	var urlBytes []byte
+	var hostBytes []byte
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	eur.EnterpriseID, eur.Format = fdf.decode()
+	*data, eur.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, eur.Direction = (*data)[4:], SFlowURLDirection(binary.BigEndian.Uint32((*data)[:4]))
+	*data, urlLen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	urlLenWithPad = int(urlLen + ((4 - urlLen) % 4))
+	*data, urlBytes = (*data)[urlLenWithPad:], (*data)[:urlLenWithPad]
+	eur.URL = string(urlBytes[:urlLen])
+	*data, hostLen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hostLenWithPad = int(hostLen + ((4 - hostLen) % 4))
+	*data, hostBytes = (*data)[hostLenWithPad:], (*data)[:hostLenWithPad]
+	eur.Host = string(hostBytes[:hostLen])
+	return eur, nil
+}
+
+func decodeExtendedSwitchFlowRecord(data *[]byte) (SFlowExtendedSwitchFlowRecord, error) {
+	es := SFlowExtendedSwitchFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	es.EnterpriseID, es.Format = fdf.decode()
+	*data, es.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, es.IncomingVLAN = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, es.IncomingVLANPriority = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, es.OutgoingVLAN = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, es.OutgoingVLANPriority = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	return es, nil
+}
+
+func decodeExtendedRouterFlowRecord(data *[]byte) (SFlowExtendedRouterFlowRecord, error) {
+	er := SFlowExtendedRouterFlowRecord{}
+	var fdf SFlowFlowDataFormat
+	var extendedRouterAddressType layers.SFlowIPType
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	er.EnterpriseID, er.Format = fdf.decode()
+	*data, er.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, extendedRouterAddressType = (*data)[4:], layers.SFlowIPType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, er.NextHop = (*data)[extendedRouterAddressType.Length():], (*data)[:extendedRouterAddressType.Length()]
+	*data, er.NextHopSourceMask = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, er.NextHopDestinationMask = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	return er, nil
+}
+
+func decodeExtendedGatewayFlowRecord(data *[]byte) (SFlowExtendedGatewayFlowRecord, error) {
+	eg := SFlowExtendedGatewayFlowRecord{}
+	var fdf SFlowFlowDataFormat
+	var extendedGatewayAddressType layers.SFlowIPType
+	var communitiesLength uint32
+	var community uint32
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	eg.EnterpriseID, eg.Format = fdf.decode()
+	*data, eg.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, extendedGatewayAddressType = (*data)[4:], layers.SFlowIPType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, eg.NextHop = (*data)[extendedGatewayAddressType.Length():], (*data)[:extendedGatewayAddressType.Length()]
+	*data, eg.AS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, eg.SourceAS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, eg.PeerAS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, eg.ASPathCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	for i := uint32(0); i < eg.ASPathCount; i++ {
+		asPath := SFlowASDestination{}
+		if err := asPath.decodePath(data); err != nil {
+			return eg, err
+		}
+		eg.ASPath = append(eg.ASPath, asPath)
+	}
+	*data, communitiesLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	if err := checkSFlowArray(*data, communitiesLength, 4, "communities"); err != nil {
+		return eg, err
+	}
+	eg.Communities = make([]uint32, communitiesLength)
+	for j := uint32(0); j < communitiesLength; j++ {
+		*data, community = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		eg.Communities[j] = community
+	}
+	*data, eg.LocalPref = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	return eg, nil
+}
+
+func decodeEthernetFrameFlowRecord(data *[]byte) (SFlowEthernetFrameFlowRecord, error) {
+	es := SFlowEthernetFrameFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	es.EnterpriseID, es.Format = fdf.decode()
+	*data, es.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, es.FrameLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	// MAC addresses are XDR opaque<6>, padded to 8 bytes
+	*data, es.SrcMac = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, es.DstMac = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, es.Type = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return es, nil
+}
+
+func decodeIpv4FlowRecord(data *[]byte) (SFlowIpv4FlowRecord, error) {
+	rec := SFlowIpv4FlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv4Record, _ = decodeSFlowIpv4Record(data)
+
+	return rec, nil
+}
+
+func decodeIpv6FlowRecord(data *[]byte) (SFlowIpv6FlowRecord, error) {
+	rec := SFlowIpv6FlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv6Record, _ = decodeSFlowIpv6Record(data)
+
+	return rec, nil
+}
+
+func decodeSFlowIpv4Record(data *[]byte) (SFlowIpv4Record, error) {
+	si := SFlowIpv4Record{}
+
+	*data, si.Length = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.Protocol = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.IPSrc = (*data)[4:], net.IP((*data)[:4])
+	*data, si.IPDst = (*data)[4:], net.IP((*data)[:4])
+	*data, si.PortSrc = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.PortDst = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.TCPFlags = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.TOS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return si, nil
+}
+
+func decodeSFlowIpv6Record(data *[]byte) (SFlowIpv6Record, error) {
+	si := SFlowIpv6Record{}
+
+	*data, si.Length = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.Protocol = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.IPSrc = (*data)[16:], net.IP((*data)[:16])
+	*data, si.IPDst = (*data)[16:], net.IP((*data)[:16])
+	*data, si.PortSrc = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.PortDst = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.TCPFlags = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, si.Priority = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return si, nil
+}
+
+func decodeExtendedIpv4TunnelEgress(data *[]byte) (SFlowExtendedIpv4TunnelEgressRecord, error) {
+	rec := SFlowExtendedIpv4TunnelEgressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv4Record, _ = decodeSFlowIpv4Record(data)
+
+	return rec, nil
+}
+
+func decodeExtendedIpv4TunnelIngress(data *[]byte) (SFlowExtendedIpv4TunnelIngressRecord, error) {
+	rec := SFlowExtendedIpv4TunnelIngressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv4Record, _ = decodeSFlowIpv4Record(data)
+
+	return rec, nil
+}
+
+func decodeExtendedIpv6TunnelEgress(data *[]byte) (SFlowExtendedIpv6TunnelEgressRecord, error) {
+	rec := SFlowExtendedIpv6TunnelEgressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv6Record, _ = decodeSFlowIpv6Record(data)
+
+	return rec, nil
+}
+
+func decodeExtendedIpv6TunnelIngress(data *[]byte) (SFlowExtendedIpv6TunnelIngressRecord, error) {
+	rec := SFlowExtendedIpv6TunnelIngressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SFlowIpv6Record, _ = decodeSFlowIpv6Record(data)
+
+	return rec, nil
+}
+
+func decodeExtendedDecapsulateEgress(data *[]byte) (SFlowExtendedDecapsulateEgressRecord, error) {
+	rec := SFlowExtendedDecapsulateEgressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.InnerHeaderOffset = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedDecapsulateIngress(data *[]byte) (SFlowExtendedDecapsulateIngressRecord, error) {
+	rec := SFlowExtendedDecapsulateIngressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.InnerHeaderOffset = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedVniEgress(data *[]byte) (SFlowExtendedVniEgressRecord, error) {
+	rec := SFlowExtendedVniEgressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.VNI = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedVniIngress(data *[]byte) (SFlowExtendedVniIngressRecord, error) {
+	rec := SFlowExtendedVniIngressRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.VNI = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedEgressQueueRecord(data *[]byte) (SFlowExtendedEgressQueueRecord, error) {
+	rec := SFlowExtendedEgressQueueRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Queue = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedTransitRecord(data *[]byte) (SFlowExtendedTransitRecord, error) {
+	rec := SFlowExtendedTransitRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Delay = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedQueueRecord(data *[]byte) (SFlowExtendedQueueRecord, error) {
+	rec := SFlowExtendedQueueRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Depth = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedFunctionFlowRecord(data *[]byte) (SFlowExtendedFunctionFlowRecord, error) {
+	rec := SFlowExtendedFunctionFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Symbol = decodeSFlowString(data)
+
+	return rec, nil
+}
+
+func decodeExtendedMPLSFlowRecord(data *[]byte) (SFlowExtendedMPLSFlowRecord, error) {
+	rec := SFlowExtendedMPLSFlowRecord{}
+	var fdf SFlowFlowDataFormat
+	var nextHopAddressType layers.SFlowIPType
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, nextHopAddressType = (*data)[4:], layers.SFlowIPType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.NextHop = (*data)[nextHopAddressType.Length():], (*data)[:nextHopAddressType.Length()]
+	var err error
+	if rec.InLabelStack, err = decodeUint32Array(data); err != nil {
+		return rec, err
+	}
+	if rec.OutLabelStack, err = decodeUint32Array(data); err != nil {
+		return rec, err
+	}
+
+	return rec, nil
+}
+
+func decodeExtendedNATFlowRecord(data *[]byte) (SFlowExtendedNATFlowRecord, error) {
+	rec := SFlowExtendedNATFlowRecord{}
+	var fdf SFlowFlowDataFormat
+	var srcAddressType, dstAddressType layers.SFlowIPType
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, srcAddressType = (*data)[4:], layers.SFlowIPType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.SourceAddress = (*data)[srcAddressType.Length():], (*data)[:srcAddressType.Length()]
+	*data, dstAddressType = (*data)[4:], layers.SFlowIPType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.DestinationAddress = (*data)[dstAddressType.Length():], (*data)[:dstAddressType.Length()]
+
+	return rec, nil
+}
+
+func decodeExtendedMPLSTunnelFlowRecord(data *[]byte) (SFlowExtendedMPLSTunnelFlowRecord, error) {
+	rec := SFlowExtendedMPLSTunnelFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.TunnelLSPName = decodeSFlowString(data)
+	*data, rec.TunnelID = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.TunnelCOS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedMPLSVCFlowRecord(data *[]byte) (SFlowExtendedMPLSVCFlowRecord, error) {
+	rec := SFlowExtendedMPLSVCFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.VCInstanceName = decodeSFlowString(data)
+	*data, rec.VLLVCID = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.VCLabelCOS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedMPLSFECFlowRecord(data *[]byte) (SFlowExtendedMPLSFECFlowRecord, error) {
+	rec := SFlowExtendedMPLSFECFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.FTNDescr = decodeSFlowString(data)
+	*data, rec.FTNMask = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedMPLSLVPFECFlowRecord(data *[]byte) (SFlowExtendedMPLSLVPFECFlowRecord, error) {
+	rec := SFlowExtendedMPLSLVPFECFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.FECAddrPrefixLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedVLANTunnelFlowRecord(data *[]byte) (SFlowExtendedVLANTunnelFlowRecord, error) {
+	rec := SFlowExtendedVLANTunnelFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	tags, err := decodeUint32Array(data)
+	if err != nil {
+		return rec, err
+	}
+	for _, tag := range tags {
+		rec.Stack = append(rec.Stack, SFlowVLANTag(tag))
+	}
+
+	return rec, nil
+}
+
+func decodeExtended80211PayloadFlowRecord(data *[]byte) (SFlowExtended80211PayloadFlowRecord, error) {
+	rec := SFlowExtended80211PayloadFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.CipherSuite = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Data = decodeSFlowOpaque(data)
+
+	return rec, nil
+}
+
+func decodeExtended80211RxFlowRecord(data *[]byte) (SFlowExtended80211RxFlowRecord, error) {
+	rec := SFlowExtended80211RxFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SSID = decodeSFlowString(data)
+	*data, rec.BSSID = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, rec.Version = (*data)[4:], SFlowIEEE80211Version(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.Channel = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Speed = decodeUint64(data)
+	*data, rec.RSNI = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RCPI = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.PacketDuration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtended80211TxFlowRecord(data *[]byte) (SFlowExtended80211TxFlowRecord, error) {
+	rec := SFlowExtended80211TxFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.SSID = decodeSFlowString(data)
+	*data, rec.BSSID = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, rec.Version = (*data)[4:], SFlowIEEE80211Version(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.Transmissions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.PacketDuration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RetransDuration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Channel = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Speed = decodeUint64(data)
+	*data, rec.Power = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+// decodeExtended80211AggregationFlowRecord decodes the flow records of
+// every PDU of an aggregated frame with the same decoders as the records
+// of a flow sample
+func decodeExtended80211AggregationFlowRecord(data *[]byte) (SFlowExtended80211AggregationFlowRecord, error) {
+	rec := SFlowExtended80211AggregationFlowRecord{}
+	var fdf SFlowFlowDataFormat
+	var pduCount, recordCount uint32
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, pduCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	// a PDU is at least its record count
+	if err := checkSFlowArray(*data, pduCount, 4, "PDUs"); err != nil {
+		return rec, err
+	}
+	for i := uint32(0); i < pduCount; i++ {
+		*data, recordCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		// a record is at least its format and length
+		if err := checkSFlowArray(*data, recordCount, 8, "PDU records"); err != nil {
+			return rec, err
+		}
+		records, err := decodeFlowRecords(data, recordCount)
+		if err != nil {
+			return rec, err
+		}
+		rec.PDUs = append(rec.PDUs, SFlow80211PDU{Records: records})
+	}
+
+	return rec, nil
+}
+
+// decodeExtendedSocketFlowRecord decodes both the IPv4 and the IPv6
+// socket record
+func decodeExtendedSocketFlowRecord(data *[]byte) (SFlowExtendedSocketFlowRecord, error) {
+	rec := SFlowExtendedSocketFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	addressLength := net.IPv4len
+	if rec.Format == SFlowTypeExtendedSocketIpv6Flow {
+		addressLength = net.IPv6len
+	}
+	*data, rec.Protocol = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.LocalIP = (*data)[addressLength:], net.IP((*data)[:addressLength])
+	*data, rec.RemoteIP = (*data)[addressLength:], net.IP((*data)[:addressLength])
+	*data, rec.LocalPort = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RemotePort = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeExtendedTCPInfoFlowRecord(data *[]byte) (SFlowExtendedTCPInfoFlowRecord, error) {
+	rec := SFlowExtendedTCPInfoFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Direction = (*data)[4:], SFlowPacketDirection(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.SndMSS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RcvMSS = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Unacked = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Lost = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Retrans = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.PMTU = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RTT = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.RTTVar = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.SndCwnd = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Reordering = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.MinRTT = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rec, nil
+}
+
+func decodeMemcacheFlowRecord(data *[]byte) (SFlowMemcacheFlowRecord, error) {
+	rec := SFlowMemcacheFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Protocol = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Command = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Key = decodeSFlowString(data)
+	*data, rec.NumKeys = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.ValueBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Duration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Status = (*data)[4:], SFlowMemcacheStatus(binary.BigEndian.Uint32((*data)[:4]))
+
+	return rec, nil
+}
+
+// decodeHTTPRequestFlowRecord decodes both versions of the HTTP request
+// record, only the second one (2206) carries X-Forwarded-For
+func decodeHTTPRequestFlowRecord(data *[]byte) (SFlowHTTPRequestFlowRecord, error) {
+	rec := SFlowHTTPRequestFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Method = (*data)[4:], SFlowHTTPMethod(binary.BigEndian.Uint32((*data)[:4]))
+	*data, rec.Protocol = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.URI = decodeSFlowString(data)
+	rec.Host = decodeSFlowString(data)
+	rec.Referer = decodeSFlowString(data)
+	rec.UserAgent = decodeSFlowString(data)
+	if rec.Format == SFlowTypeHTTP2Flow {
+		rec.XFF = decodeSFlowString(data)
+	}
+	rec.AuthUser = decodeSFlowString(data)
+	rec.MimeType = decodeSFlowString(data)
+	rec.RequestBytes = decodeUint64(data)
+	rec.ResponseBytes = decodeUint64(data)
+	*data, rec.Duration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Status = (*data)[4:], int32(binary.BigEndian.Uint32((*data)[:4]))
+
+	return rec, nil
+}
+
+func decodeAppOperationFlowRecord(data *[]byte) (SFlowAppOperationFlowRecord, error) {
+	rec := SFlowAppOperationFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Context = decodeSFlowAppContext(data)
+	rec.StatusDescr = decodeSFlowString(data)
+	rec.RequestBytes = decodeUint64(data)
+	rec.ResponseBytes = decodeUint64(data)
+	*data, rec.Duration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rec.Status = (*data)[4:], SFlowAppStatus(binary.BigEndian.Uint32((*data)[:4]))
+
+	return rec, nil
+}
+
+func decodeAppParentContextFlowRecord(data *[]byte) (SFlowAppParentContextFlowRecord, error) {
+	rec := SFlowAppParentContextFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Context = decodeSFlowAppContext(data)
+
+	return rec, nil
+}
+
+func decodeAppActorFlowRecord(data *[]byte) (SFlowAppActorFlowRecord, error) {
+	rec := SFlowAppActorFlowRecord{}
+	var fdf SFlowFlowDataFormat
+
+	*data, fdf = (*data)[4:], SFlowFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rec.EnterpriseID, rec.Format = fdf.decode()
+	*data, rec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	rec.Actor = decodeSFlowString(data)
+
+	return rec, nil
+}
+
+func decodeSFlowAppContext(data *[]byte) SFlowAppContext {
+	return SFlowAppContext{
+		Application: decodeSFlowString(data),
+		Operation:   decodeSFlowString(data),
+		Attributes:  decodeSFlowString(data),
+	}
+}
+
+// decodeSFlowString decodes an XDR string: its length followed by the
+// bytes padded to a multiple of 4
+func decodeSFlowString(data *[]byte) string {
+	var strLen uint32
+	var strBytes []byte
+
+	*data, strLen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	strLenWithPad := int(strLen + ((4 - strLen) % 4))
+	*data, strBytes = (*data)[strLenWithPad:], (*data)[:strLenWithPad]
+	return string(strBytes[:strLen])
+}
+
+// decodeSFlowOpaque decodes XDR variable length opaque data: its length
+// followed by the bytes padded to a multiple of 4
+func decodeSFlowOpaque(data *[]byte) []byte {
+	var length uint32
+	var opaque []byte
+
+	*data, length = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	lengthWithPad := int(length + ((4 - length) % 4))
+	*data, opaque = (*data)[lengthWithPad:], (*data)[:lengthWithPad]
+	return opaque[:length]
+}
+
+// decodeUint32Array decodes an XDR variable length array of unsigned
+// ints: the element count followed by the elements
+func decodeUint32Array(data *[]byte) ([]uint32, error) {
+	var count uint32
+
+	*data, count = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	if err := checkSFlowArray(*data, count, 4, "array"); err != nil {
+		return nil, err
+	}
+	values := make([]uint32, count)
+	for i := uint32(0); i < count; i++ {
+		*data, values[i] = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	}
+	return values, nil
+}
+
+// checkSFlowArray checks that the count elements of size bytes of an
+// array fit in the data left before they are allocated. The count comes
+// from the datagram and running out of memory cannot be recovered from.
+func checkSFlowArray(data []byte, count uint32, size int, name string) error {
+	if uint64(count)*uint64(size) > uint64(len(data)) {
+		return fmt.Errorf("%s of %d elements, %d bytes left", name, count, len(data))
+	}
+	return nil
+}
+
+// decodeUint64 decodes an XDR unsigned hyper
+func decodeUint64(data *[]byte) uint64 {
+	var value uint64
+
+	*data, value = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	return value
+}
+
+// ****************************************************************************************************
+//  Counter Decoding
+// ****************************************************************************************************
+
+func decodeCounterSample(data *[]byte, expanded bool) (SFlowCounterSample, error) {
+	s := SFlowCounterSample{}
+	var sdc SFlowDataSource
+	var sdce SFlowDataSourceExpanded
+	var sdf SFlowDataFormat
+
+	*data, sdf = (*data)[4:], SFlowDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	s.EnterpriseID, s.Format = sdf.decode()
+	*data, s.SampleLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, s.SequenceNumber = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	if expanded {
+		*data, sdce = (*data)[8:], SFlowDataSourceExpanded{SFlowSourceFormat(binary.BigEndian.Uint32((*data)[:4])), SFlowSourceValue(binary.BigEndian.Uint32((*data)[4:8]))}
+		s.SourceIDClass, s.SourceIDIndex = sdce.decode()
+	} else {
+		*data, sdc = (*data)[4:], SFlowDataSource(binary.BigEndian.Uint32((*data)[:4]))
+		s.SourceIDClass, s.SourceIDIndex = sdc.decode()
+	}
+	//fmt.Println("SourceIDIndex: ", s.SourceIDIndex)
+	*data, s.RecordCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	var err error
+	s.Records, err = decodeCounterRecords(data, s.RecordCount)
+	return s, err
+}
+
+// decodeCounterRecords decodes the counter records of a counter sample
+func decodeCounterRecords(data *[]byte, count uint32) (SFlowRecords, error) {
+	var records SFlowRecords
+
+	for i := uint32(0); i < count; i++ {
+		cdf := SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+		_, counterRecordType := cdf.decode()
+		switch counterRecordType {
+		case SFlowTypeGenericInterfaceCounters:
+			if record, err := decodeGenericInterfaceCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeEthernetInterfaceCounters:
+			if record, err := decodeEthernetCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeTokenRingInterfaceCounters:
+			skipRecord(data)
+			return records, errors.New("skipping TypeTokenRingInterfaceCounters")
+		case SFlowType100BaseVGInterfaceCounters:
+			skipRecord(data)
+			return records, errors.New("skipping Type100BaseVGInterfaceCounters")
+		case SFlowTypeVLANCounters:
+			if record, err := decodeVLANCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowType80211Counters:
+			if record, err := decode80211Counters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeLACPCounters:
+			if record, err := decodeLACPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeSFPCounters:
+			if record, err := decodeSFPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeProcessorCounters:
+			if record, err := decodeProcessorCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeRadioCounters:
+			if record, err := decodeRadioCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeOFPortCounter:
+			if record, err := decodeOFPortCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeOFPortNameCounter:
+			if record, err := decodeOFPortNameCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostDescrCounters:
+			if record, err := decodeHostDescrCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostAdaptersCounters:
+			if record, err := decodeHostAdaptersCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostParentCounters:
+			if record, err := decodeHostParentCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostCPUCounters:
+			if record, err := decodeHostCPUCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostMemoryCounters:
+			if record, err := decodeHostMemoryCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostDiskCounters:
+			if record, err := decodeHostDiskCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostNetIOCounters:
+			if record, err := decodeHostNetIOCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostIPCounters:
+			if record, err := decodeHostIPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostICMPCounters:
+			if record, err := decodeHostICMPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostTCPCounters:
+			if record, err := decodeHostTCPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHostUDPCounters:
+			if record, err := decodeHostUDPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVirtNodeCounters:
+			if record, err := decodeVirtNodeCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVirtCPUCounters:
+			if record, err := decodeVirtCPUCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVirtMemoryCounters:
+			if record, err := decodeVirtMemoryCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVirtDiskCounters:
+			if record, err := decodeVirtDiskCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVirtNetIOCounters:
+			if record, err := decodeVirtNetIOCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeMemcacheLegacyCounters:
+			if record, err := decodeMemcacheLegacyCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeHTTPCounters:
+			if record, err := decodeHTTPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppCounters:
+			if record, err := decodeAppCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppResourcesCounters:
+			if record, err := decodeAppResourcesCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeMemcacheCounters:
+			if record, err := decodeMemcacheCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeVDICounters:
+			if record, err := decodeVDICounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeAppWorkersCounters:
+			if record, err := decodeAppWorkersCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		case SFlowTypeOVSDPCounters:
+			if record, err := decodeOVSDPCounters(data); err == nil {
+				records = append(records, record)
+			} else {
+				return records, err
+			}
+		default:
+			return records, fmt.Errorf("Invalid counter record type: %d", counterRecordType)
+		}
+	}
+	return records, nil
+}
+
+func skipRecord(data *[]byte) {
+	recordLength := int(binary.BigEndian.Uint32((*data)[4:]))
+	*data = (*data)[(recordLength+((4-recordLength)%4))+8:]
+}
+
+func decodeGenericInterfaceCounters(data *[]byte) (SFlowGenericInterfaceCounters, error) {
+	gic := SFlowGenericInterfaceCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	gic.EnterpriseID, gic.Format = cdf.decode()
+	*data, gic.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfIndex = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfType = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfSpeed = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	*data, gic.IfDirection = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfStatus = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInOctets = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	*data, gic.IfInUcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInMulticastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInBroadcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInDiscards = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfInUnknownProtos = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfOutOctets = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	*data, gic.IfOutUcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfOutMulticastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfOutBroadcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfOutDiscards = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfOutErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, gic.IfPromiscuousMode = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	return gic, nil
+}
+
+func decodeEthernetCounters(data *[]byte) (SFlowEthernetCounters, error) {
+	ec := SFlowEthernetCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ec.EnterpriseID, ec.Format = cdf.decode()
+	*data, ec.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.AlignmentErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.FCSErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.SingleCollisionFrames = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.MultipleCollisionFrames = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.SQETestErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.DeferredTransmissions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.LateCollisions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.ExcessiveCollisions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.InternalMacTransmitErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.CarrierSenseErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.FrameTooLongs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.InternalMacReceiveErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ec.SymbolErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	return ec, nil
+}
+
+func decodeVLANCounters(data *[]byte) (SFlowVLANCounters, error) {
+	vc := SFlowVLANCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vc.EnterpriseID, vc.Format = cdf.decode()
+	*data, vc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.VlanID = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.Octets = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	*data, vc.UcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.MulticastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.BroadcastPkts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.Discards = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vc, nil
+}
+
+func decode80211Counters(data *[]byte) (SFlow80211Counters, error) {
+	wc := SFlow80211Counters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	wc.EnterpriseID, wc.Format = cdf.decode()
+	*data, wc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.TransmittedFragmentCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.MulticastTransmittedFrameCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.FailedCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.RetryCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.MultipleRetryCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.FrameDuplicateCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.RTSSuccessCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.RTSFailureCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.ACKFailureCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.ReceivedFragmentCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.MulticastReceivedFrameCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.FCSErrorCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.TransmittedFrameCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.WEPUndecryptableCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.QoSDiscardedFragmentCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.AssociatedStationCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.QoSCFPollsReceivedCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.QoSCFPollsUnusedCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.QoSCFPollsUnusableCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, wc.QoSCFPollsLostCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return wc, nil
+}
+
+func decodeLACPCounters(data *[]byte) (SFlowLACPCounters, error) {
+	lc := SFlowLACPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	lc.EnterpriseID, lc.Format = cdf.decode()
+	*data, lc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.ActorSystemID = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, lc.PartnerSystemID = (*data)[8:], net.HardwareAddr((*data)[:6])
+	*data, lc.AttachedAggID = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	lc.ActorAdminState, lc.ActorOperState = SFlowLACPState((*data)[0]), SFlowLACPState((*data)[1])
+	lc.PartnerAdminState, lc.PartnerOperState = SFlowLACPState((*data)[2]), SFlowLACPState((*data)[3])
+	*data = (*data)[4:]
+	*data, lc.LACPDUsRx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.MarkerPDUsRx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.MarkerResponsePDUsRx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.UnknownRx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.IllegalRx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.LACPDUsTx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.MarkerPDUsTx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, lc.MarkerResponsePDUsTx = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return lc, nil
+}
+
+func decodeSFPCounters(data *[]byte) (SFlowSFPCounters, error) {
+	sc := SFlowSFPCounters{}
+	var cdf SFlowCounterDataFormat
+	var laneCount uint32
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	sc.EnterpriseID, sc.Format = cdf.decode()
+	*data, sc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, sc.ModuleID = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, sc.ModuleTotalLanes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, sc.ModuleSupplyVolts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, sc.ModuleTemperature = (*data)[4:], int32(binary.BigEndian.Uint32((*data)[:4]))
+	*data, laneCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	// a lane is 10 unsigned ints
+	if err := checkSFlowArray(*data, laneCount, 40, "SFP lanes"); err != nil {
+		return sc, err
+	}
+	for i := uint32(0); i < laneCount; i++ {
+		lane := SFlowSFPLane{}
+		*data, lane.LaneIndex = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.TxBiasCurrent = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.TxPower = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.TxPowerMin = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.TxPowerMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.TxWavelength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.RxPower = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.RxPowerMin = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.RxPowerMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, lane.RxWavelength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		sc.Lanes = append(sc.Lanes, lane)
+	}
+
+	return sc, nil
+}
+
+func decodeProcessorCounters(data *[]byte) (SFlowProcessorCounters, error) {
+	pc := SFlowProcessorCounters{}
+	var cdf SFlowCounterDataFormat
+	var high32, low32 uint32
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	pc.EnterpriseID, pc.Format = cdf.decode()
+	*data, pc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	*data, pc.FiveSecCpu = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, pc.OneMinCpu = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, pc.FiveMinCpu = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, high32 = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, low32 = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	pc.TotalMemory = (uint64(high32) << 32) + uint64(low32)
+	*data, high32 = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, low32 = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	pc.FreeMemory = (uint64(high32) << 32) + uint64(low32)
+
+	return pc, nil
+}
+
+func decodeRadioCounters(data *[]byte) (SFlowRadioCounters, error) {
+	rc := SFlowRadioCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	rc.EnterpriseID, rc.Format = cdf.decode()
+	*data, rc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rc.ElapsedTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rc.OnChannelTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, rc.OnChannelBusyTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return rc, nil
+}
+
+func decodeOFPortCounters(data *[]byte) (SFlowOFPortCounters, error) {
+	ofc := SFlowOFPortCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ofc.EnterpriseID, ofc.Format = cdf.decode()
+	*data, ofc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	//*data, ofc.OfDataPathId = (*data)[8:], binary.BigEndian.Uint64((*data)[:8])
+	*data, ofc.OfDataPathId = (*data)[8:], (*data)[:8]
+	*data, ofc.OfPort = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	//fmt.Println("Path ID: ", ofc.OfDataPathId)
+	return ofc, nil
+}
+
+func decodeOFPortNameCounters(data *[]byte) (SFlowOFPortNameCounters, error) {
+	ofpnc := SFlowOFPortNameCounters{}
+	var cdf SFlowCounterDataFormat
+	var portNameLenght uint32
+	var portNameLenWithPad int
+	var portNameBytes []byte
+
+	/*
+		remove SFlowFlowDataFormat byte from previous method (in prev method we didn't remove them from *data)
+		tag = 1005 -> SFLCOUNTERS_PORTNAME
+		length
+		skipBytes
+		name
+	*/
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ofpnc.EnterpriseID, ofpnc.Format = cdf.decode()
+	*data, ofpnc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	*data, portNameLenght = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	portNameLenWithPad = int(portNameLenght + ((4 - portNameLenght) % 4))
+	*data, portNameBytes = (*data)[portNameLenWithPad:], (*data)[:portNameLenWithPad]
+	ofpnc.OfPortName = string(portNameBytes[:portNameLenght])
+	//fmt.Println("port name: ", ofpnc.OfPortName)
+
+	return ofpnc, nil
+}
+
+func decodeHostDescrCounters(data *[]byte) (SFlowHostDescrCounters, error) {
+	hd := SFlowHostDescrCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hd.EnterpriseID, hd.Format = cdf.decode()
+	*data, hd.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hd.Hostname = decodeSFlowString(data)
+	*data, hd.UUID = (*data)[16:], (*data)[:16]
+	*data, hd.MachineType = (*data)[4:], SFlowMachineType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, hd.OSName = (*data)[4:], SFlowOSName(binary.BigEndian.Uint32((*data)[:4]))
+	hd.OSRelease = decodeSFlowString(data)
+
+	return hd, nil
+}
+
+func decodeHostAdaptersCounters(data *[]byte) (SFlowHostAdaptersCounters, error) {
+	ha := SFlowHostAdaptersCounters{}
+	var cdf SFlowCounterDataFormat
+	var adapterCount, macCount uint32
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ha.EnterpriseID, ha.Format = cdf.decode()
+	*data, ha.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, adapterCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	// an adapter is at least its ifIndex and MAC count
+	if err := checkSFlowArray(*data, adapterCount, 8, "adapters"); err != nil {
+		return ha, err
+	}
+	for i := uint32(0); i < adapterCount; i++ {
+		adapter := SFlowHostAdapter{}
+		*data, adapter.IfIndex = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		*data, macCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		if err := checkSFlowArray(*data, macCount, 8, "MAC addresses"); err != nil {
+			return ha, err
+		}
+		for j := uint32(0); j < macCount; j++ {
+			var mac net.HardwareAddr
+			// MAC addresses are XDR opaque<6>, padded to 8 bytes
+			*data, mac = (*data)[8:], net.HardwareAddr((*data)[:6])
+			adapter.MacAddresses = append(adapter.MacAddresses, mac)
+		}
+		ha.Adapters = append(ha.Adapters, adapter)
+	}
+
+	return ha, nil
+}
+
+func decodeHostParentCounters(data *[]byte) (SFlowHostParentCounters, error) {
+	hp := SFlowHostParentCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hp.EnterpriseID, hp.Format = cdf.decode()
+	*data, hp.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hp.ContainerType = (*data)[4:], SFlowSourceFormat(binary.BigEndian.Uint32((*data)[:4]))
+	*data, hp.ContainerIndex = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return hp, nil
+}
+
+func decodeHostCPUCounters(data *[]byte) (SFlowHostCPUCounters, error) {
+	hc := SFlowHostCPUCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hc.EnterpriseID, hc.Format = cdf.decode()
+	*data, hc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	record := (*data)[:hc.FlowDataLength]
+	*data = (*data)[hc.FlowDataLength:]
+
+	record, hc.LoadOne = record[4:], math.Float32frombits(binary.BigEndian.Uint32(record[:4]))
+	record, hc.LoadFive = record[4:], math.Float32frombits(binary.BigEndian.Uint32(record[:4]))
+	record, hc.LoadFifteen = record[4:], math.Float32frombits(binary.BigEndian.Uint32(record[:4]))
+	record, hc.ProcRun = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.ProcTotal = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUNum = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUSpeed = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.Uptime = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUUser = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUNice = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUSystem = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUIdle = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUWio = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUIntr = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.CPUSintr = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.Interrupts = record[4:], binary.BigEndian.Uint32(record[:4])
+	record, hc.Contexts = record[4:], binary.BigEndian.Uint32(record[:4])
+	// steal / guest times were appended to the structure later
+	if len(record) >= 12 {
+		record, hc.CPUSteal = record[4:], binary.BigEndian.Uint32(record[:4])
+		record, hc.CPUGuest = record[4:], binary.BigEndian.Uint32(record[:4])
+		record, hc.CPUGuestNice = record[4:], binary.BigEndian.Uint32(record[:4])
+	}
+
+	return hc, nil
+}
+
+func decodeHostMemoryCounters(data *[]byte) (SFlowHostMemoryCounters, error) {
+	hm := SFlowHostMemoryCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hm.EnterpriseID, hm.Format = cdf.decode()
+	*data, hm.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hm.MemTotal = decodeUint64(data)
+	hm.MemFree = decodeUint64(data)
+	hm.MemShared = decodeUint64(data)
+	hm.MemBuffers = decodeUint64(data)
+	hm.MemCached = decodeUint64(data)
+	hm.SwapTotal = decodeUint64(data)
+	hm.SwapFree = decodeUint64(data)
+	*data, hm.PageIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hm.PageOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hm.SwapIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hm.SwapOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return hm, nil
+}
+
+func decodeHostDiskCounters(data *[]byte) (SFlowHostDiskCounters, error) {
+	hd := SFlowHostDiskCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hd.EnterpriseID, hd.Format = cdf.decode()
+	*data, hd.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hd.DiskTotal = decodeUint64(data)
+	hd.DiskFree = decodeUint64(data)
+	*data, hd.PartMaxUsed = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hd.Reads = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hd.BytesRead = decodeUint64(data)
+	*data, hd.ReadTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hd.Writes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hd.BytesWritten = decodeUint64(data)
+	*data, hd.WriteTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return hd, nil
+}
+
+func decodeHostNetIOCounters(data *[]byte) (SFlowHostNetIOCounters, error) {
+	hn := SFlowHostNetIOCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hn.EnterpriseID, hn.Format = cdf.decode()
+	*data, hn.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hn.BytesIn = decodeUint64(data)
+	*data, hn.PacketsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hn.ErrorsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hn.DropsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	hn.BytesOut = decodeUint64(data)
+	*data, hn.PacketsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hn.ErrorsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hn.DropsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return hn, nil
+}
+
+func decodeHostIPCounters(data *[]byte) (SFlowHostIPCounters, error) {
+	ic := SFlowHostIPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ic.EnterpriseID, ic.Format = cdf.decode()
+	*data, ic.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPForwarding = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPDefaultTTL = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInReceives = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInHdrErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInAddrErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPForwDatagrams = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInUnknownProtos = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInDiscards = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPInDelivers = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPOutRequests = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPOutDiscards = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPOutNoRoutes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPReasmTimeout = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPReasmReqds = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPReasmOKs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPReasmFails = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPFragOKs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPFragFails = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.IPFragCreates = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return ic, nil
+}
+
+func decodeHostICMPCounters(data *[]byte) (SFlowHostICMPCounters, error) {
+	ic := SFlowHostICMPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ic.EnterpriseID, ic.Format = cdf.decode()
+	*data, ic.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInMsgs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInDestUnreachs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInTimeExcds = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInParamProbs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInSrcQuenchs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInRedirects = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInEchos = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInEchoReps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInTimestamps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInAddrMasks = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPInAddrMaskReps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutMsgs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutDestUnreachs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutTimeExcds = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutParamProbs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutSrcQuenchs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutRedirects = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutEchos = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutEchoReps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutTimestamps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutTimestampReps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutAddrMasks = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ic.ICMPOutAddrMaskReps = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return ic, nil
+}
+
+func decodeHostTCPCounters(data *[]byte) (SFlowHostTCPCounters, error) {
+	tc := SFlowHostTCPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	tc.EnterpriseID, tc.Format = cdf.decode()
+	*data, tc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPRtoAlgorithm = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPRtoMin = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPRtoMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPMaxConn = (*data)[4:], int32(binary.BigEndian.Uint32((*data)[:4]))
+	*data, tc.TCPActiveOpens = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPPassiveOpens = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPAttemptFails = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPEstabResets = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPCurrEstab = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPInSegs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPOutSegs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPRetransSegs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPInErrs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPOutRsts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, tc.TCPInCsumErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return tc, nil
+}
+
+func decodeHostUDPCounters(data *[]byte) (SFlowHostUDPCounters, error) {
+	uc := SFlowHostUDPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	uc.EnterpriseID, uc.Format = cdf.decode()
+	*data, uc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPInDatagrams = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPNoPorts = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPInErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPOutDatagrams = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPRcvbufErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPSndbufErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, uc.UDPInCsumErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return uc, nil
+}
+
+func decodeVirtNodeCounters(data *[]byte) (SFlowVirtNodeCounters, error) {
+	vn := SFlowVirtNodeCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vn.EnterpriseID, vn.Format = cdf.decode()
+	*data, vn.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.MHz = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.CPUs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vn.Memory = decodeUint64(data)
+	vn.MemoryFree = decodeUint64(data)
+	*data, vn.NumDomains = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vn, nil
+}
+
+func decodeVirtCPUCounters(data *[]byte) (SFlowVirtCPUCounters, error) {
+	vc := SFlowVirtCPUCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vc.EnterpriseID, vc.Format = cdf.decode()
+	*data, vc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.State = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.CPUTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.NrVirtCPU = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vc, nil
+}
+
+func decodeVirtMemoryCounters(data *[]byte) (SFlowVirtMemoryCounters, error) {
+	vm := SFlowVirtMemoryCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vm.EnterpriseID, vm.Format = cdf.decode()
+	*data, vm.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vm.Memory = decodeUint64(data)
+	vm.MaxMemory = decodeUint64(data)
+
+	return vm, nil
+}
+
+func decodeVirtDiskCounters(data *[]byte) (SFlowVirtDiskCounters, error) {
+	vd := SFlowVirtDiskCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vd.EnterpriseID, vd.Format = cdf.decode()
+	*data, vd.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vd.Capacity = decodeUint64(data)
+	vd.Allocation = decodeUint64(data)
+	vd.Available = decodeUint64(data)
+	*data, vd.ReadRequests = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vd.BytesRead = decodeUint64(data)
+	*data, vd.WriteRequests = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vd.BytesWritten = decodeUint64(data)
+	*data, vd.Errors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vd, nil
+}
+
+func decodeVirtNetIOCounters(data *[]byte) (SFlowVirtNetIOCounters, error) {
+	vn := SFlowVirtNetIOCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vn.EnterpriseID, vn.Format = cdf.decode()
+	*data, vn.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vn.BytesIn = decodeUint64(data)
+	*data, vn.PacketsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.ErrorsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.DropsIn = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	vn.BytesOut = decodeUint64(data)
+	*data, vn.PacketsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.ErrorsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vn.DropsOut = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vn, nil
+}
+
+func decodeMemcacheLegacyCounters(data *[]byte) (SFlowMemcacheLegacyCounters, error) {
+	mc := SFlowMemcacheLegacyCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	mc.EnterpriseID, mc.Format = cdf.decode()
+	*data, mc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Uptime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.RusageUser = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.RusageSystem = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CurrConnections = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.TotalConnections = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ConnectionStructures = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdGet = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdSet = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdFlush = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.GetHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.GetMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DeleteMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DeleteHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.IncrMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.IncrHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DecrMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DecrHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasBadval = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.AuthCmds = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.AuthErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	mc.BytesRead = decodeUint64(data)
+	mc.BytesWritten = decodeUint64(data)
+	*data, mc.LimitMaxbytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.AcceptingConns = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ListenDisabledNum = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Threads = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ConnYields = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	mc.Bytes = decodeUint64(data)
+	*data, mc.CurrItems = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.TotalItems = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Evictions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return mc, nil
+}
+
+func decodeHTTPCounters(data *[]byte) (SFlowHTTPCounters, error) {
+	hc := SFlowHTTPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	hc.EnterpriseID, hc.Format = cdf.decode()
+	*data, hc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodOptionCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodGetCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodHeadCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodPostCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodPutCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodDeleteCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodTraceCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodConnectCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.MethodOtherCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.Status1XXCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.Status2XXCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.Status3XXCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.Status4XXCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.Status5XXCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, hc.StatusOtherCount = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return hc, nil
+}
+
+func decodeAppCounters(data *[]byte) (SFlowAppCounters, error) {
+	ac := SFlowAppCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ac.EnterpriseID, ac.Format = cdf.decode()
+	*data, ac.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	ac.Application = decodeSFlowString(data)
+	*data, ac.StatusOK = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusOther = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusTimeout = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusInternalError = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusBadRequest = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusForbidden = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusTooLarge = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusNotImplemented = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusNotFound = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusUnavailable = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ac.StatusUnauthorized = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return ac, nil
+}
+
+func decodeAppResourcesCounters(data *[]byte) (SFlowAppResourcesCounters, error) {
+	ar := SFlowAppResourcesCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	ar.EnterpriseID, ar.Format = cdf.decode()
+	*data, ar.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ar.UserTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ar.SystemTime = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	ar.MemUsed = decodeUint64(data)
+	ar.MemMax = decodeUint64(data)
+	*data, ar.FdOpen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ar.FdMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ar.ConnOpen = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, ar.ConnMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return ar, nil
+}
+
+func decodeMemcacheCounters(data *[]byte) (SFlowMemcacheCounters, error) {
+	mc := SFlowMemcacheCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	mc.EnterpriseID, mc.Format = cdf.decode()
+	*data, mc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdSet = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdTouch = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CmdFlush = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.GetHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.GetMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DeleteHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DeleteMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.IncrHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.IncrMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DecrHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.DecrMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasMisses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CasBadval = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.AuthCmds = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.AuthErrors = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Threads = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ConnYields = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ListenDisabledNum = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CurrConnections = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.RejectedConnections = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.TotalConnections = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.ConnectionStructures = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Evictions = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.Reclaimed = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.CurrItems = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, mc.TotalItems = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	mc.BytesRead = decodeUint64(data)
+	mc.BytesWritten = decodeUint64(data)
+	mc.Bytes = decodeUint64(data)
+	mc.LimitMaxbytes = decodeUint64(data)
+
+	return mc, nil
+}
+
+func decodeVDICounters(data *[]byte) (SFlowVDICounters, error) {
+	vc := SFlowVDICounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	vc.EnterpriseID, vc.Format = cdf.decode()
+	*data, vc.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.SessionsCurrent = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.SessionsTotal = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.SessionsDuration = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.TxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RxPackets = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.TxPackets = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RxPacketsLost = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.TxPacketsLost = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RTTMinMs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RTTMaxMs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.RTTAvgMs = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.AudioRxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.AudioTxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.AudioTxLimit = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgRxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgTxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgFrames = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgQualMin = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgQualMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.ImgQualAvg = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.UsbRxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, vc.UsbTxBytes = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return vc, nil
+}
+
+func decodeAppWorkersCounters(data *[]byte) (SFlowAppWorkersCounters, error) {
+	aw := SFlowAppWorkersCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	aw.EnterpriseID, aw.Format = cdf.decode()
+	*data, aw.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, aw.WorkersActive = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, aw.WorkersIdle = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, aw.WorkersMax = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, aw.RequestsDelayed = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, aw.RequestsDropped = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return aw, nil
+}
+
+func decodeOVSDPCounters(data *[]byte) (SFlowOVSDPCounters, error) {
+	od := SFlowOVSDPCounters{}
+	var cdf SFlowCounterDataFormat
+
+	*data, cdf = (*data)[4:], SFlowCounterDataFormat(binary.BigEndian.Uint32((*data)[:4]))
+	od.EnterpriseID, od.Format = cdf.decode()
+	*data, od.FlowDataLength = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.Hits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.Misses = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.Lost = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.MaskHits = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.Flows = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	*data, od.Masks = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+
+	return od, nil
+}
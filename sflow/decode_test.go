@@ -0,0 +1,323 @@
+package sflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// xdr builds the XDR encoding of a datagram out of its 32 bit words
+func xdr(words ...uint32) []byte {
+	data := make([]byte, 0, 4*len(words))
+	for _, word := range words {
+		data = binary.BigEndian.AppendUint32(data, word)
+	}
+	return data
+}
+
+// testDatagram wraps flow records in a flow sample and a datagram
+func testDatagram(records ...[]byte) []byte {
+	var body []byte
+	for _, record := range records {
+		body = append(body, record...)
+	}
+	sample := append(xdr(1, 0x0000000a, 1024, 2048, 0, 10, 11, uint32(len(records))), body...)
+	datagram := xdr(5, 1, 0xc0000201, 0, 1, 1000, 1)
+	datagram = append(datagram, xdr(uint32(SFlowTypeFlowSample), uint32(len(sample)))...)
+	return append(datagram, sample...)
+}
+
+// testCounterDatagram wraps counter records in a counter sample and a
+// datagram
+func testCounterDatagram(records ...[]byte) []byte {
+	var body []byte
+	for _, record := range records {
+		body = append(body, record...)
+	}
+	sample := append(xdr(1, 0x0000000a, uint32(len(records))), body...)
+	datagram := xdr(5, 1, 0xc0000201, 0, 1, 1000, 1)
+	datagram = append(datagram, xdr(uint32(SFlowTypeCounterSample), uint32(len(sample)))...)
+	return append(datagram, sample...)
+}
+
+// testCounterRecord prefixes the fields of a counter record with its
+// format and length
+func testCounterRecord(format SFlowCounterRecordType, fields ...uint32) []byte {
+	return append(xdr(uint32(format), uint32(4*len(fields))), xdr(fields...)...)
+}
+
+// testRecord prefixes the fields of a record with its format and length
+func testRecord(format SFlowFlowRecordType, fields ...uint32) []byte {
+	return append(xdr(uint32(format), uint32(4*len(fields))), xdr(fields...)...)
+}
+
+// The counts of the arrays come from the datagram, a count larger than
+// the datagram must not be allocated
+func TestDecodeArrayCounts(t *testing.T) {
+	const huge = 0xFFFFFFF0
+	tests := []struct {
+		name   string
+		record []byte
+	}{
+		{"mpls in labels", testRecord(SFlowTypeExtendedMlpsFlow, 1, 0x0a000001, huge)},
+		{"mpls out labels", testRecord(SFlowTypeExtendedMlpsFlow, 1, 0x0a000001, 1, 16, huge)},
+		{"vlan tunnel stack", testRecord(SFlowTypeExtendedVlanFlow, huge)},
+		{"gateway as path", testRecord(SFlowTypeExtendedGatewayFlow, 1, 0x0a000001, 1, 2, 3, 1, 2, huge)},
+		{"gateway communities", testRecord(SFlowTypeExtendedGatewayFlow, 1, 0x0a000001, 1, 2, 3, 0, huge)},
+	}
+	for _, test := range tests {
+		datagram, err := Decode(testDatagram(test.record))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(datagram.FlowSamples) != 0 {
+			t.Errorf("%s: the sample was kept %+v", test.name, datagram.FlowSamples)
+		}
+		inspection := Inspect(testDatagram(test.record))
+		if len(inspection.Samples) != 1 || len(inspection.Samples[0].Records) != 1 || inspection.Samples[0].Records[0].Err == nil {
+			t.Errorf("%s: inspection %+v", test.name, inspection)
+		}
+	}
+}
+
+// The loops over the lanes, adapters, MAC addresses and PDUs are bounded
+// by the record like the arrays
+func TestDecodeLoopCounts(t *testing.T) {
+	const huge = 0xFFFFFFF0
+	tests := []struct {
+		name     string
+		datagram []byte
+		counters bool
+	}{
+		{"sfp lanes", testCounterDatagram(testCounterRecord(SFlowTypeSFPCounters, 1, 4, 3300, 40, huge)), true},
+		{"host adapters", testCounterDatagram(testCounterRecord(SFlowTypeHostAdaptersCounters, huge)), true},
+		{"host adapter mac addresses", testCounterDatagram(testCounterRecord(SFlowTypeHostAdaptersCounters, 1, 3, huge)), true},
+		{"802.11 pdus", testDatagram(testRecord(SFlowTypeExtended80211AggregationFlow, huge)), false},
+		{"802.11 pdu records", testDatagram(testRecord(SFlowTypeExtended80211AggregationFlow, 1, huge)), false},
+	}
+	for _, test := range tests {
+		datagram, err := Decode(test.datagram)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(datagram.FlowSamples) != 0 || len(datagram.CounterSamples) != 0 {
+			t.Errorf("%s: the sample was kept %+v", test.name, datagram)
+		}
+		inspection := Inspect(test.datagram)
+		if len(inspection.Samples) != 1 || len(inspection.Samples[0].Records) != 1 || inspection.Samples[0].Records[0].Err == nil {
+			t.Errorf("%s: inspection %+v", test.name, inspection)
+		}
+	}
+
+	// counts that fit are decoded
+	datagram, err := Decode(testCounterDatagram(
+		testCounterRecord(SFlowTypeSFPCounters, 1, 1, 3300, 40, 1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+		testCounterRecord(SFlowTypeHostAdaptersCounters, 1, 3, 2, 0x02000000, 0x00010000, 0x02000000, 0x00020000),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datagram.CounterSamples) != 1 || len(datagram.CounterSamples[0].Records) != 2 {
+		t.Fatalf("samples %+v", datagram.CounterSamples)
+	}
+	records := datagram.CounterSamples[0].Records
+	if sfp := records[0].(SFlowSFPCounters); len(sfp.Lanes) != 1 || sfp.Lanes[0].TxPower != 2 {
+		t.Errorf("sfp %+v", sfp)
+	}
+	adapters := records[1].(SFlowHostAdaptersCounters)
+	if len(adapters.Adapters) != 1 || len(adapters.Adapters[0].MacAddresses) != 2 || adapters.Adapters[0].MacAddresses[1].String() != "02:00:00:00:00:02" {
+		t.Errorf("adapters %+v", adapters)
+	}
+}
+
+func TestDecodeDatagramHeader(t *testing.T) {
+	record := testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2)
+	ipv6 := append(xdr(5, 2, 0x20010db8, 0, 0, 1, 0, 1, 1000, 1, uint32(SFlowTypeFlowSample)), testDatagram(record)[32:]...)
+	tests := []struct {
+		name     string
+		datagram []byte
+		err      string
+	}{
+		{"version 4", append(xdr(4), testDatagram(record)[4:]...), "unsupported sFlow version 4"},
+		{"unknown address type", append(xdr(5, 3), testDatagram(record)[8:]...), "unknown agent address type 3"},
+		{"no address type", append(xdr(5, 0), testDatagram(record)[8:]...), "unknown agent address type 0"},
+		{"ipv6 agent", ipv6, ""},
+	}
+	for _, test := range tests {
+		datagram, err := Decode(test.datagram)
+		inspection := Inspect(test.datagram)
+		if test.err == "" {
+			if err != nil || len(datagram.FlowSamples) != 1 || datagram.AgentAddress.String() != "2001:db8::1" || inspection.Err != nil {
+				t.Errorf("%s: %+v %v, inspection error %v", test.name, datagram, err, inspection.Err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != test.err || datagram != nil {
+			t.Errorf("%s: %+v %v, want %q", test.name, datagram, err, test.err)
+		}
+		if inspection.Err == nil || inspection.Err.Error() != test.err || len(inspection.Samples) != 0 {
+			t.Errorf("%s: inspection %+v, want %q", test.name, inspection, test.err)
+		}
+	}
+}
+
+func TestDecodeArrays(t *testing.T) {
+	datagram, err := Decode(testDatagram(
+		testRecord(SFlowTypeExtendedMlpsFlow, 1, 0x0a000001, 2, 16, 17, 1, 18),
+		testRecord(SFlowTypeExtendedVlanFlow, 2, 0x8100000a, 0x88a80014),
+		testRecord(SFlowTypeExtendedGatewayFlow, 1, 0x0a000001, 1, 2, 3, 1, 2, 2, 65001, 65002, 1, 100, 200),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datagram.FlowSamples) != 1 || len(datagram.FlowSamples[0].Records) != 3 {
+		t.Fatalf("samples %+v", datagram.FlowSamples)
+	}
+	records := datagram.FlowSamples[0].Records
+	mpls := records[0].(SFlowExtendedMPLSFlowRecord)
+	if len(mpls.InLabelStack) != 2 || mpls.InLabelStack[1] != 17 || len(mpls.OutLabelStack) != 1 || mpls.OutLabelStack[0] != 18 {
+		t.Errorf("mpls %+v", mpls)
+	}
+	vlan := records[1].(SFlowExtendedVLANTunnelFlowRecord)
+	if len(vlan.Stack) != 2 || vlan.Stack[1] != 0x88a80014 {
+		t.Errorf("vlan tunnel %+v", vlan)
+	}
+	gateway := records[2].(SFlowExtendedGatewayFlowRecord)
+	if len(gateway.ASPath) != 1 || len(gateway.ASPath[0].Members) != 2 || gateway.ASPath[0].Members[1] != 65002 ||
+		len(gateway.Communities) != 1 || gateway.Communities[0] != 100 || gateway.LocalPref != 200 {
+		t.Errorf("gateway %+v", gateway)
+	}
+}
+
+// The compact data source is an 8 bit type and a 24 bit index, the
+// expanded one a word each
+func TestDecodeDataSource(t *testing.T) {
+	flow := xdr(1, 0x03000005, 1024, 2048, 0, 10, 11, 0)
+	counter := xdr(2, 0x00FFFFFF, 0)
+	payload := xdr(5, 1, 0xc0000201, 0, 1, 1000, 2)
+	payload = append(payload, xdr(uint32(SFlowTypeFlowSample), uint32(len(flow)))...)
+	payload = append(payload, flow...)
+	payload = append(payload, xdr(uint32(SFlowTypeCounterSample), uint32(len(counter)))...)
+	payload = append(payload, counter...)
+
+	datagram, err := Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datagram.FlowSamples) != 1 || len(datagram.CounterSamples) != 1 {
+		t.Fatalf("datagram %+v", datagram)
+	}
+	// a virtual machine of hsflowd
+	if s := datagram.FlowSamples[0]; s.SourceIDClass != 3 || s.SourceIDIndex != 5 {
+		t.Errorf("flow sample source %d:%d, want 3:5", s.SourceIDClass, s.SourceIDIndex)
+	}
+	if s := datagram.CounterSamples[0]; s.SourceIDClass != 0 || s.SourceIDIndex != 0xFFFFFF {
+		t.Errorf("counter sample source %d:%d, want 0:16777215", s.SourceIDClass, s.SourceIDIndex)
+	}
+	// the encoder writes the same words back
+	encoded, err := Encode(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, payload) {
+		t.Fatalf("encoded\n%x\nwant\n%x", encoded, payload)
+	}
+
+	expanded := append(xdr(uint32(SFlowTypeExpandedCounterSample), 16), xdr(3, 3, 0x01000001, 0)...)
+	s, err := decodeCounterSample(&expanded, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.SourceIDClass != 3 || s.SourceIDIndex != 0x01000001 {
+		t.Errorf("expanded counter sample source %d:%d, want 3:16777217", s.SourceIDClass, s.SourceIDIndex)
+	}
+}
+
+// testSerialize serializes the layers of a sampled header
+func testSerialize(t *testing.T, serialized ...gopacket.SerializableLayer) []byte {
+	t.Helper()
+	buffer := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{FixLengths: true}, serialized...); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestDecodeRawHeader(t *testing.T) {
+	ipv4 := testSerialize(t,
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: testIPv4, DstIP: net.IP{10, 0, 0, 2}},
+		&layers.UDP{SrcPort: 40000, DstPort: 4000}, gopacket.Payload("query"))
+	ipv6 := testSerialize(t,
+		&layers.IPv6{Version: 6, HopLimit: 64, NextHeader: layers.IPProtocolUDP, SrcIP: testIPv6, DstIP: net.ParseIP("2001:db8::2")},
+		&layers.UDP{SrcPort: 40000, DstPort: 4000}, gopacket.Payload("query"))
+	// a lone Ethernet layer is padded to the minimum frame
+	ethernet := append(append(append(append([]byte{}, testMac...), testMac...), 0x08, 0x00), ipv4...)
+	// LLC and SNAP header of an IPv4 packet
+	snap := []byte{0xaa, 0xaa, 0x03, 0, 0, 0, 0x08, 0x00}
+	fddi := append(append([]byte{0x50, 2, 0, 0, 0, 0, 1, 2, 0, 0, 0, 0, 2}, snap...), ipv4...)
+	dot11 := append(append(testSerialize(t, &layers.Dot11{Type: layers.Dot11TypeData, Address1: testMac, Address2: testMac, Address3: testMac}), snap...), ipv4...)
+	ppp := append([]byte{0x00, 0x21}, ipv4...)
+	// label 100, bottom of the stack, TTL 64
+	mpls := append([]byte{0x00, 0x06, 0x41, 0x40}, ipv4...)
+
+	tests := []struct {
+		protocol SFlowRawHeaderProtocol
+		header   []byte
+		first    gopacket.LayerType
+		network  gopacket.LayerType
+	}{
+		{SFlowProtoEthernet, ethernet, layers.LayerTypeEthernet, layers.LayerTypeIPv4},
+		{SFlowProtoFDDI, fddi, layers.LayerTypeFDDI, layers.LayerTypeIPv4},
+		{SFlowProtoPPP, ppp, layers.LayerTypePPP, layers.LayerTypeIPv4},
+		// PPP in HDLC-like framing
+		{SFlowProtoPOS, append([]byte{0xff, 0x03}, ppp...), layers.LayerTypePPP, layers.LayerTypeIPv4},
+		{SFlowProtoIPv4, ipv4, layers.LayerTypeIPv4, layers.LayerTypeIPv4},
+		{SFlowProtoIPv6, ipv6, layers.LayerTypeIPv6, layers.LayerTypeIPv6},
+		{SFlowProtoMPLS, mpls, layers.LayerTypeMPLS, layers.LayerTypeIPv4},
+		{SFlowProto80211MAC, dot11, layers.LayerTypeDot11, layers.LayerTypeIPv4},
+	}
+	for _, test := range tests {
+		parsed := DecodeRawHeader(test.protocol, test.header)
+		if !parsed.Supported || parsed.Protocol != test.protocol || parsed.Packet == nil || string(parsed.Data) != string(test.header) {
+			t.Errorf("%s: %+v", test.protocol, parsed)
+			continue
+		}
+		if failure := parsed.Packet.ErrorLayer(); failure != nil {
+			t.Errorf("%s: %v", test.protocol, failure.Error())
+		}
+		if first := parsed.Packet.Layers()[0].LayerType(); first != test.first {
+			t.Errorf("%s: first layer %s, want %s", test.protocol, first, test.first)
+		}
+		if parsed.Packet.Layer(test.network) == nil || parsed.Packet.Layer(layers.LayerTypeUDP) == nil {
+			t.Errorf("%s: layers %v", test.protocol, parsed.Packet.Layers())
+		}
+	}
+}
+
+func TestUnsupportedHeaders(t *testing.T) {
+	before := UnsupportedHeaders.Counts()
+	for _, protocol := range []SFlowRawHeaderProtocol{SFlowProtoX25, SFlowProtoAAL5, SFlowProtoAAL5, SFlowRawHeaderProtocol(99)} {
+		parsed := DecodeRawHeader(protocol, []byte{1, 2, 3, 4})
+		if parsed.Supported || parsed.Packet != nil || len(parsed.Data) != 4 || parsed.Ethernet() != nil {
+			t.Errorf("%s: %+v", protocol, parsed)
+		}
+	}
+	// the supported headers are not counted
+	DecodeRawHeader(SFlowProtoIPv4, []byte{1, 2, 3, 4})
+
+	after := UnsupportedHeaders.Counts()
+	for name, added := range map[string]uint64{"X25(6)": 1, "AAL5(9)": 2, "UNKNOWN(99)": 1} {
+		if after[name]-before[name] != added {
+			t.Errorf("%s counted %d times, want %d", name, after[name]-before[name], added)
+		}
+	}
+	if after["IPv4(11)"] != before["IPv4(11)"] {
+		t.Errorf("IPv4 headers counted")
+	}
+}
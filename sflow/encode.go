@@ -0,0 +1,936 @@
+package sflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Datagram Encoding
+// ****************************************************************************************************
+
+// Encode serializes a datagram to the sFlow v5 wire format, the inverse of
+// Decode. The sample count, the sample and record lengths and counts and
+// the XDR padding are computed from the content, the fields holding them
+// are ignored. The samples keep the order of SampleOrder, so
+// encode(decode(x)) == x for the datagrams Decode keeps all the samples
+// of.
+func Encode(datagram *Datagram) ([]byte, error) {
+	e := &encoder{}
+	if err := e.datagram(datagram); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// SerializeTo implements gopacket.SerializableLayer, a datagram can be
+// serialized with its UDP / IP headers by gopacket.SerializeLayers
+func (d *Datagram) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
+	data, err := Encode(d)
+	if err != nil {
+		return err
+	}
+	bytes, err := b.PrependBytes(len(data))
+	if err != nil {
+		return err
+	}
+	copy(bytes, data)
+	return nil
+}
+
+// EncodeFlowSample serializes a flow sample (or an expanded flow sample
+// when its Format says so) with its records
+func EncodeFlowSample(s SFlowFlowSample) ([]byte, error) {
+	e := &encoder{}
+	err := e.flowSample(s)
+	return e.buf, err
+}
+
+// EncodeCounterSample serializes a counter sample (or an expanded counter
+// sample when its Format says so) with its records
+func EncodeCounterSample(s SFlowCounterSample) ([]byte, error) {
+	e := &encoder{}
+	err := e.counterSample(s)
+	return e.buf, err
+}
+
+// EncodeDiscardSample serializes a discard sample with its records
+func EncodeDiscardSample(s SFlowDiscardSample) ([]byte, error) {
+	e := &encoder{}
+	err := e.discardSample(s)
+	return e.buf, err
+}
+
+// EncodeRecord serializes a flow or counter record with its data format
+// and length
+func EncodeRecord(record SFlowRecord) ([]byte, error) {
+	e := &encoder{}
+	err := e.record(record)
+	return e.buf, err
+}
+
+// encoder appends XDR encoded values to buf
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint32(value uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], value)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) uint64(value uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], value)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) float32(value float32) {
+	e.uint32(math.Float32bits(value))
+}
+
+// pad appends the zero bytes aligning length bytes of data to 4 bytes
+func (e *encoder) pad(length int) {
+	e.buf = append(e.buf, make([]byte, (4-length%4)%4)...)
+}
+
+// fixed appends XDR fixed length opaque data of size bytes, data is
+// truncated or zero filled to size
+func (e *encoder) fixed(data []byte, size int) {
+	start := len(e.buf)
+	e.buf = append(e.buf, make([]byte, size)...)
+	copy(e.buf[start:], data)
+	e.pad(size)
+}
+
+// opaque appends XDR variable length opaque data: its length followed by
+// the bytes padded to a multiple of 4
+func (e *encoder) opaque(data []byte) {
+	e.uint32(uint32(len(data)))
+	e.buf = append(e.buf, data...)
+	e.pad(len(data))
+}
+
+// string appends an XDR string
+func (e *encoder) string(value string) {
+	e.opaque([]byte(value))
+}
+
+// mac appends a MAC address as XDR opaque<6>, padded to 8 bytes
+func (e *encoder) mac(mac net.HardwareAddr) {
+	e.fixed(mac, 6)
+}
+
+// address appends an sFlow address: its type followed by the 4 or 16
+// bytes of the address. IPv4 mapped IPv6 addresses are written as IPv4,
+// an empty address (as decoded from the unknown type) or one of another
+// length as the unknown type.
+func (e *encoder) address(ip net.IP) {
+	switch {
+	case ip.To4() != nil:
+		e.uint32(uint32(layers.SFlowIPv4))
+		e.fixed(ip.To4(), net.IPv4len)
+	case ip.To16() != nil:
+		e.uint32(uint32(layers.SFlowIPv6))
+		e.fixed(ip.To16(), net.IPv6len)
+	default:
+		// unknown address type, without address
+		e.uint32(0)
+	}
+}
+
+// uint32Array appends an XDR variable length array of unsigned ints
+func (e *encoder) uint32Array(values []uint32) {
+	e.uint32(uint32(len(values)))
+	for _, value := range values {
+		e.uint32(value)
+	}
+}
+
+// length appends a placeholder for the length of the data that follows,
+// end sets it
+func (e *encoder) length() int {
+	e.uint32(0)
+	return len(e.buf)
+}
+
+func (e *encoder) end(start int) {
+	binary.BigEndian.PutUint32(e.buf[start-4:], uint32(len(e.buf)-start))
+}
+
+func (e *encoder) datagram(d *Datagram) error {
+	e.uint32(d.DatagramVersion)
+	e.address(d.AgentAddress)
+	e.uint32(d.SubAgentID)
+	e.uint32(d.SequenceNumber)
+	e.uint32(d.AgentUptime)
+	e.uint32(uint32(len(d.FlowSamples) + len(d.CounterSamples) + len(d.DiscardSamples)))
+	return d.EachSample(e.flowSample, e.counterSample, e.discardSample)
+}
+
+// sampleFormat returns the data format of a sample, standard for samples
+// built without a Format
+func sampleFormat(enterprise SFlowEnterpriseID, format SFlowSampleType, standard SFlowSampleType) uint32 {
+	if format == 0 {
+		format = standard
+	}
+	return uint32(enterprise)<<12 | uint32(format)
+}
+
+// dataSource returns the compact data source of the non expanded samples:
+// the source class in the high byte and the index in the 24 bits below
+func dataSource(class SFlowSourceFormat, index SFlowSourceValue) uint32 {
+	return uint32(class)<<24 | uint32(index)&0x00FFFFFF
+}
+
+// ****************************************************************************************************
+//  Sample Encoding
+// ****************************************************************************************************
+
+func (e *encoder) flowSample(s SFlowFlowSample) error {
+	expanded := s.Format == SFlowTypeExpandedFlowSample
+	e.uint32(sampleFormat(s.EnterpriseID, s.Format, SFlowTypeFlowSample))
+	length := e.length()
+	e.uint32(s.SequenceNumber)
+	if expanded {
+		e.uint32(uint32(s.SourceIDClass))
+		e.uint32(uint32(s.SourceIDIndex))
+	} else {
+		e.uint32(dataSource(s.SourceIDClass, s.SourceIDIndex))
+	}
+	e.uint32(s.SamplingRate)
+	e.uint32(s.SamplePool)
+	e.uint32(s.Dropped)
+	if expanded {
+		e.uint32(s.InputInterfaceFormat)
+		e.uint32(s.InputInterface)
+		e.uint32(s.OutputInterfaceFormat)
+		e.uint32(s.OutputInterface)
+	} else {
+		e.uint32(s.InputInterface)
+		e.uint32(s.OutputInterface)
+	}
+	if err := e.records(s.Records); err != nil {
+		return err
+	}
+	e.end(length)
+	return nil
+}
+
+func (e *encoder) counterSample(s SFlowCounterSample) error {
+	e.uint32(sampleFormat(s.EnterpriseID, s.Format, SFlowTypeCounterSample))
+	length := e.length()
+	e.uint32(s.SequenceNumber)
+	if s.Format == SFlowTypeExpandedCounterSample {
+		e.uint32(uint32(s.SourceIDClass))
+		e.uint32(uint32(s.SourceIDIndex))
+	} else {
+		e.uint32(dataSource(s.SourceIDClass, s.SourceIDIndex))
+	}
+	if err := e.records(s.Records); err != nil {
+		return err
+	}
+	e.end(length)
+	return nil
+}
+
+func (e *encoder) discardSample(s SFlowDiscardSample) error {
+	e.uint32(sampleFormat(s.EnterpriseID, s.Format, SFlowTypeDiscardSample))
+	length := e.length()
+	e.uint32(s.SequenceNumber)
+	e.uint32(uint32(s.SourceIDClass))
+	e.uint32(uint32(s.SourceIDIndex))
+	e.uint32(s.Drops)
+	e.uint32(s.InputInterface)
+	e.uint32(s.OutputInterface)
+	e.uint32(uint32(s.Reason))
+	if err := e.records(s.Records); err != nil {
+		return err
+	}
+	e.end(length)
+	return nil
+}
+
+// records appends the record count and the records
+func (e *encoder) records(records SFlowRecords) error {
+	e.uint32(uint32(len(records)))
+	for _, record := range records {
+		if err := e.record(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ****************************************************************************************************
+//  Record Encoding
+// ****************************************************************************************************
+
+// hostCPUCountersV1Length is the length of the host CPU counters sent
+// before the steal / guest times were appended
+const hostCPUCountersV1Length = 68
+
+func (e *encoder) record(record SFlowRecord) error {
+	start := len(e.buf)
+	e.uint32(uint32(record.RecordEnterprise())<<12 | record.RecordFormat())
+	length := e.length()
+
+	switch r := record.(type) {
+	case SFlowRawPacketFlowRecord:
+		header := r.ParsedHeader.Data
+		if header == nil && r.Header != nil {
+			header = r.Header.Data()
+		}
+		e.uint32(uint32(r.HeaderProtocol))
+		e.uint32(r.FrameLength)
+		e.uint32(r.PayloadRemoved)
+		e.opaque(header)
+	case SFlowExtendedUserFlow:
+		e.uint32(uint32(r.SourceCharSet))
+		e.string(r.SourceUserID)
+		e.uint32(uint32(r.DestinationCharSet))
+		e.string(r.DestinationUserID)
+	case SFlowExtendedURLRecord:
+		e.uint32(uint32(r.Direction))
+		e.string(r.URL)
+		e.string(r.Host)
+	case SFlowExtendedSwitchFlowRecord:
+		e.uint32(r.IncomingVLAN)
+		e.uint32(r.IncomingVLANPriority)
+		e.uint32(r.OutgoingVLAN)
+		e.uint32(r.OutgoingVLANPriority)
+	case SFlowExtendedRouterFlowRecord:
+		e.address(r.NextHop)
+		e.uint32(r.NextHopSourceMask)
+		e.uint32(r.NextHopDestinationMask)
+	case SFlowExtendedGatewayFlowRecord:
+		e.address(r.NextHop)
+		e.uint32(r.AS)
+		e.uint32(r.SourceAS)
+		e.uint32(r.PeerAS)
+		e.uint32(uint32(len(r.ASPath)))
+		for _, asPath := range r.ASPath {
+			e.uint32(uint32(asPath.Type))
+			e.uint32Array(asPath.Members)
+		}
+		e.uint32Array(r.Communities)
+		e.uint32(r.LocalPref)
+	case SFlowEthernetFrameFlowRecord:
+		e.uint32(r.FrameLength)
+		e.mac(r.SrcMac)
+		e.mac(r.DstMac)
+		e.uint32(r.Type)
+	case SFlowIpv4FlowRecord:
+		e.ipv4Record(r.SFlowIpv4Record)
+	case SFlowIpv6FlowRecord:
+		e.ipv6Record(r.SFlowIpv6Record)
+	case SFlowExtendedIpv4TunnelEgressRecord:
+		e.ipv4Record(r.SFlowIpv4Record)
+	case SFlowExtendedIpv4TunnelIngressRecord:
+		e.ipv4Record(r.SFlowIpv4Record)
+	case SFlowExtendedIpv6TunnelEgressRecord:
+		e.ipv6Record(r.SFlowIpv6Record)
+	case SFlowExtendedIpv6TunnelIngressRecord:
+		e.ipv6Record(r.SFlowIpv6Record)
+	case SFlowExtendedDecapsulateEgressRecord:
+		e.uint32(r.InnerHeaderOffset)
+	case SFlowExtendedDecapsulateIngressRecord:
+		e.uint32(r.InnerHeaderOffset)
+	case SFlowExtendedVniEgressRecord:
+		e.uint32(r.VNI)
+	case SFlowExtendedVniIngressRecord:
+		e.uint32(r.VNI)
+	case SFlowExtendedEgressQueueRecord:
+		e.uint32(r.Queue)
+	case SFlowExtendedTransitRecord:
+		e.uint32(r.Delay)
+	case SFlowExtendedQueueRecord:
+		e.uint32(r.Depth)
+	case SFlowExtendedFunctionFlowRecord:
+		e.string(r.Symbol)
+	case SFlowExtendedMPLSFlowRecord:
+		e.address(r.NextHop)
+		e.uint32Array(r.InLabelStack)
+		e.uint32Array(r.OutLabelStack)
+	case SFlowExtendedNATFlowRecord:
+		e.address(r.SourceAddress)
+		e.address(r.DestinationAddress)
+	case SFlowExtendedMPLSTunnelFlowRecord:
+		e.string(r.TunnelLSPName)
+		e.uint32(r.TunnelID)
+		e.uint32(r.TunnelCOS)
+	case SFlowExtendedMPLSVCFlowRecord:
+		e.string(r.VCInstanceName)
+		e.uint32(r.VLLVCID)
+		e.uint32(r.VCLabelCOS)
+	case SFlowExtendedMPLSFECFlowRecord:
+		e.string(r.FTNDescr)
+		e.uint32(r.FTNMask)
+	case SFlowExtendedMPLSLVPFECFlowRecord:
+		e.uint32(r.FECAddrPrefixLength)
+	case SFlowExtendedVLANTunnelFlowRecord:
+		e.uint32(uint32(len(r.Stack)))
+		for _, tag := range r.Stack {
+			e.uint32(uint32(tag))
+		}
+	case SFlowExtended80211PayloadFlowRecord:
+		e.uint32(r.CipherSuite)
+		e.opaque(r.Data)
+	case SFlowExtended80211RxFlowRecord:
+		e.string(r.SSID)
+		e.mac(r.BSSID)
+		e.uint32(uint32(r.Version))
+		e.uint32(r.Channel)
+		e.uint64(r.Speed)
+		e.uint32(r.RSNI)
+		e.uint32(r.RCPI)
+		e.uint32(r.PacketDuration)
+	case SFlowExtended80211TxFlowRecord:
+		e.string(r.SSID)
+		e.mac(r.BSSID)
+		e.uint32(uint32(r.Version))
+		e.uint32(r.Transmissions)
+		e.uint32(r.PacketDuration)
+		e.uint32(r.RetransDuration)
+		e.uint32(r.Channel)
+		e.uint64(r.Speed)
+		e.uint32(r.Power)
+	case SFlowExtended80211AggregationFlowRecord:
+		e.uint32(uint32(len(r.PDUs)))
+		for _, pdu := range r.PDUs {
+			if err := e.records(pdu.Records); err != nil {
+				return err
+			}
+		}
+	case SFlowExtendedSocketFlowRecord:
+		addressLength := net.IPv4len
+		if r.RecordFormat() == uint32(SFlowTypeExtendedSocketIpv6Flow) {
+			addressLength = net.IPv6len
+		}
+		e.uint32(r.Protocol)
+		e.fixed(socketAddress(r.LocalIP, addressLength), addressLength)
+		e.fixed(socketAddress(r.RemoteIP, addressLength), addressLength)
+		e.uint32(r.LocalPort)
+		e.uint32(r.RemotePort)
+	case SFlowExtendedTCPInfoFlowRecord:
+		e.uint32(uint32(r.Direction))
+		e.uint32(r.SndMSS)
+		e.uint32(r.RcvMSS)
+		e.uint32(r.Unacked)
+		e.uint32(r.Lost)
+		e.uint32(r.Retrans)
+		e.uint32(r.PMTU)
+		e.uint32(r.RTT)
+		e.uint32(r.RTTVar)
+		e.uint32(r.SndCwnd)
+		e.uint32(r.Reordering)
+		e.uint32(r.MinRTT)
+	case SFlowMemcacheFlowRecord:
+		e.uint32(r.Protocol)
+		e.uint32(r.Command)
+		e.string(r.Key)
+		e.uint32(r.NumKeys)
+		e.uint32(r.ValueBytes)
+		e.uint32(r.Duration)
+		e.uint32(uint32(r.Status))
+	case SFlowHTTPRequestFlowRecord:
+		e.uint32(uint32(r.Method))
+		e.uint32(r.Protocol)
+		e.string(r.URI)
+		e.string(r.Host)
+		e.string(r.Referer)
+		e.string(r.UserAgent)
+		if r.RecordFormat() == uint32(SFlowTypeHTTP2Flow) {
+			e.string(r.XFF)
+		}
+		e.string(r.AuthUser)
+		e.string(r.MimeType)
+		e.uint64(r.RequestBytes)
+		e.uint64(r.ResponseBytes)
+		e.uint32(r.Duration)
+		e.uint32(uint32(r.Status))
+	case SFlowAppOperationFlowRecord:
+		e.appContext(r.Context)
+		e.string(r.StatusDescr)
+		e.uint64(r.RequestBytes)
+		e.uint64(r.ResponseBytes)
+		e.uint32(r.Duration)
+		e.uint32(uint32(r.Status))
+	case SFlowAppParentContextFlowRecord:
+		e.appContext(r.Context)
+	case SFlowAppActorFlowRecord:
+		e.string(r.Actor)
+
+	case SFlowGenericInterfaceCounters:
+		e.uint32(r.IfIndex)
+		e.uint32(r.IfType)
+		e.uint64(r.IfSpeed)
+		e.uint32(r.IfDirection)
+		e.uint32(r.IfStatus)
+		e.uint64(r.IfInOctets)
+		e.uint32(r.IfInUcastPkts)
+		e.uint32(r.IfInMulticastPkts)
+		e.uint32(r.IfInBroadcastPkts)
+		e.uint32(r.IfInDiscards)
+		e.uint32(r.IfInErrors)
+		e.uint32(r.IfInUnknownProtos)
+		e.uint64(r.IfOutOctets)
+		e.uint32(r.IfOutUcastPkts)
+		e.uint32(r.IfOutMulticastPkts)
+		e.uint32(r.IfOutBroadcastPkts)
+		e.uint32(r.IfOutDiscards)
+		e.uint32(r.IfOutErrors)
+		e.uint32(r.IfPromiscuousMode)
+	case SFlowEthernetCounters:
+		e.uint32(r.AlignmentErrors)
+		e.uint32(r.FCSErrors)
+		e.uint32(r.SingleCollisionFrames)
+		e.uint32(r.MultipleCollisionFrames)
+		e.uint32(r.SQETestErrors)
+		e.uint32(r.DeferredTransmissions)
+		e.uint32(r.LateCollisions)
+		e.uint32(r.ExcessiveCollisions)
+		e.uint32(r.InternalMacTransmitErrors)
+		e.uint32(r.CarrierSenseErrors)
+		e.uint32(r.FrameTooLongs)
+		e.uint32(r.InternalMacReceiveErrors)
+		e.uint32(r.SymbolErrors)
+	case SFlowVLANCounters:
+		e.uint32(r.VlanID)
+		e.uint64(r.Octets)
+		e.uint32(r.UcastPkts)
+		e.uint32(r.MulticastPkts)
+		e.uint32(r.BroadcastPkts)
+		e.uint32(r.Discards)
+	case SFlow80211Counters:
+		e.uint32(r.TransmittedFragmentCount)
+		e.uint32(r.MulticastTransmittedFrameCount)
+		e.uint32(r.FailedCount)
+		e.uint32(r.RetryCount)
+		e.uint32(r.MultipleRetryCount)
+		e.uint32(r.FrameDuplicateCount)
+		e.uint32(r.RTSSuccessCount)
+		e.uint32(r.RTSFailureCount)
+		e.uint32(r.ACKFailureCount)
+		e.uint32(r.ReceivedFragmentCount)
+		e.uint32(r.MulticastReceivedFrameCount)
+		e.uint32(r.FCSErrorCount)
+		e.uint32(r.TransmittedFrameCount)
+		e.uint32(r.WEPUndecryptableCount)
+		e.uint32(r.QoSDiscardedFragmentCount)
+		e.uint32(r.AssociatedStationCount)
+		e.uint32(r.QoSCFPollsReceivedCount)
+		e.uint32(r.QoSCFPollsUnusedCount)
+		e.uint32(r.QoSCFPollsUnusableCount)
+		e.uint32(r.QoSCFPollsLostCount)
+	case SFlowLACPCounters:
+		e.mac(r.ActorSystemID)
+		e.mac(r.PartnerSystemID)
+		e.uint32(r.AttachedAggID)
+		e.buf = append(e.buf, byte(r.ActorAdminState), byte(r.ActorOperState), byte(r.PartnerAdminState), byte(r.PartnerOperState))
+		e.uint32(r.LACPDUsRx)
+		e.uint32(r.MarkerPDUsRx)
+		e.uint32(r.MarkerResponsePDUsRx)
+		e.uint32(r.UnknownRx)
+		e.uint32(r.IllegalRx)
+		e.uint32(r.LACPDUsTx)
+		e.uint32(r.MarkerPDUsTx)
+		e.uint32(r.MarkerResponsePDUsTx)
+	case SFlowSFPCounters:
+		e.uint32(r.ModuleID)
+		e.uint32(r.ModuleTotalLanes)
+		e.uint32(r.ModuleSupplyVolts)
+		e.uint32(uint32(r.ModuleTemperature))
+		e.uint32(uint32(len(r.Lanes)))
+		for _, lane := range r.Lanes {
+			e.uint32(lane.LaneIndex)
+			e.uint32(lane.TxBiasCurrent)
+			e.uint32(lane.TxPower)
+			e.uint32(lane.TxPowerMin)
+			e.uint32(lane.TxPowerMax)
+			e.uint32(lane.TxWavelength)
+			e.uint32(lane.RxPower)
+			e.uint32(lane.RxPowerMin)
+			e.uint32(lane.RxPowerMax)
+			e.uint32(lane.RxWavelength)
+		}
+	case SFlowProcessorCounters:
+		e.uint32(r.FiveSecCpu)
+		e.uint32(r.OneMinCpu)
+		e.uint32(r.FiveMinCpu)
+		e.uint64(r.TotalMemory)
+		e.uint64(r.FreeMemory)
+	case SFlowRadioCounters:
+		e.uint32(r.ElapsedTime)
+		e.uint32(r.OnChannelTime)
+		e.uint32(r.OnChannelBusyTime)
+	case SFlowOFPortCounters:
+		e.fixed(r.OfDataPathId, 8)
+		e.uint32(r.OfPort)
+	case SFlowOFPortNameCounters:
+		e.string(r.OfPortName)
+	case SFlowHostDescrCounters:
+		e.string(r.Hostname)
+		e.fixed(r.UUID, 16)
+		e.uint32(uint32(r.MachineType))
+		e.uint32(uint32(r.OSName))
+		e.string(r.OSRelease)
+	case SFlowHostAdaptersCounters:
+		e.uint32(uint32(len(r.Adapters)))
+		for _, adapter := range r.Adapters {
+			e.uint32(adapter.IfIndex)
+			e.uint32(uint32(len(adapter.MacAddresses)))
+			for _, mac := range adapter.MacAddresses {
+				e.mac(mac)
+			}
+		}
+	case SFlowHostParentCounters:
+		e.uint32(uint32(r.ContainerType))
+		e.uint32(r.ContainerIndex)
+	case SFlowHostCPUCounters:
+		e.float32(r.LoadOne)
+		e.float32(r.LoadFive)
+		e.float32(r.LoadFifteen)
+		e.uint32(r.ProcRun)
+		e.uint32(r.ProcTotal)
+		e.uint32(r.CPUNum)
+		e.uint32(r.CPUSpeed)
+		e.uint32(r.Uptime)
+		e.uint32(r.CPUUser)
+		e.uint32(r.CPUNice)
+		e.uint32(r.CPUSystem)
+		e.uint32(r.CPUIdle)
+		e.uint32(r.CPUWio)
+		e.uint32(r.CPUIntr)
+		e.uint32(r.CPUSintr)
+		e.uint32(r.Interrupts)
+		e.uint32(r.Contexts)
+		// keep the short structure of the records decoded without them
+		if r.FlowDataLength != hostCPUCountersV1Length {
+			e.uint32(r.CPUSteal)
+			e.uint32(r.CPUGuest)
+			e.uint32(r.CPUGuestNice)
+		}
+	case SFlowHostMemoryCounters:
+		e.uint64(r.MemTotal)
+		e.uint64(r.MemFree)
+		e.uint64(r.MemShared)
+		e.uint64(r.MemBuffers)
+		e.uint64(r.MemCached)
+		e.uint64(r.SwapTotal)
+		e.uint64(r.SwapFree)
+		e.uint32(r.PageIn)
+		e.uint32(r.PageOut)
+		e.uint32(r.SwapIn)
+		e.uint32(r.SwapOut)
+	case SFlowHostDiskCounters:
+		e.uint64(r.DiskTotal)
+		e.uint64(r.DiskFree)
+		e.uint32(r.PartMaxUsed)
+		e.uint32(r.Reads)
+		e.uint64(r.BytesRead)
+		e.uint32(r.ReadTime)
+		e.uint32(r.Writes)
+		e.uint64(r.BytesWritten)
+		e.uint32(r.WriteTime)
+	case SFlowHostNetIOCounters:
+		e.uint64(r.BytesIn)
+		e.uint32(r.PacketsIn)
+		e.uint32(r.ErrorsIn)
+		e.uint32(r.DropsIn)
+		e.uint64(r.BytesOut)
+		e.uint32(r.PacketsOut)
+		e.uint32(r.ErrorsOut)
+		e.uint32(r.DropsOut)
+	case SFlowHostIPCounters:
+		e.uint32(r.IPForwarding)
+		e.uint32(r.IPDefaultTTL)
+		e.uint32(r.IPInReceives)
+		e.uint32(r.IPInHdrErrors)
+		e.uint32(r.IPInAddrErrors)
+		e.uint32(r.IPForwDatagrams)
+		e.uint32(r.IPInUnknownProtos)
+		e.uint32(r.IPInDiscards)
+		e.uint32(r.IPInDelivers)
+		e.uint32(r.IPOutRequests)
+		e.uint32(r.IPOutDiscards)
+		e.uint32(r.IPOutNoRoutes)
+		e.uint32(r.IPReasmTimeout)
+		e.uint32(r.IPReasmReqds)
+		e.uint32(r.IPReasmOKs)
+		e.uint32(r.IPReasmFails)
+		e.uint32(r.IPFragOKs)
+		e.uint32(r.IPFragFails)
+		e.uint32(r.IPFragCreates)
+	case SFlowHostICMPCounters:
+		e.uint32(r.ICMPInMsgs)
+		e.uint32(r.ICMPInErrors)
+		e.uint32(r.ICMPInDestUnreachs)
+		e.uint32(r.ICMPInTimeExcds)
+		e.uint32(r.ICMPInParamProbs)
+		e.uint32(r.ICMPInSrcQuenchs)
+		e.uint32(r.ICMPInRedirects)
+		e.uint32(r.ICMPInEchos)
+		e.uint32(r.ICMPInEchoReps)
+		e.uint32(r.ICMPInTimestamps)
+		e.uint32(r.ICMPInAddrMasks)
+		e.uint32(r.ICMPInAddrMaskReps)
+		e.uint32(r.ICMPOutMsgs)
+		e.uint32(r.ICMPOutErrors)
+		e.uint32(r.ICMPOutDestUnreachs)
+		e.uint32(r.ICMPOutTimeExcds)
+		e.uint32(r.ICMPOutParamProbs)
+		e.uint32(r.ICMPOutSrcQuenchs)
+		e.uint32(r.ICMPOutRedirects)
+		e.uint32(r.ICMPOutEchos)
+		e.uint32(r.ICMPOutEchoReps)
+		e.uint32(r.ICMPOutTimestamps)
+		e.uint32(r.ICMPOutTimestampReps)
+		e.uint32(r.ICMPOutAddrMasks)
+		e.uint32(r.ICMPOutAddrMaskReps)
+	case SFlowHostTCPCounters:
+		e.uint32(r.TCPRtoAlgorithm)
+		e.uint32(r.TCPRtoMin)
+		e.uint32(r.TCPRtoMax)
+		e.uint32(uint32(r.TCPMaxConn))
+		e.uint32(r.TCPActiveOpens)
+		e.uint32(r.TCPPassiveOpens)
+		e.uint32(r.TCPAttemptFails)
+		e.uint32(r.TCPEstabResets)
+		e.uint32(r.TCPCurrEstab)
+		e.uint32(r.TCPInSegs)
+		e.uint32(r.TCPOutSegs)
+		e.uint32(r.TCPRetransSegs)
+		e.uint32(r.TCPInErrs)
+		e.uint32(r.TCPOutRsts)
+		e.uint32(r.TCPInCsumErrors)
+	case SFlowHostUDPCounters:
+		e.uint32(r.UDPInDatagrams)
+		e.uint32(r.UDPNoPorts)
+		e.uint32(r.UDPInErrors)
+		e.uint32(r.UDPOutDatagrams)
+		e.uint32(r.UDPRcvbufErrors)
+		e.uint32(r.UDPSndbufErrors)
+		e.uint32(r.UDPInCsumErrors)
+	case SFlowVirtNodeCounters:
+		e.uint32(r.MHz)
+		e.uint32(r.CPUs)
+		e.uint64(r.Memory)
+		e.uint64(r.MemoryFree)
+		e.uint32(r.NumDomains)
+	case SFlowVirtCPUCounters:
+		e.uint32(r.State)
+		e.uint32(r.CPUTime)
+		e.uint32(r.NrVirtCPU)
+	case SFlowVirtMemoryCounters:
+		e.uint64(r.Memory)
+		e.uint64(r.MaxMemory)
+	case SFlowVirtDiskCounters:
+		e.uint64(r.Capacity)
+		e.uint64(r.Allocation)
+		e.uint64(r.Available)
+		e.uint32(r.ReadRequests)
+		e.uint64(r.BytesRead)
+		e.uint32(r.WriteRequests)
+		e.uint64(r.BytesWritten)
+		e.uint32(r.Errors)
+	case SFlowVirtNetIOCounters:
+		e.uint64(r.BytesIn)
+		e.uint32(r.PacketsIn)
+		e.uint32(r.ErrorsIn)
+		e.uint32(r.DropsIn)
+		e.uint64(r.BytesOut)
+		e.uint32(r.PacketsOut)
+		e.uint32(r.ErrorsOut)
+		e.uint32(r.DropsOut)
+	case SFlowMemcacheLegacyCounters:
+		e.uint32(r.Uptime)
+		e.uint32(r.RusageUser)
+		e.uint32(r.RusageSystem)
+		e.uint32(r.CurrConnections)
+		e.uint32(r.TotalConnections)
+		e.uint32(r.ConnectionStructures)
+		e.uint32(r.CmdGet)
+		e.uint32(r.CmdSet)
+		e.uint32(r.CmdFlush)
+		e.uint32(r.GetHits)
+		e.uint32(r.GetMisses)
+		e.uint32(r.DeleteMisses)
+		e.uint32(r.DeleteHits)
+		e.uint32(r.IncrMisses)
+		e.uint32(r.IncrHits)
+		e.uint32(r.DecrMisses)
+		e.uint32(r.DecrHits)
+		e.uint32(r.CasMisses)
+		e.uint32(r.CasHits)
+		e.uint32(r.CasBadval)
+		e.uint32(r.AuthCmds)
+		e.uint32(r.AuthErrors)
+		e.uint64(r.BytesRead)
+		e.uint64(r.BytesWritten)
+		e.uint32(r.LimitMaxbytes)
+		e.uint32(r.AcceptingConns)
+		e.uint32(r.ListenDisabledNum)
+		e.uint32(r.Threads)
+		e.uint32(r.ConnYields)
+		e.uint64(r.Bytes)
+		e.uint32(r.CurrItems)
+		e.uint32(r.TotalItems)
+		e.uint32(r.Evictions)
+	case SFlowHTTPCounters:
+		e.uint32(r.MethodOptionCount)
+		e.uint32(r.MethodGetCount)
+		e.uint32(r.MethodHeadCount)
+		e.uint32(r.MethodPostCount)
+		e.uint32(r.MethodPutCount)
+		e.uint32(r.MethodDeleteCount)
+		e.uint32(r.MethodTraceCount)
+		e.uint32(r.MethodConnectCount)
+		e.uint32(r.MethodOtherCount)
+		e.uint32(r.Status1XXCount)
+		e.uint32(r.Status2XXCount)
+		e.uint32(r.Status3XXCount)
+		e.uint32(r.Status4XXCount)
+		e.uint32(r.Status5XXCount)
+		e.uint32(r.StatusOtherCount)
+	case SFlowAppCounters:
+		e.string(r.Application)
+		e.uint32(r.StatusOK)
+		e.uint32(r.StatusOther)
+		e.uint32(r.StatusTimeout)
+		e.uint32(r.StatusInternalError)
+		e.uint32(r.StatusBadRequest)
+		e.uint32(r.StatusForbidden)
+		e.uint32(r.StatusTooLarge)
+		e.uint32(r.StatusNotImplemented)
+		e.uint32(r.StatusNotFound)
+		e.uint32(r.StatusUnavailable)
+		e.uint32(r.StatusUnauthorized)
+	case SFlowAppResourcesCounters:
+		e.uint32(r.UserTime)
+		e.uint32(r.SystemTime)
+		e.uint64(r.MemUsed)
+		e.uint64(r.MemMax)
+		e.uint32(r.FdOpen)
+		e.uint32(r.FdMax)
+		e.uint32(r.ConnOpen)
+		e.uint32(r.ConnMax)
+	case SFlowMemcacheCounters:
+		e.uint32(r.CmdSet)
+		e.uint32(r.CmdTouch)
+		e.uint32(r.CmdFlush)
+		e.uint32(r.GetHits)
+		e.uint32(r.GetMisses)
+		e.uint32(r.DeleteHits)
+		e.uint32(r.DeleteMisses)
+		e.uint32(r.IncrHits)
+		e.uint32(r.IncrMisses)
+		e.uint32(r.DecrHits)
+		e.uint32(r.DecrMisses)
+		e.uint32(r.CasHits)
+		e.uint32(r.CasMisses)
+		e.uint32(r.CasBadval)
+		e.uint32(r.AuthCmds)
+		e.uint32(r.AuthErrors)
+		e.uint32(r.Threads)
+		e.uint32(r.ConnYields)
+		e.uint32(r.ListenDisabledNum)
+		e.uint32(r.CurrConnections)
+		e.uint32(r.RejectedConnections)
+		e.uint32(r.TotalConnections)
+		e.uint32(r.ConnectionStructures)
+		e.uint32(r.Evictions)
+		e.uint32(r.Reclaimed)
+		e.uint32(r.CurrItems)
+		e.uint32(r.TotalItems)
+		e.uint64(r.BytesRead)
+		e.uint64(r.BytesWritten)
+		e.uint64(r.Bytes)
+		e.uint64(r.LimitMaxbytes)
+	case SFlowVDICounters:
+		e.uint32(r.SessionsCurrent)
+		e.uint32(r.SessionsTotal)
+		e.uint32(r.SessionsDuration)
+		e.uint32(r.RxBytes)
+		e.uint32(r.TxBytes)
+		e.uint32(r.RxPackets)
+		e.uint32(r.TxPackets)
+		e.uint32(r.RxPacketsLost)
+		e.uint32(r.TxPacketsLost)
+		e.uint32(r.RTTMinMs)
+		e.uint32(r.RTTMaxMs)
+		e.uint32(r.RTTAvgMs)
+		e.uint32(r.AudioRxBytes)
+		e.uint32(r.AudioTxBytes)
+		e.uint32(r.AudioTxLimit)
+		e.uint32(r.ImgRxBytes)
+		e.uint32(r.ImgTxBytes)
+		e.uint32(r.ImgFrames)
+		e.uint32(r.ImgQualMin)
+		e.uint32(r.ImgQualMax)
+		e.uint32(r.ImgQualAvg)
+		e.uint32(r.UsbRxBytes)
+		e.uint32(r.UsbTxBytes)
+	case SFlowAppWorkersCounters:
+		e.uint32(r.WorkersActive)
+		e.uint32(r.WorkersIdle)
+		e.uint32(r.WorkersMax)
+		e.uint32(r.RequestsDelayed)
+		e.uint32(r.RequestsDropped)
+	case SFlowOVSDPCounters:
+		e.uint32(r.Hits)
+		e.uint32(r.Misses)
+		e.uint32(r.Lost)
+		e.uint32(r.MaskHits)
+		e.uint32(r.Flows)
+		e.uint32(r.Masks)
+	default:
+		e.buf = e.buf[:start]
+		return fmt.Errorf("cannot encode %T records", record)
+	}
+
+	e.end(length)
+	return nil
+}
+
+func (e *encoder) ipv4Record(r SFlowIpv4Record) {
+	e.uint32(r.Length)
+	e.uint32(r.Protocol)
+	e.fixed(r.IPSrc.To4(), net.IPv4len)
+	e.fixed(r.IPDst.To4(), net.IPv4len)
+	e.uint32(r.PortSrc)
+	e.uint32(r.PortDst)
+	e.uint32(r.TCPFlags)
+	e.uint32(r.TOS)
+}
+
+func (e *encoder) ipv6Record(r SFlowIpv6Record) {
+	e.uint32(r.Length)
+	e.uint32(r.Protocol)
+	e.fixed(r.IPSrc.To16(), net.IPv6len)
+	e.fixed(r.IPDst.To16(), net.IPv6len)
+	e.uint32(r.PortSrc)
+	e.uint32(r.PortDst)
+	e.uint32(r.TCPFlags)
+	e.uint32(r.Priority)
+}
+
+func (e *encoder) appContext(context SFlowAppContext) {
+	e.string(context.Application)
+	e.string(context.Operation)
+	e.string(context.Attributes)
+}
+
+// socketAddress returns ip in the 4 or 16 bytes form of the socket record
+func socketAddress(ip net.IP, length int) net.IP {
+	if length == net.IPv4len {
+		return ip.To4()
+	}
+	return ip.To16()
+}
@@ -0,0 +1,336 @@
+package sflow
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testFill sets the numbers and strings of a record, recursing into its
+// structs but not into its base record, each field to a different value
+func testFill(v reflect.Value, next *uint64) {
+	for i := 0; i < v.NumField(); i++ {
+		field, structField := v.Field(i), v.Type().Field(i)
+		if structField.Anonymous && (structField.Type == reflect.TypeOf(SFlowBaseFlowRecord{}) || structField.Type == reflect.TypeOf(SFlowBaseCounterRecord{})) {
+			continue
+		}
+		*next++
+		switch field.Kind() {
+		case reflect.Uint8:
+			field.SetUint(*next % 256)
+		case reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(*next * 7)
+		case reflect.Int32:
+			field.SetInt(-int64(*next))
+		case reflect.Float32:
+			field.SetFloat(float64(*next) + 0.25)
+		case reflect.String:
+			// all the XDR paddings
+			field.SetString(strings.Repeat("s", int(*next%4)+1))
+		case reflect.Struct:
+			testFill(field, next)
+		}
+	}
+}
+
+// testFilled returns a copy of record with its numbers and strings set
+func testFilled(record SFlowRecord) SFlowRecord {
+	v := reflect.New(reflect.TypeOf(record)).Elem()
+	v.Set(reflect.ValueOf(record))
+	var next uint64
+	testFill(v, &next)
+	return v.Interface().(SFlowRecord)
+}
+
+var (
+	testMac  = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	testIPv4 = net.IP{10, 0, 0, 1}
+	testIPv6 = net.ParseIP("2001:db8::1")
+)
+
+// testFlowRecords has a record of every flow record type, and of both
+// versions of the types encoding two formats
+func testFlowRecords() []SFlowRecord {
+	return []SFlowRecord{
+		testFilled(SFlowRawPacketFlowRecord{ParsedHeader: SFlowRawHeader{Data: []byte{1, 2, 3, 4, 5, 6, 7}}}),
+		testFilled(SFlowExtendedUserFlow{}),
+		testFilled(SFlowExtendedURLRecord{}),
+		testFilled(SFlowExtendedSwitchFlowRecord{}),
+		testFilled(SFlowExtendedRouterFlowRecord{NextHop: testIPv6}),
+		testFilled(SFlowExtendedGatewayFlowRecord{
+			NextHop:     testIPv4,
+			ASPath:      []SFlowASDestination{{Type: 2, Count: 2, Members: []uint32{64512, 64513}}},
+			ASPathCount: 1,
+			Communities: []uint32{5, 6},
+		}),
+		testFilled(SFlowEthernetFrameFlowRecord{SrcMac: testMac, DstMac: testMac}),
+		SFlowIpv4FlowRecord{SFlowIpv4Record: SFlowIpv4Record{Length: 1, Protocol: 6, IPSrc: testIPv4, IPDst: testIPv4, PortSrc: 1, PortDst: 2, TCPFlags: 3, TOS: 4}},
+		SFlowIpv6FlowRecord{SFlowIpv6Record: SFlowIpv6Record{Length: 1, Protocol: 6, IPSrc: testIPv6, IPDst: testIPv6, PortSrc: 1, PortDst: 2, TCPFlags: 3, Priority: 4}},
+		SFlowExtendedIpv4TunnelEgressRecord{SFlowIpv4Record: SFlowIpv4Record{IPSrc: testIPv4, IPDst: testIPv4, TOS: 9}},
+		SFlowExtendedIpv4TunnelIngressRecord{SFlowIpv4Record: SFlowIpv4Record{IPSrc: testIPv4, IPDst: testIPv4, TOS: 9}},
+		SFlowExtendedIpv6TunnelEgressRecord{SFlowIpv6Record: SFlowIpv6Record{IPSrc: testIPv6, IPDst: testIPv6, Priority: 9}},
+		SFlowExtendedIpv6TunnelIngressRecord{SFlowIpv6Record: SFlowIpv6Record{IPSrc: testIPv6, IPDst: testIPv6, Priority: 9}},
+		testFilled(SFlowExtendedDecapsulateEgressRecord{}),
+		testFilled(SFlowExtendedDecapsulateIngressRecord{}),
+		testFilled(SFlowExtendedVniEgressRecord{}),
+		testFilled(SFlowExtendedVniIngressRecord{}),
+		testFilled(SFlowExtendedEgressQueueRecord{}),
+		testFilled(SFlowExtendedTransitRecord{}),
+		testFilled(SFlowExtendedQueueRecord{}),
+		testFilled(SFlowExtendedFunctionFlowRecord{}),
+		testFilled(SFlowExtendedMPLSFlowRecord{NextHop: testIPv4, InLabelStack: []uint32{1, 2}, OutLabelStack: []uint32{3}}),
+		testFilled(SFlowExtendedNATFlowRecord{SourceAddress: testIPv4, DestinationAddress: testIPv6}),
+		testFilled(SFlowExtendedMPLSTunnelFlowRecord{}),
+		testFilled(SFlowExtendedMPLSVCFlowRecord{}),
+		testFilled(SFlowExtendedMPLSFECFlowRecord{}),
+		testFilled(SFlowExtendedMPLSLVPFECFlowRecord{}),
+		SFlowExtendedVLANTunnelFlowRecord{Stack: []SFlowVLANTag{0x8100000a, 0x88a80014}},
+		testFilled(SFlowExtended80211PayloadFlowRecord{Data: []byte{9, 8, 7, 6, 5}}),
+		testFilled(SFlowExtended80211RxFlowRecord{BSSID: testMac}),
+		testFilled(SFlowExtended80211TxFlowRecord{BSSID: testMac}),
+		SFlowExtended80211AggregationFlowRecord{PDUs: []SFlow80211PDU{
+			{Records: SFlowRecords{testFilled(SFlowExtended80211PayloadFlowRecord{Data: []byte{1}})}},
+			{Records: SFlowRecords{testFilled(SFlowExtendedSwitchFlowRecord{})}},
+		}},
+		testFilled(SFlowExtendedSocketFlowRecord{LocalIP: testIPv4, RemoteIP: testIPv4}),
+		testFilled(SFlowExtendedSocketFlowRecord{LocalIP: testIPv6, RemoteIP: testIPv6}),
+		testFilled(SFlowExtendedTCPInfoFlowRecord{}),
+		testFilled(SFlowMemcacheFlowRecord{}),
+		// with X-Forwarded-For, the second version of the record
+		testFilled(SFlowHTTPRequestFlowRecord{}),
+		func() SFlowRecord {
+			record := testFilled(SFlowHTTPRequestFlowRecord{}).(SFlowHTTPRequestFlowRecord)
+			record.XFF = ""
+			return record
+		}(),
+		testFilled(SFlowAppOperationFlowRecord{}),
+		testFilled(SFlowAppParentContextFlowRecord{}),
+		testFilled(SFlowAppActorFlowRecord{}),
+		testFilled(SFlowAppActorFlowRecord{SFlowBaseFlowRecord: SFlowBaseFlowRecord{Format: SFlowTypeAppTargetFlow}}),
+	}
+}
+
+// testCounterRecords has a record of every counter record type
+func testCounterRecords() []SFlowRecord {
+	return []SFlowRecord{
+		testFilled(SFlowGenericInterfaceCounters{}),
+		testFilled(SFlowEthernetCounters{}),
+		testFilled(SFlowVLANCounters{}),
+		testFilled(SFlow80211Counters{}),
+		testFilled(SFlowLACPCounters{ActorSystemID: testMac, PartnerSystemID: testMac}),
+		testFilled(SFlowSFPCounters{Lanes: []SFlowSFPLane{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, {11, 12, 13, 14, 15, 16, 17, 18, 19, 20}}}),
+		SFlowProcessorCounters{FiveSecCpu: 1, OneMinCpu: 2, FiveMinCpu: 3, TotalMemory: 1 << 40, FreeMemory: 1<<33 + 5},
+		testFilled(SFlowRadioCounters{}),
+		SFlowOFPortCounters{OfDataPathId: []byte{0, 0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc}, OfPort: 3},
+		SFlowOFPortNameCounters{OfPortName: "vnet12"},
+		testFilled(SFlowHostDescrCounters{UUID: make([]byte, 16)}),
+		SFlowHostAdaptersCounters{Adapters: []SFlowHostAdapter{{IfIndex: 2, MacAddresses: []net.HardwareAddr{testMac, testMac}}, {IfIndex: 3}}},
+		testFilled(SFlowHostParentCounters{}),
+		testFilled(SFlowHostCPUCounters{}),
+		// sent before the steal and guest times were appended
+		func() SFlowRecord {
+			record := testFilled(SFlowHostCPUCounters{}).(SFlowHostCPUCounters)
+			record.FlowDataLength = hostCPUCountersV1Length
+			record.CPUSteal, record.CPUGuest, record.CPUGuestNice = 0, 0, 0
+			return record
+		}(),
+		testFilled(SFlowHostMemoryCounters{}),
+		testFilled(SFlowHostDiskCounters{}),
+		testFilled(SFlowHostNetIOCounters{}),
+		testFilled(SFlowHostIPCounters{}),
+		testFilled(SFlowHostICMPCounters{}),
+		testFilled(SFlowHostTCPCounters{}),
+		testFilled(SFlowHostUDPCounters{}),
+		testFilled(SFlowVirtNodeCounters{}),
+		testFilled(SFlowVirtCPUCounters{}),
+		testFilled(SFlowVirtMemoryCounters{}),
+		testFilled(SFlowVirtDiskCounters{}),
+		testFilled(SFlowVirtNetIOCounters{}),
+		testFilled(SFlowMemcacheLegacyCounters{}),
+		testFilled(SFlowHTTPCounters{}),
+		testFilled(SFlowAppCounters{}),
+		testFilled(SFlowAppResourcesCounters{}),
+		testFilled(SFlowMemcacheCounters{}),
+		testFilled(SFlowVDICounters{}),
+		testFilled(SFlowAppWorkersCounters{}),
+		testFilled(SFlowOVSDPCounters{}),
+	}
+}
+
+// testRoundTrip encodes a datagram, decodes it and checks that encoding
+// the result again gives the same bytes
+func testRoundTrip(t *testing.T, datagram *Datagram) *Datagram {
+	t.Helper()
+	encoded, err := Encode(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Encode(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, again) {
+		t.Fatalf("encode(decode(x)) != x\n%x\n%x", encoded, again)
+	}
+	return decoded
+}
+
+// testRecordRoundTrip checks a record that went through Encode and Decode
+func testRecordRoundTrip(t *testing.T, record SFlowRecord, decoded SFlowRecords) {
+	t.Helper()
+	if len(decoded) != 1 {
+		t.Fatalf("records %+v", decoded)
+	}
+	if reflect.TypeOf(decoded[0]) != reflect.TypeOf(record) || decoded[0].RecordType() != record.RecordType() || decoded[0].RecordFormat() != record.RecordFormat() {
+		t.Fatalf("decoded %T %s, want %T %s", decoded[0], decoded[0].RecordType(), record, record.RecordType())
+	}
+	encoded, err := EncodeRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, mustEncodeRecord(t, decoded[0])) {
+		t.Fatalf("the decoded record encodes differently")
+	}
+	if decoded[0].RecordLength() != uint32(len(encoded)-8) {
+		t.Fatalf("record length %d, want %d", decoded[0].RecordLength(), len(encoded)-8)
+	}
+}
+
+func mustEncodeRecord(t *testing.T, record SFlowRecord) []byte {
+	t.Helper()
+	encoded, err := EncodeRecord(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestRoundTripFlowRecords(t *testing.T) {
+	for _, record := range testFlowRecords() {
+		record := record
+		t.Run(record.RecordType(), func(t *testing.T) {
+			datagram := testRoundTrip(t, &Datagram{
+				DatagramVersion: 5,
+				AgentAddress:    testIPv4,
+				FlowSamples: []SFlowFlowSample{
+					{SequenceNumber: 1, SourceIDClass: 2, SourceIDIndex: 7, SamplingRate: 100, InputInterface: 1, OutputInterface: 2, Records: SFlowRecords{record}},
+				},
+			})
+			if len(datagram.FlowSamples) != 1 {
+				t.Fatalf("flow samples %+v", datagram.FlowSamples)
+			}
+			testRecordRoundTrip(t, record, datagram.FlowSamples[0].Records)
+
+			// also as the record of a discarded packet
+			datagram = testRoundTrip(t, &Datagram{
+				DatagramVersion: 5,
+				AgentAddress:    testIPv4,
+				DiscardSamples:  []SFlowDiscardSample{{SequenceNumber: 1, SourceIDIndex: 4, Drops: 3, Reason: SFlowDropRED, Records: SFlowRecords{record}}},
+			})
+			if len(datagram.DiscardSamples) != 1 {
+				t.Fatalf("discard samples %+v", datagram.DiscardSamples)
+			}
+			testRecordRoundTrip(t, record, datagram.DiscardSamples[0].Records)
+		})
+	}
+}
+
+func TestRoundTripCounterRecords(t *testing.T) {
+	for _, record := range testCounterRecords() {
+		record := record
+		t.Run(record.RecordType(), func(t *testing.T) {
+			datagram := testRoundTrip(t, &Datagram{
+				DatagramVersion: 5,
+				AgentAddress:    testIPv6,
+				CounterSamples:  []SFlowCounterSample{{SequenceNumber: 1, SourceIDIndex: 9, Records: SFlowRecords{record}}},
+			})
+			if len(datagram.CounterSamples) != 1 {
+				t.Fatalf("counter samples %+v", datagram.CounterSamples)
+			}
+			testRecordRoundTrip(t, record, datagram.CounterSamples[0].Records)
+		})
+	}
+}
+
+// The samples are encoded in the order they were decoded in, not grouped
+// by kind
+func TestRoundTripSampleOrder(t *testing.T) {
+	counter := func(sequence uint32) SFlowCounterSample {
+		return SFlowCounterSample{SequenceNumber: sequence, SourceIDIndex: 1, Records: SFlowRecords{SFlowOVSDPCounters{Hits: sequence}}}
+	}
+	datagram := &Datagram{
+		DatagramVersion: 5,
+		AgentAddress:    testIPv4,
+		FlowSamples:     []SFlowFlowSample{{SequenceNumber: 2, SamplingRate: 1, Records: SFlowRecords{SFlowExtendedSwitchFlowRecord{IncomingVLAN: 10}}}},
+		CounterSamples:  []SFlowCounterSample{counter(1), counter(4)},
+		DiscardSamples:  []SFlowDiscardSample{{SequenceNumber: 3, Reason: SFlowDropACL}},
+		SampleOrder:     []SFlowSampleType{SFlowTypeCounterSample, SFlowTypeFlowSample, SFlowTypeDiscardSample, SFlowTypeCounterSample},
+	}
+	encoded, err := Encode(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var formats []SFlowSampleType
+	for _, sample := range Inspect(encoded).Samples {
+		formats = append(formats, sample.Format)
+	}
+	if !reflect.DeepEqual(formats, datagram.SampleOrder) {
+		t.Fatalf("sample formats %v, want %v", formats, datagram.SampleOrder)
+	}
+	decoded := testRoundTrip(t, datagram)
+	if !reflect.DeepEqual(decoded.SampleOrder, datagram.SampleOrder) {
+		t.Fatalf("sample order %v, want %v", decoded.SampleOrder, datagram.SampleOrder)
+	}
+
+	// without SampleOrder the flow samples come first
+	datagram.SampleOrder = nil
+	decoded = testRoundTrip(t, datagram)
+	want := []SFlowSampleType{SFlowTypeFlowSample, SFlowTypeCounterSample, SFlowTypeCounterSample, SFlowTypeDiscardSample}
+	if !reflect.DeepEqual(decoded.SampleOrder, want) {
+		t.Fatalf("sample order %v, want %v", decoded.SampleOrder, want)
+	}
+}
+
+func TestEncodeAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   net.IP
+		want []byte
+	}{
+		{"nil", nil, xdr(0)},
+		// as decoded from the unknown address type
+		{"empty", net.IP{}, xdr(0)},
+		{"invalid length", net.IP{1, 2, 3, 4, 5}, xdr(0)},
+		{"IPv4", testIPv4, xdr(1, 0x0a000001)},
+		{"IPv4 mapped IPv6", net.ParseIP("10.0.0.1"), xdr(1, 0x0a000001)},
+		{"IPv6", testIPv6, xdr(2, 0x20010db8, 0, 0, 1)},
+	}
+	for _, test := range tests {
+		e := &encoder{}
+		e.address(test.ip)
+		if !bytes.Equal(e.buf, test.want) {
+			t.Errorf("%s: %x, want %x", test.name, e.buf, test.want)
+		}
+	}
+
+	// a datagram without an agent address is encoded, Decode rejects it
+	payload := xdr(5, 0, 1, 2, 3, 0)
+	if encoded, err := Encode(&Datagram{DatagramVersion: 5, SubAgentID: 1, SequenceNumber: 2, AgentUptime: 3}); err != nil || !bytes.Equal(encoded, payload) {
+		t.Fatalf("encode = %x %v, want %x", encoded, err, payload)
+	}
+	if _, err := Decode(payload); err == nil {
+		t.Fatal("an agent of unknown address type was decoded")
+	}
+}
+
+func TestEncodeRecordUnknownType(t *testing.T) {
+	if _, err := EncodeRecord(SFlowBaseFlowRecord{Format: SFlowTypeRawPacketFlow}); err == nil {
+		t.Fatal("a base record was encoded")
+	}
+}
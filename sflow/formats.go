@@ -0,0 +1,649 @@
+package sflow
+
+import "strconv"
+
+// ****************************************************************************************************
+//  Record Formats
+// ****************************************************************************************************
+
+// Every record type reports its own format, a record built in code
+// without its base record encodes and names like a decoded one. The
+// enterprise still comes from the base record.
+
+// flowRecordType names a flow record format of an enterprise
+func flowRecordType(enterprise SFlowEnterpriseID, format SFlowFlowRecordType) string {
+	if name := format.Name(); enterprise == 0 && name != "" {
+		return name
+	}
+	return "flow_" + strconv.Itoa(int(enterprise)) + "_" + strconv.Itoa(int(format))
+}
+
+// counterRecordType names a counter record format of an enterprise
+func counterRecordType(enterprise SFlowEnterpriseID, format SFlowCounterRecordType) string {
+	if name := format.Name(); enterprise == 0 && name != "" {
+		return name
+	}
+	return "counters_" + strconv.Itoa(int(enterprise)) + "_" + strconv.Itoa(int(format))
+}
+
+// ****************************************************************************************************
+//  Flow Record Formats
+// ****************************************************************************************************
+
+func (r SFlowRawPacketFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeRawPacketFlow)
+}
+
+func (r SFlowRawPacketFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeRawPacketFlow)
+}
+
+func (r SFlowExtendedUserFlow) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedUserFlow)
+}
+
+func (r SFlowExtendedUserFlow) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedUserFlow)
+}
+
+func (r SFlowExtendedURLRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedUrlFlow)
+}
+
+func (r SFlowExtendedURLRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedUrlFlow)
+}
+
+func (r SFlowExtendedSwitchFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedSwitchFlow)
+}
+
+func (r SFlowExtendedSwitchFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedSwitchFlow)
+}
+
+func (r SFlowExtendedRouterFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedRouterFlow)
+}
+
+func (r SFlowExtendedRouterFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedRouterFlow)
+}
+
+func (r SFlowExtendedGatewayFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedGatewayFlow)
+}
+
+func (r SFlowExtendedGatewayFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedGatewayFlow)
+}
+
+func (r SFlowEthernetFrameFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeEthernetFrameFlow)
+}
+
+func (r SFlowEthernetFrameFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeEthernetFrameFlow)
+}
+
+func (r SFlowIpv4FlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeIpv4Flow)
+}
+
+func (r SFlowIpv4FlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeIpv4Flow)
+}
+
+func (r SFlowIpv6FlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeIpv6Flow)
+}
+
+func (r SFlowIpv6FlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeIpv6Flow)
+}
+
+func (r SFlowExtendedIpv4TunnelEgressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedIpv4TunnelEgressFlow)
+}
+
+func (r SFlowExtendedIpv4TunnelEgressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedIpv4TunnelEgressFlow)
+}
+
+func (r SFlowExtendedIpv4TunnelIngressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedIpv4TunnelIngressFlow)
+}
+
+func (r SFlowExtendedIpv4TunnelIngressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedIpv4TunnelIngressFlow)
+}
+
+func (r SFlowExtendedIpv6TunnelEgressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedIpv6TunnelEgressFlow)
+}
+
+func (r SFlowExtendedIpv6TunnelEgressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedIpv6TunnelEgressFlow)
+}
+
+func (r SFlowExtendedIpv6TunnelIngressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedIpv6TunnelIngressFlow)
+}
+
+func (r SFlowExtendedIpv6TunnelIngressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedIpv6TunnelIngressFlow)
+}
+
+func (r SFlowExtendedDecapsulateEgressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedDecapsulateEgressFlow)
+}
+
+func (r SFlowExtendedDecapsulateEgressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedDecapsulateEgressFlow)
+}
+
+func (r SFlowExtendedDecapsulateIngressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedDecapsulateIngressFlow)
+}
+
+func (r SFlowExtendedDecapsulateIngressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedDecapsulateIngressFlow)
+}
+
+func (r SFlowExtendedVniEgressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedVniEgressFlow)
+}
+
+func (r SFlowExtendedVniEgressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedVniEgressFlow)
+}
+
+func (r SFlowExtendedVniIngressRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedVniIngressFlow)
+}
+
+func (r SFlowExtendedVniIngressRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedVniIngressFlow)
+}
+
+func (r SFlowExtendedEgressQueueRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedEgressQueueFlow)
+}
+
+func (r SFlowExtendedEgressQueueRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedEgressQueueFlow)
+}
+
+func (r SFlowExtendedTransitRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedTransitFlow)
+}
+
+func (r SFlowExtendedTransitRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedTransitFlow)
+}
+
+func (r SFlowExtendedQueueRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedQueueFlow)
+}
+
+func (r SFlowExtendedQueueRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedQueueFlow)
+}
+
+func (r SFlowExtendedFunctionFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedFunctionFlow)
+}
+
+func (r SFlowExtendedFunctionFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedFunctionFlow)
+}
+
+func (r SFlowExtendedMPLSFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedMlpsFlow)
+}
+
+func (r SFlowExtendedMPLSFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedMlpsFlow)
+}
+
+func (r SFlowExtendedNATFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedNatFlow)
+}
+
+func (r SFlowExtendedNATFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedNatFlow)
+}
+
+func (r SFlowExtendedMPLSTunnelFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedMlpsTunnelFlow)
+}
+
+func (r SFlowExtendedMPLSTunnelFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedMlpsTunnelFlow)
+}
+
+func (r SFlowExtendedMPLSVCFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedMlpsVcFlow)
+}
+
+func (r SFlowExtendedMPLSVCFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedMlpsVcFlow)
+}
+
+func (r SFlowExtendedMPLSFECFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedMlpsFecFlow)
+}
+
+func (r SFlowExtendedMPLSFECFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedMlpsFecFlow)
+}
+
+func (r SFlowExtendedMPLSLVPFECFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedMlpsLvpFecFlow)
+}
+
+func (r SFlowExtendedMPLSLVPFECFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedMlpsLvpFecFlow)
+}
+
+func (r SFlowExtendedVLANTunnelFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedVlanFlow)
+}
+
+func (r SFlowExtendedVLANTunnelFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedVlanFlow)
+}
+
+func (r SFlowExtended80211PayloadFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtended80211PayloadFlow)
+}
+
+func (r SFlowExtended80211PayloadFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtended80211PayloadFlow)
+}
+
+func (r SFlowExtended80211RxFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtended80211RxFlow)
+}
+
+func (r SFlowExtended80211RxFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtended80211RxFlow)
+}
+
+func (r SFlowExtended80211TxFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtended80211TxFlow)
+}
+
+func (r SFlowExtended80211TxFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtended80211TxFlow)
+}
+
+func (r SFlowExtended80211AggregationFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtended80211AggregationFlow)
+}
+
+func (r SFlowExtended80211AggregationFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtended80211AggregationFlow)
+}
+
+func (r SFlowExtendedTCPInfoFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeExtendedTCPInfoFlow)
+}
+
+func (r SFlowExtendedTCPInfoFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeExtendedTCPInfoFlow)
+}
+
+func (r SFlowMemcacheFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeMemcacheFlow)
+}
+
+func (r SFlowMemcacheFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeMemcacheFlow)
+}
+
+func (r SFlowAppOperationFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeAppOperationFlow)
+}
+
+func (r SFlowAppOperationFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeAppOperationFlow)
+}
+
+func (r SFlowAppParentContextFlowRecord) RecordFormat() uint32 {
+	return uint32(SFlowTypeAppParentContextFlow)
+}
+
+func (r SFlowAppParentContextFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, SFlowTypeAppParentContextFlow)
+}
+
+// format tells the IPv4 and IPv6 socket records apart by the local
+// address when the record was built in code
+func (r SFlowExtendedSocketFlowRecord) format() SFlowFlowRecordType {
+	switch {
+	case r.Format == SFlowTypeExtendedSocketIpv4Flow || r.Format == SFlowTypeExtendedSocketIpv6Flow:
+		return r.Format
+	case r.LocalIP.To4() != nil:
+		return SFlowTypeExtendedSocketIpv4Flow
+	}
+	return SFlowTypeExtendedSocketIpv6Flow
+}
+
+func (r SFlowExtendedSocketFlowRecord) RecordFormat() uint32 {
+	return uint32(r.format())
+}
+
+func (r SFlowExtendedSocketFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, r.format())
+}
+
+// format is the second version of the HTTP record for the records that
+// carry X-Forwarded-For, only that version has it
+func (r SFlowHTTPRequestFlowRecord) format() SFlowFlowRecordType {
+	if r.Format == SFlowTypeHTTP2Flow || r.XFF != "" {
+		return SFlowTypeHTTP2Flow
+	}
+	return SFlowTypeHTTPFlow
+}
+
+func (r SFlowHTTPRequestFlowRecord) RecordFormat() uint32 {
+	return uint32(r.format())
+}
+
+func (r SFlowHTTPRequestFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, r.format())
+}
+
+// format is the initiator record unless the record was decoded as the
+// target one
+func (r SFlowAppActorFlowRecord) format() SFlowFlowRecordType {
+	if r.Format == SFlowTypeAppTargetFlow {
+		return SFlowTypeAppTargetFlow
+	}
+	return SFlowTypeAppInitiatorFlow
+}
+
+func (r SFlowAppActorFlowRecord) RecordFormat() uint32 {
+	return uint32(r.format())
+}
+
+func (r SFlowAppActorFlowRecord) RecordType() string {
+	return flowRecordType(r.EnterpriseID, r.format())
+}
+
+// ****************************************************************************************************
+//  Counter Record Formats
+// ****************************************************************************************************
+
+func (r SFlowGenericInterfaceCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeGenericInterfaceCounters)
+}
+
+func (r SFlowGenericInterfaceCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeGenericInterfaceCounters)
+}
+
+func (r SFlowEthernetCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeEthernetInterfaceCounters)
+}
+
+func (r SFlowEthernetCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeEthernetInterfaceCounters)
+}
+
+func (r SFlowVLANCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVLANCounters)
+}
+
+func (r SFlowVLANCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVLANCounters)
+}
+
+func (r SFlow80211Counters) RecordFormat() uint32 {
+	return uint32(SFlowType80211Counters)
+}
+
+func (r SFlow80211Counters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowType80211Counters)
+}
+
+func (r SFlowLACPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeLACPCounters)
+}
+
+func (r SFlowLACPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeLACPCounters)
+}
+
+func (r SFlowSFPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeSFPCounters)
+}
+
+func (r SFlowSFPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeSFPCounters)
+}
+
+func (r SFlowProcessorCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeProcessorCounters)
+}
+
+func (r SFlowProcessorCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeProcessorCounters)
+}
+
+func (r SFlowRadioCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeRadioCounters)
+}
+
+func (r SFlowRadioCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeRadioCounters)
+}
+
+func (r SFlowOFPortCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeOFPortCounter)
+}
+
+func (r SFlowOFPortCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeOFPortCounter)
+}
+
+func (r SFlowOFPortNameCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeOFPortNameCounter)
+}
+
+func (r SFlowOFPortNameCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeOFPortNameCounter)
+}
+
+func (r SFlowHostDescrCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostDescrCounters)
+}
+
+func (r SFlowHostDescrCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostDescrCounters)
+}
+
+func (r SFlowHostAdaptersCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostAdaptersCounters)
+}
+
+func (r SFlowHostAdaptersCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostAdaptersCounters)
+}
+
+func (r SFlowHostParentCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostParentCounters)
+}
+
+func (r SFlowHostParentCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostParentCounters)
+}
+
+func (r SFlowHostCPUCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostCPUCounters)
+}
+
+func (r SFlowHostCPUCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostCPUCounters)
+}
+
+func (r SFlowHostMemoryCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostMemoryCounters)
+}
+
+func (r SFlowHostMemoryCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostMemoryCounters)
+}
+
+func (r SFlowHostDiskCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostDiskCounters)
+}
+
+func (r SFlowHostDiskCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostDiskCounters)
+}
+
+func (r SFlowHostNetIOCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostNetIOCounters)
+}
+
+func (r SFlowHostNetIOCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostNetIOCounters)
+}
+
+func (r SFlowHostIPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostIPCounters)
+}
+
+func (r SFlowHostIPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostIPCounters)
+}
+
+func (r SFlowHostICMPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostICMPCounters)
+}
+
+func (r SFlowHostICMPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostICMPCounters)
+}
+
+func (r SFlowHostTCPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostTCPCounters)
+}
+
+func (r SFlowHostTCPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostTCPCounters)
+}
+
+func (r SFlowHostUDPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHostUDPCounters)
+}
+
+func (r SFlowHostUDPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHostUDPCounters)
+}
+
+func (r SFlowVirtNodeCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVirtNodeCounters)
+}
+
+func (r SFlowVirtNodeCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVirtNodeCounters)
+}
+
+func (r SFlowVirtCPUCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVirtCPUCounters)
+}
+
+func (r SFlowVirtCPUCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVirtCPUCounters)
+}
+
+func (r SFlowVirtMemoryCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVirtMemoryCounters)
+}
+
+func (r SFlowVirtMemoryCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVirtMemoryCounters)
+}
+
+func (r SFlowVirtDiskCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVirtDiskCounters)
+}
+
+func (r SFlowVirtDiskCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVirtDiskCounters)
+}
+
+func (r SFlowVirtNetIOCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVirtNetIOCounters)
+}
+
+func (r SFlowVirtNetIOCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVirtNetIOCounters)
+}
+
+func (r SFlowMemcacheLegacyCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeMemcacheLegacyCounters)
+}
+
+func (r SFlowMemcacheLegacyCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeMemcacheLegacyCounters)
+}
+
+func (r SFlowHTTPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeHTTPCounters)
+}
+
+func (r SFlowHTTPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeHTTPCounters)
+}
+
+func (r SFlowAppCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeAppCounters)
+}
+
+func (r SFlowAppCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeAppCounters)
+}
+
+func (r SFlowAppResourcesCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeAppResourcesCounters)
+}
+
+func (r SFlowAppResourcesCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeAppResourcesCounters)
+}
+
+func (r SFlowMemcacheCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeMemcacheCounters)
+}
+
+func (r SFlowMemcacheCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeMemcacheCounters)
+}
+
+func (r SFlowVDICounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeVDICounters)
+}
+
+func (r SFlowVDICounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeVDICounters)
+}
+
+func (r SFlowAppWorkersCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeAppWorkersCounters)
+}
+
+func (r SFlowAppWorkersCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeAppWorkersCounters)
+}
+
+func (r SFlowOVSDPCounters) RecordFormat() uint32 {
+	return uint32(SFlowTypeOVSDPCounters)
+}
+
+func (r SFlowOVSDPCounters) RecordType() string {
+	return counterRecordType(r.EnterpriseID, SFlowTypeOVSDPCounters)
+}
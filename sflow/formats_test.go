@@ -0,0 +1,37 @@
+package sflow
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRecordFormats(t *testing.T) {
+	records := []struct {
+		record SFlowRecord
+		format uint32
+		name   string
+	}{
+		{SFlowRawPacketFlowRecord{}, 1, "raw_packet"},
+		{SFlowExtendedSwitchFlowRecord{}, 1001, "extended_switch"},
+		{SFlowExtendedSocketFlowRecord{LocalIP: net.ParseIP("10.0.0.1")}, 2100, "extended_socket_ipv4"},
+		{SFlowExtendedSocketFlowRecord{LocalIP: net.ParseIP("2001:db8::1")}, 2101, "extended_socket_ipv6"},
+		{SFlowHTTPRequestFlowRecord{}, 2201, "http_request"},
+		// only the second version carries X-Forwarded-For
+		{SFlowHTTPRequestFlowRecord{XFF: "192.0.2.1"}, 2206, "http_request_xff"},
+		{SFlowAppActorFlowRecord{}, 2204, "app_initiator"},
+		{SFlowAppActorFlowRecord{SFlowBaseFlowRecord: SFlowBaseFlowRecord{Format: SFlowTypeAppTargetFlow}}, 2205, "app_target"},
+		{SFlowGenericInterfaceCounters{}, 1, "generic_interface_counters"},
+		{SFlowOVSDPCounters{}, 2207, "ovs_dp_counters"},
+		// the enterprise comes from the base record
+		{SFlowRawPacketFlowRecord{SFlowBaseFlowRecord: SFlowBaseFlowRecord{EnterpriseID: 4413}}, 1, "flow_4413_1"},
+		{SFlowEthernetCounters{SFlowBaseCounterRecord: SFlowBaseCounterRecord{EnterpriseID: 4413}}, 2, "counters_4413_2"},
+	}
+	for _, r := range records {
+		if format := r.record.RecordFormat(); format != r.format {
+			t.Errorf("%T: format %d, want %d", r.record, format, r.format)
+		}
+		if name := r.record.RecordType(); name != r.name {
+			t.Errorf("%T: name %q, want %q", r.record, name, r.name)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package sflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+
+	"github.com/google/gopacket/layers"
+)
+
+// ****************************************************************************************************
+//  Datagram Inspection
+// ****************************************************************************************************
+
+// Inspection is the annotated decode of a datagram: where each field,
+// sample and record starts in the payload, and why the samples and
+// records Decode leaves out are skipped
+type Inspection struct {
+	Length  int
+	Fields  []InspectedField
+	Samples []InspectedSample
+	// Err is why the inspection stopped before the end of the payload,
+	// Decode returns an error for the same datagram
+	Err error
+}
+
+// InspectedField is a field of the datagram, a sample or a record header
+type InspectedField struct {
+	Offset int
+	Length int
+	Name   string
+	Value  interface{}
+}
+
+// InspectedSample is a sample, its header fields and its records
+type InspectedSample struct {
+	Offset       int
+	Length       int
+	EnterpriseID SFlowEnterpriseID
+	Format       SFlowSampleType
+	Fields       []InspectedField
+	Records      []InspectedRecord
+	// Skipped is set for the samples Decode leaves out, the unknown and
+	// expanded ones, Err says why, also for the samples it drops because
+	// of a record it cannot decode
+	Skipped bool
+	Err     error
+}
+
+// InspectedRecord is a flow or counter record of a sample
+type InspectedRecord struct {
+	Offset       int
+	Length       int
+	EnterpriseID SFlowEnterpriseID
+	Format       uint32
+	// Counter is set for the counter records, Format is then a
+	// SFlowCounterRecordType, else a SFlowFlowRecordType
+	Counter bool
+	// Fields are the format and length of the record followed by the
+	// fields of the decoded record, up to the first one whose length the
+	// record type does not tell (an address, an array)
+	Fields []InspectedField
+	// Record is the decoded record, nil with the Err of its decoder
+	Record SFlowRecord
+	Err    error
+	// Unread are the bytes of the record its decoder left, Decode reads
+	// the next record from there
+	Unread int
+}
+
+// Description is the name of the record format, e.g. "Raw Packet Flow
+// Record" or "generic_interface_counters", "" for the formats this
+// package does not know
+func (r InspectedRecord) Description() string {
+	if r.Counter {
+		return SFlowCounterRecordType(r.Format).Name()
+	}
+	if description := SFlowFlowRecordType(r.Format).String(); description != "" {
+		return description
+	}
+	return SFlowFlowRecordType(r.Format).Name()
+}
+
+// SampleTypeName names the sample formats, "" for the unknown ones
+func SampleTypeName(format SFlowSampleType) string {
+	switch format {
+	case SFlowTypeFlowSample:
+		return "flow sample"
+	case SFlowTypeCounterSample:
+		return "counter sample"
+	case SFlowTypeExpandedFlowSample:
+		return "expanded flow sample"
+	case SFlowTypeExpandedCounterSample:
+		return "expanded counter sample"
+	case SFlowTypeDiscardSample:
+		return "discard sample"
+	}
+	return ""
+}
+
+// inspector reads the fields of a payload and records their offset, data
+// is the part of the payload left before the offset end
+type inspector struct {
+	data []byte
+	end  int
+}
+
+func (in *inspector) offset() int { return in.end - len(in.data) }
+
+func (in *inspector) uint32(fields *[]InspectedField, name string) uint32 {
+	if len(in.data) < 4 {
+		panic(fmt.Errorf("truncated at offset %#x reading %s", in.offset(), name))
+	}
+	value := binary.BigEndian.Uint32(in.data[:4])
+	*fields = append(*fields, InspectedField{Offset: in.offset(), Length: 4, Name: name, Value: value})
+	in.data = in.data[4:]
+	return value
+}
+
+// annotate replaces the value of the last field
+func annotate(fields []InspectedField, value interface{}) {
+	fields[len(fields)-1].Value = value
+}
+
+// Inspect decodes a datagram the way Decode does, keeping the offsets of
+// its parts and the samples and records Decode skips
+func Inspect(payload []byte) (inspection *Inspection) {
+	inspection = &Inspection{Length: len(payload)}
+	in := &inspector{data: payload, end: len(payload)}
+	defer func() {
+		if r := recover(); r != nil {
+			inspection.Err = fmt.Errorf("malformed sFlow datagram: %v", r)
+		}
+	}()
+
+	fields := &inspection.Fields
+	version := in.uint32(fields, "version")
+	addressType := layers.SFlowIPType(in.uint32(fields, "agent address type"))
+	annotate(*fields, addressType)
+	if err := checkDatagramHeader(version, addressType); err != nil {
+		inspection.Err = err
+		return inspection
+	}
+	if len(in.data) < addressType.Length() {
+		panic(fmt.Errorf("truncated at offset %#x reading agent address", in.offset()))
+	}
+	address := append([]byte(nil), in.data[:addressType.Length()]...)
+	*fields = append(*fields, InspectedField{Offset: in.offset(), Length: len(address), Name: "agent address", Value: address})
+	in.data = in.data[len(address):]
+	in.uint32(fields, "sub agent id")
+	in.uint32(fields, "sequence number")
+	in.uint32(fields, "uptime (ms)")
+	count := in.uint32(fields, "sample count")
+
+	for i := uint32(0); i < count && len(in.data) > 0; i++ {
+		inspection.Samples = append(inspection.Samples, in.sample())
+	}
+	if len(in.data) > 0 {
+		inspection.Err = fmt.Errorf("%d bytes after the %d samples", len(in.data), count)
+	}
+	return inspection
+}
+
+func (in *inspector) sample() InspectedSample {
+	s := InspectedSample{Offset: in.offset()}
+	fields := &s.Fields
+	s.EnterpriseID, s.Format = SFlowDataFormat(in.uint32(fields, "sample format")).decode()
+	annotate(*fields, fmt.Sprintf("%d:%d", s.EnterpriseID, s.Format))
+	length := in.uint32(fields, "sample length")
+	s.Length = 8 + int(length)
+	if len(in.data) < int(length) {
+		panic(fmt.Errorf("sample at offset %#x is %d bytes, %d left", s.Offset, length, len(in.data)))
+	}
+	// the next sample starts after the length, whatever the records
+	next, end := in.data[length:], in.end
+	in.data, in.end = in.data[:length], in.offset()+int(length)
+
+	// Decode only tells the samples apart by their format, it skips the
+	// expanded ones, they are still annotated here
+	expanded := s.Format == SFlowTypeExpandedFlowSample || s.Format == SFlowTypeExpandedCounterSample || s.Format == SFlowTypeDiscardSample
+	switch s.Format {
+	case SFlowTypeExpandedFlowSample, SFlowTypeExpandedCounterSample:
+		s.Skipped = true
+		s.Err = fmt.Errorf("Decode skips the %ss", SampleTypeName(s.Format))
+	}
+	switch SampleTypeName(s.Format) {
+	case "":
+		s.Skipped = true
+		s.Err = fmt.Errorf("unsupported sample type %d:%d", s.EnterpriseID, s.Format)
+	default:
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					s.Err = fmt.Errorf("%v", r)
+				}
+			}()
+			in.uint32(fields, "sequence number")
+			if expanded {
+				in.uint32(fields, "source id type")
+				in.uint32(fields, "source id index")
+			} else {
+				class, index := SFlowDataSource(in.uint32(fields, "source id")).decode()
+				annotate(*fields, fmt.Sprintf("%d:%d", class, index))
+			}
+			switch s.Format {
+			case SFlowTypeFlowSample, SFlowTypeExpandedFlowSample:
+				in.uint32(fields, "sampling rate")
+				in.uint32(fields, "sample pool")
+				in.uint32(fields, "drops")
+				if expanded {
+					in.uint32(fields, "input interface format")
+					in.uint32(fields, "input interface")
+					in.uint32(fields, "output interface format")
+					in.uint32(fields, "output interface")
+				} else {
+					in.uint32(fields, "input interface")
+					in.uint32(fields, "output interface")
+				}
+			case SFlowTypeDiscardSample:
+				in.uint32(fields, "drops")
+				in.uint32(fields, "input interface")
+				in.uint32(fields, "output interface")
+				reason := in.uint32(fields, "reason")
+				annotate(*fields, SFlowDropReason(reason))
+			}
+			recordCount := in.uint32(fields, "record count")
+			counter := s.Format == SFlowTypeCounterSample || s.Format == SFlowTypeExpandedCounterSample
+			for i := uint32(0); i < recordCount; i++ {
+				record := in.record(counter)
+				if record.Err != nil && s.Err == nil {
+					s.Err = fmt.Errorf("Decode drops the sample, the record at offset %#x fails: %v", record.Offset, record.Err)
+				}
+				s.Records = append(s.Records, record)
+			}
+			if len(in.data) > 0 && s.Err == nil {
+				s.Err = fmt.Errorf("%d bytes after the %d records", len(in.data), recordCount)
+			}
+		}()
+	}
+	in.data, in.end = next, end
+	return s
+}
+
+// record decodes one record with the decoders of Decode, a record they
+// cannot decode is skipped by its length
+func (in *inspector) record(counter bool) (r InspectedRecord) {
+	r = InspectedRecord{Offset: in.offset(), Counter: counter}
+	if len(in.data) < 8 {
+		panic(fmt.Errorf("truncated at offset %#x reading a record header", in.offset()))
+	}
+	format := binary.BigEndian.Uint32(in.data[:4])
+	r.EnterpriseID, r.Format = SFlowEnterpriseID(format>>12), format&0xFFF
+	length := int(binary.BigEndian.Uint32(in.data[4:8]))
+	padded := (length + 3) &^ 3
+	r.Length = 8 + length
+	r.Fields = []InspectedField{
+		{Offset: r.Offset, Length: 4, Name: "record format", Value: fmt.Sprintf("%d:%d", r.EnterpriseID, r.Format)},
+		{Offset: r.Offset + 4, Length: 4, Name: "record length", Value: uint32(length)},
+	}
+	if len(in.data) < 8+padded {
+		panic(fmt.Errorf("record at offset %#x is %d bytes, %d left", r.Offset, length, len(in.data)-8))
+	}
+	data := in.data[:8+padded]
+	in.data = in.data[8+padded:]
+	body := &inspector{data: data[8:], end: r.Offset + 8 + padded}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.Record, r.Err = nil, fmt.Errorf("truncated record: %v", recovered)
+		}
+	}()
+	var records SFlowRecords
+	if counter {
+		records, r.Err = decodeCounterRecords(&data, 1)
+	} else {
+		records, r.Err = decodeFlowRecords(&data, 1)
+	}
+	if r.Err == nil && len(records) == 1 {
+		r.Record = records[0]
+		r.Unread = len(data)
+		body.fields(&r.Fields, reflect.ValueOf(r.Record))
+	}
+	return r
+}
+
+var (
+	inspectedFlowRecordType    = reflect.TypeOf(SFlowBaseFlowRecord{})
+	inspectedCounterRecordType = reflect.TypeOf(SFlowBaseCounterRecord{})
+	inspectedMacType           = reflect.TypeOf(net.HardwareAddr{})
+)
+
+// fields annotates the fields of the decoded struct v in their order, as
+// long as each one is read back from the data at its offset: the numbers,
+// strings and MAC addresses. It reports whether every field was.
+func (in *inspector) fields(fields *[]InspectedField, v reflect.Value) bool {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		switch {
+		case field.Type == inspectedFlowRecordType || field.Type == inspectedCounterRecordType:
+			// the format and length of the record
+		case field.PkgPath != "":
+			return false
+		case field.Anonymous && value.Kind() == reflect.Struct:
+			if !in.fields(fields, value) {
+				return false
+			}
+		case !in.field(fields, field.Name, value):
+			return false
+		}
+	}
+	return true
+}
+
+// field annotates one field if its value is the one at the offset
+func (in *inspector) field(fields *[]InspectedField, name string, value reflect.Value) bool {
+	var length int
+	var match bool
+	switch {
+	case value.Type() == inspectedMacType:
+		// XDR opaque<6>, padded to 8 bytes
+		length = 8
+		match = len(in.data) >= length && bytes.Equal(in.data[:6], value.Bytes())
+	case value.Kind() == reflect.String:
+		length = 4 + (value.Len()+3)&^3
+		match = len(in.data) >= length && binary.BigEndian.Uint32(in.data) == uint32(value.Len()) && string(in.data[4:4+value.Len()]) == value.String()
+	case value.Kind() == reflect.Uint64:
+		length = 8
+		match = len(in.data) >= length && binary.BigEndian.Uint64(in.data) == value.Uint()
+	case value.Kind() == reflect.Uint32:
+		length = 4
+		match = len(in.data) >= length && binary.BigEndian.Uint32(in.data) == uint32(value.Uint())
+	case value.Kind() == reflect.Int32:
+		length = 4
+		match = len(in.data) >= length && int32(binary.BigEndian.Uint32(in.data)) == int32(value.Int())
+	case value.Kind() == reflect.Float32:
+		length = 4
+		match = len(in.data) >= length && math.Float32frombits(binary.BigEndian.Uint32(in.data)) == float32(value.Float())
+	}
+	if !match {
+		return false
+	}
+	*fields = append(*fields, InspectedField{Offset: in.offset(), Length: length, Name: name, Value: value.Interface()})
+	in.data = in.data[length:]
+	return true
+}
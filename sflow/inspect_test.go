@@ -0,0 +1,204 @@
+package sflow
+
+import (
+	"strings"
+	"testing"
+)
+
+// testFieldOffsets checks the names and offsets of fields
+func testFieldOffsets(t *testing.T, what string, fields []InspectedField, names []string, offsets []int) {
+	t.Helper()
+	if len(fields) != len(names) {
+		t.Fatalf("%s: fields %+v, want %v", what, fields, names)
+	}
+	for i, field := range fields {
+		if field.Name != names[i] || field.Offset != offsets[i] {
+			t.Errorf("%s: field %d %s at %d, want %s at %d", what, i, field.Name, field.Offset, names[i], offsets[i])
+		}
+	}
+}
+
+func TestInspectOffsets(t *testing.T) {
+	inspection := Inspect(testDatagram(
+		testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2),
+		testRecord(SFlowTypeExtendedUserFlow, 3, 5, 0x616c6963, 0x65000000, 3, 3, 0x626f6200),
+	))
+	if inspection.Err != nil || inspection.Length != 68+24+36 {
+		t.Fatalf("inspection %+v", inspection)
+	}
+	testFieldOffsets(t, "datagram", inspection.Fields,
+		[]string{"version", "agent address type", "agent address", "sub agent id", "sequence number", "uptime (ms)", "sample count"},
+		[]int{0, 4, 8, 12, 16, 20, 24})
+	if len(inspection.Samples) != 1 {
+		t.Fatalf("samples %+v", inspection.Samples)
+	}
+	s := inspection.Samples[0]
+	if s.Offset != 28 || s.Length != 8+32+24+36 || s.Format != SFlowTypeFlowSample || s.Skipped || s.Err != nil {
+		t.Fatalf("sample %+v", s)
+	}
+	testFieldOffsets(t, "sample", s.Fields,
+		[]string{"sample format", "sample length", "sequence number", "source id", "sampling rate", "sample pool", "drops", "input interface", "output interface", "record count"},
+		[]int{28, 32, 36, 40, 44, 48, 52, 56, 60, 64})
+	if s.Fields[3].Value != "0:10" {
+		t.Errorf("source id %v", s.Fields[3].Value)
+	}
+	if len(s.Records) != 2 {
+		t.Fatalf("records %+v", s.Records)
+	}
+
+	vlan := s.Records[0]
+	if vlan.Offset != 68 || vlan.Length != 24 || vlan.Format != uint32(SFlowTypeExtendedSwitchFlow) || vlan.Counter || vlan.Err != nil || vlan.Unread != 0 {
+		t.Fatalf("switch record %+v", vlan)
+	}
+	testFieldOffsets(t, "switch record", vlan.Fields,
+		[]string{"record format", "record length", "IncomingVLAN", "IncomingVLANPriority", "OutgoingVLAN", "OutgoingVLANPriority"},
+		[]int{68, 72, 76, 80, 84, 88})
+	if vlan.Fields[0].Value != "0:1001" || vlan.Fields[4].Value != uint32(20) {
+		t.Errorf("switch record fields %+v", vlan.Fields)
+	}
+
+	// the strings are padded to 4 bytes
+	user := s.Records[1]
+	if user.Offset != 92 || user.Length != 36 || user.Err != nil {
+		t.Fatalf("user record %+v", user)
+	}
+	testFieldOffsets(t, "user record", user.Fields,
+		[]string{"record format", "record length", "SourceCharSet", "SourceUserID", "DestinationCharSet", "DestinationUserID"},
+		[]int{92, 96, 100, 104, 116, 120})
+	if user.Fields[3].Value != "alice" || user.Fields[3].Length != 12 || user.Fields[5].Value != "bob" || user.Fields[5].Length != 8 {
+		t.Errorf("user record fields %+v", user.Fields)
+	}
+}
+
+// The fields are annotated up to the first one whose length the record
+// type does not tell
+func TestInspectRecordFields(t *testing.T) {
+	records := map[bool][]SFlowRecord{false: testFlowRecords(), true: testCounterRecords()}
+	for counter, records := range records {
+		for _, record := range records {
+			encoded := mustEncodeRecord(t, record)
+			inspected := (&inspector{data: encoded, end: len(encoded)}).record(counter)
+			if inspected.Err != nil || len(inspected.Fields) < 2 {
+				t.Errorf("%T: %+v", record, inspected)
+				continue
+			}
+			end := 0
+			for i, field := range inspected.Fields {
+				if field.Offset != end || field.Length < 4 {
+					t.Errorf("%T: field %d %s at %d, %d bytes, after %d", record, i, field.Name, field.Offset, field.Length, end)
+				}
+				end = field.Offset + field.Length
+			}
+		}
+	}
+
+	encoded := mustEncodeRecord(t, testFilled(SFlowGenericInterfaceCounters{}))
+	inspected := (&inspector{data: encoded, end: len(encoded)}).record(true)
+	if last := inspected.Fields[len(inspected.Fields)-1]; len(inspected.Fields) != 2+19 || last.Name != "IfPromiscuousMode" || last.Offset+last.Length != len(encoded) {
+		t.Fatalf("generic interface counters fields %+v", inspected.Fields)
+	}
+	encoded = mustEncodeRecord(t, testFilled(SFlowExtendedRouterFlowRecord{NextHop: testIPv6}))
+	if inspected := (&inspector{data: encoded, end: len(encoded)}).record(false); len(inspected.Fields) != 2 {
+		t.Fatalf("router record fields %+v", inspected.Fields)
+	}
+}
+
+func TestInspectRecordErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		record []byte
+		err    string
+		unread int
+	}{
+		{"unknown format", testRecord(SFlowFlowRecordType(999), 1, 2), "Unsupported flow record type: 999", 0},
+		{"truncated record", testRecord(SFlowTypeExtendedSwitchFlow, 10, 1), "truncated record", 0},
+		{"unread bytes", testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2, 7, 8), "", 8},
+	}
+	for _, test := range tests {
+		// the record is followed by one the sample keeps decoding
+		inspection := Inspect(testDatagram(test.record, testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2)))
+		if inspection.Err != nil || len(inspection.Samples) != 1 || len(inspection.Samples[0].Records) != 2 {
+			t.Errorf("%s: inspection %+v", test.name, inspection)
+			continue
+		}
+		s := inspection.Samples[0]
+		record := s.Records[0]
+		if test.err == "" {
+			if record.Err != nil || record.Unread != test.unread || s.Err != nil {
+				t.Errorf("%s: record %+v, sample error %v", test.name, record, s.Err)
+			}
+		} else {
+			if record.Err == nil || !strings.Contains(record.Err.Error(), test.err) || record.Record != nil {
+				t.Errorf("%s: record %+v, want error %q", test.name, record, test.err)
+			}
+			// Decode drops the sample
+			if s.Skipped || s.Err == nil || !strings.Contains(s.Err.Error(), "Decode drops the sample") {
+				t.Errorf("%s: sample error %v", test.name, s.Err)
+			}
+			if len(record.Fields) != 2 || record.Fields[1].Value != uint32(len(test.record)-8) {
+				t.Errorf("%s: fields %+v", test.name, record.Fields)
+			}
+		}
+		if next := s.Records[1]; next.Err != nil || next.Offset != record.Offset+len(test.record) {
+			t.Errorf("%s: next record %+v", test.name, next)
+		}
+	}
+}
+
+func TestInspectSkippedSamples(t *testing.T) {
+	expanded := xdr(uint32(SFlowTypeExpandedCounterSample), 20, 1, 0, 7, 1)
+	expanded = append(expanded, testCounterRecord(SFlowTypeOVSDPCounters, 1, 2, 3, 4, 5, 6)...)
+	expanded[7] = byte(len(expanded) - 8)
+	unknown := xdr(99, 4, 1)
+	datagram := append(xdr(5, 1, 0xc0000201, 0, 1, 1000, 2), append(expanded, unknown...)...)
+
+	inspection := Inspect(datagram)
+	if inspection.Err != nil || len(inspection.Samples) != 2 {
+		t.Fatalf("inspection %+v", inspection)
+	}
+	s := inspection.Samples[0]
+	if !s.Skipped || s.Err == nil || s.Err.Error() != "Decode skips the expanded counter samples" {
+		t.Fatalf("expanded sample %+v", s)
+	}
+	// the expanded sample is still annotated
+	testFieldOffsets(t, "expanded sample", s.Fields,
+		[]string{"sample format", "sample length", "sequence number", "source id type", "source id index", "record count"},
+		[]int{28, 32, 36, 40, 44, 48})
+	if len(s.Records) != 1 || s.Records[0].Err != nil || len(s.Records[0].Fields) != 8 {
+		t.Fatalf("records of the expanded sample %+v", s.Records)
+	}
+	if s := inspection.Samples[1]; !s.Skipped || s.Offset != 28+len(expanded) || s.Length != 12 || s.Err.Error() != "unsupported sample type 0:99" {
+		t.Fatalf("unknown sample %+v", s)
+	}
+
+	decoded, err := Decode(datagram)
+	if err != nil || len(decoded.CounterSamples) != 0 || len(decoded.SampleOrder) != 0 {
+		t.Fatalf("decoded %+v %v", decoded, err)
+	}
+}
+
+func TestInspectTrailingBytes(t *testing.T) {
+	// after the records of a sample
+	sample := xdr(1, 0x0000000a, 1024, 2048, 0, 10, 11, 0, 0xdeadbeef)
+	datagram := append(xdr(5, 1, 0xc0000201, 0, 1, 1000, 1, uint32(SFlowTypeFlowSample), uint32(len(sample))), sample...)
+	inspection := Inspect(datagram)
+	if inspection.Err != nil || len(inspection.Samples) != 1 || inspection.Samples[0].Skipped {
+		t.Fatalf("inspection %+v", inspection)
+	}
+	if err := inspection.Samples[0].Err; err == nil || err.Error() != "4 bytes after the 0 records" {
+		t.Fatalf("sample error %v", err)
+	}
+
+	// after the samples of the datagram
+	inspection = Inspect(append(testDatagram(testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2)), 0, 0, 0, 0, 0, 0, 0, 0))
+	if inspection.Err == nil || inspection.Err.Error() != "8 bytes after the 1 samples" || inspection.Samples[0].Err != nil {
+		t.Fatalf("inspection %+v", inspection)
+	}
+
+	// a sample longer than the datagram
+	truncated := testDatagram(testRecord(SFlowTypeExtendedSwitchFlow, 10, 1, 20, 2))
+	inspection = Inspect(truncated[:len(truncated)-4])
+	if inspection.Err == nil || !strings.Contains(inspection.Err.Error(), "sample at offset 0x1c is") {
+		t.Fatalf("inspection %+v", inspection)
+	}
+}
@@ -0,0 +1,4117 @@
+package sflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"net"
+	"strconv"
+)
+
+// SFlowRecord holds both flow sample records and counter sample records.
+// A Record is the structure that actually holds the sampled data
+// and / or counters. Every record embeds SFlowBaseFlowRecord or
+// SFlowBaseCounterRecord, which implement it. The records override the
+// format and name with those of their type, so that a record built in
+// code without its base record reports them too.
+type SFlowRecord interface {
+	RecordEnterprise() SFlowEnterpriseID
+	RecordFormat() uint32
+	// RecordLength is the length of the record data, in bytes
+	RecordLength() uint32
+	// RecordType names the record, e.g. "raw_packet" or
+	// "generic_interface_counters", it is the discriminator of the JSON
+	// encoding of SFlowRecords
+	RecordType() string
+}
+
+// SFlowRecords are the records of a sample. They encode to JSON as an
+// array of objects with a "recordType" member naming the record.
+type SFlowRecords []SFlowRecord
+
+func (records SFlowRecords) MarshalJSON() ([]byte, error) {
+	if records == nil {
+		return []byte("null"), nil
+	}
+	var buffer bytes.Buffer
+	buffer.WriteByte('[')
+	for i, record := range records {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		body, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		recordType, _ := json.Marshal(record.RecordType())
+		buffer.WriteString(`{"recordType":`)
+		buffer.Write(recordType)
+		if len(body) > 2 {
+			buffer.WriteByte(',')
+			buffer.Write(body[1:])
+		} else {
+			buffer.WriteByte('}')
+		}
+	}
+	buffer.WriteByte(']')
+	return buffer.Bytes(), nil
+}
+
+// RawPacket returns the first raw packet header record
+func (records SFlowRecords) RawPacket() (SFlowRawPacketFlowRecord, bool) {
+	for _, record := range records {
+		if rawPacket, ok := record.(SFlowRawPacketFlowRecord); ok {
+			return rawPacket, true
+		}
+	}
+	return SFlowRawPacketFlowRecord{}, false
+}
+
+// ExtendedSwitch returns the first extended switch record
+func (records SFlowRecords) ExtendedSwitch() (SFlowExtendedSwitchFlowRecord, bool) {
+	for _, record := range records {
+		if extendedSwitch, ok := record.(SFlowExtendedSwitchFlowRecord); ok {
+			return extendedSwitch, true
+		}
+	}
+	return SFlowExtendedSwitchFlowRecord{}, false
+}
+
+// Datagram is a decoded sFlow v5 datagram, it is also the gopacket layer
+// LayerTypeDatagram
+type Datagram struct {
+	layers.BaseLayer
+	DatagramVersion uint32
+	AgentAddress    net.IP
+	SubAgentID      uint32
+	SequenceNumber  uint32
+	AgentUptime     uint32
+	SampleCount     uint32
+	FlowSamples     []SFlowFlowSample
+	CounterSamples  []SFlowCounterSample
+	DiscardSamples  []SFlowDiscardSample
+	// SampleOrder is the kind (SFlowTypeFlowSample, SFlowTypeCounterSample
+	// or SFlowTypeDiscardSample) of the decoded samples in the order of the
+	// datagram
+	SampleOrder []SFlowSampleType
+}
+
+// EachSample calls flow, counter or discard for the samples in the order
+// of SampleOrder, then for the samples it does not list (all of them for
+// a datagram built in code) flow samples first. It stops at the first
+// error.
+func (d *Datagram) EachSample(flow func(SFlowFlowSample) error, counter func(SFlowCounterSample) error, discard func(SFlowDiscardSample) error) error {
+	var flows, counters, discards int
+	for _, kind := range d.SampleOrder {
+		var err error
+		switch {
+		case kind == SFlowTypeFlowSample && flows < len(d.FlowSamples):
+			err = flow(d.FlowSamples[flows])
+			flows++
+		case kind == SFlowTypeCounterSample && counters < len(d.CounterSamples):
+			err = counter(d.CounterSamples[counters])
+			counters++
+		case kind == SFlowTypeDiscardSample && discards < len(d.DiscardSamples):
+			err = discard(d.DiscardSamples[discards])
+			discards++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for ; flows < len(d.FlowSamples); flows++ {
+		if err := flow(d.FlowSamples[flows]); err != nil {
+			return err
+		}
+	}
+	for ; counters < len(d.CounterSamples); counters++ {
+		if err := counter(d.CounterSamples[counters]); err != nil {
+			return err
+		}
+	}
+	for ; discards < len(d.DiscardSamples); discards++ {
+		if err := discard(d.DiscardSamples[discards]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type SFlowFlowSample struct {
+	EnterpriseID          SFlowEnterpriseID
+	Format                SFlowSampleType
+	SampleLength          uint32
+	SequenceNumber        uint32
+	SourceIDClass         SFlowSourceFormat
+	SourceIDIndex         SFlowSourceValue
+	SamplingRate          uint32
+	SamplePool            uint32
+	Dropped               uint32
+	InputInterfaceFormat  uint32
+	InputInterface        uint32
+	OutputInterfaceFormat uint32
+	OutputInterface       uint32
+	RecordCount           uint32
+	Records               SFlowRecords
+}
+
+// RawPacket returns the first raw packet header record of the sample
+func (s SFlowFlowSample) RawPacket() (SFlowRawPacketFlowRecord, bool) {
+	return s.Records.RawPacket()
+}
+
+// ExtendedSwitch returns the extended switch record of the sample
+func (s SFlowFlowSample) ExtendedSwitch() (SFlowExtendedSwitchFlowRecord, bool) {
+	return s.Records.ExtendedSwitch()
+}
+
+type SFlowCounterSample struct {
+	EnterpriseID   SFlowEnterpriseID
+	Format         SFlowSampleType
+	SampleLength   uint32
+	SequenceNumber uint32
+	SourceIDClass  SFlowSourceFormat
+	SourceIDIndex  SFlowSourceValue
+	RecordCount    uint32
+	Records        SFlowRecords
+}
+
+// GenericInterfaceCounters returns all the generic interface counter
+// records of the sample
+func (s SFlowCounterSample) GenericInterfaceCounters() []SFlowGenericInterfaceCounters {
+	var counters []SFlowGenericInterfaceCounters
+	for _, record := range s.Records {
+		if r, ok := record.(SFlowGenericInterfaceCounters); ok {
+			counters = append(counters, r)
+		}
+	}
+	return counters
+}
+
+// EthernetCounters returns all the Ethernet interface counter records of
+// the sample
+func (s SFlowCounterSample) EthernetCounters() []SFlowEthernetCounters {
+	var counters []SFlowEthernetCounters
+	for _, record := range s.Records {
+		if r, ok := record.(SFlowEthernetCounters); ok {
+			counters = append(counters, r)
+		}
+	}
+	return counters
+}
+
+// OFPortCounters returns the OpenFlow port record of the sample
+func (s SFlowCounterSample) OFPortCounters() (SFlowOFPortCounters, bool) {
+	for _, record := range s.Records {
+		if r, ok := record.(SFlowOFPortCounters); ok {
+			return r, true
+		}
+	}
+	return SFlowOFPortCounters{}, false
+}
+
+// OFPortNameCounters returns the OpenFlow port name record of the sample
+func (s SFlowCounterSample) OFPortNameCounters() (SFlowOFPortNameCounters, bool) {
+	for _, record := range s.Records {
+		if r, ok := record.(SFlowOFPortNameCounters); ok {
+			return r, true
+		}
+	}
+	return SFlowOFPortNameCounters{}, false
+}
+
+// **************************************************
+//  Discarded Packet Sample
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  sample length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Sample Sequence Number         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |          Source ID Class / Source ID Index    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Drops                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Input Interface                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Output Interface               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Reason                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Record Count                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                 Flow Records                  /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowDiscardSample reports one packet the agent dropped, with the flow
+// records (usually the sampled header) describing it. Drops counts the
+// discards of the data source not reported because of rate limiting.
+// An interface is 0 when unknown.
+type SFlowDiscardSample struct {
+	EnterpriseID    SFlowEnterpriseID
+	Format          SFlowSampleType
+	SampleLength    uint32
+	SequenceNumber  uint32
+	SourceIDClass   SFlowSourceFormat
+	SourceIDIndex   SFlowSourceValue
+	Drops           uint32
+	InputInterface  uint32
+	OutputInterface uint32
+	Reason          SFlowDropReason
+	RecordCount     uint32
+	Records         SFlowRecords
+}
+
+// RawPacket returns the header record of the dropped packet
+func (s SFlowDiscardSample) RawPacket() (SFlowRawPacketFlowRecord, bool) {
+	return s.Records.RawPacket()
+}
+
+// SFlowDropReason tells why a packet was discarded: the ICMP unreachable
+// codes (0-15) or a forwarding pipeline reason (256 and above)
+type SFlowDropReason uint32
+
+const (
+	SFlowDropNetUnreachable               SFlowDropReason = 0
+	SFlowDropHostUnreachable              SFlowDropReason = 1
+	SFlowDropProtocolUnreachable          SFlowDropReason = 2
+	SFlowDropPortUnreachable              SFlowDropReason = 3
+	SFlowDropFragNeeded                   SFlowDropReason = 4
+	SFlowDropSrcRouteFailed               SFlowDropReason = 5
+	SFlowDropDstNetUnknown                SFlowDropReason = 6
+	SFlowDropDstHostUnknown               SFlowDropReason = 7
+	SFlowDropSrcHostIsolated              SFlowDropReason = 8
+	SFlowDropDstNetProhibited             SFlowDropReason = 9
+	SFlowDropDstHostProhibited            SFlowDropReason = 10
+	SFlowDropDstNetTOSUnreachable         SFlowDropReason = 11
+	SFlowDropDstHostTOSUnreachable        SFlowDropReason = 12
+	SFlowDropCommAdminProhibited          SFlowDropReason = 13
+	SFlowDropHostPrecedenceViolation      SFlowDropReason = 14
+	SFlowDropPrecedenceCutoff             SFlowDropReason = 15
+	SFlowDropUnknown                      SFlowDropReason = 256
+	SFlowDropTTLExceeded                  SFlowDropReason = 257
+	SFlowDropACL                          SFlowDropReason = 258
+	SFlowDropNoBufferSpace                SFlowDropReason = 259
+	SFlowDropRED                          SFlowDropReason = 260
+	SFlowDropTrafficShaping               SFlowDropReason = 261
+	SFlowDropPktTooBig                    SFlowDropReason = 262
+	SFlowDropSrcMACIsMulticast            SFlowDropReason = 263
+	SFlowDropVLANTagMismatch              SFlowDropReason = 264
+	SFlowDropIngressVLANFilter            SFlowDropReason = 265
+	SFlowDropIngressSpanningTreeFilter    SFlowDropReason = 266
+	SFlowDropPortListIsEmpty              SFlowDropReason = 267
+	SFlowDropPortLoopbackFilter           SFlowDropReason = 268
+	SFlowDropBlackholeRoute               SFlowDropReason = 269
+	SFlowDropNonIP                        SFlowDropReason = 270
+	SFlowDropUCDIPOverMCDMAC              SFlowDropReason = 271
+	SFlowDropDIPIsLoopback                SFlowDropReason = 272
+	SFlowDropSIPIsMC                      SFlowDropReason = 273
+	SFlowDropSIPIsLoopback                SFlowDropReason = 274
+	SFlowDropIPHeaderCorrupted            SFlowDropReason = 275
+	SFlowDropIPv4SIPIsLimitedBC           SFlowDropReason = 276
+	SFlowDropIPv6MCDIPReservedScope       SFlowDropReason = 277
+	SFlowDropIPv6MCDIPInterfaceLocalScope SFlowDropReason = 278
+	SFlowDropUnresolvedNeigh              SFlowDropReason = 279
+	SFlowDropMCReversePathForwarding      SFlowDropReason = 280
+	SFlowDropNonRoutablePacket            SFlowDropReason = 281
+	SFlowDropDecapError                   SFlowDropReason = 282
+	SFlowDropOverlaySMACIsMC              SFlowDropReason = 283
+	SFlowDropUnknownL2                    SFlowDropReason = 284
+	SFlowDropUnknownL3                    SFlowDropReason = 285
+	SFlowDropUnknownL3Exception           SFlowDropReason = 286
+	SFlowDropUnknownBuffer                SFlowDropReason = 287
+	SFlowDropUnknownTunnel                SFlowDropReason = 288
+	SFlowDropUnknownL4                    SFlowDropReason = 289
+	SFlowDropSIPIsUnspecified             SFlowDropReason = 290
+	SFlowDropMLAGPortIsolation            SFlowDropReason = 291
+	SFlowDropBlackholeARPNeigh            SFlowDropReason = 292
+	SFlowDropSrcMACIsDMAC                 SFlowDropReason = 293
+	SFlowDropDMACIsReserved               SFlowDropReason = 294
+	SFlowDropSIPIsClassE                  SFlowDropReason = 295
+	SFlowDropMCDMACMismatch               SFlowDropReason = 296
+	SFlowDropSIPIsDIP                     SFlowDropReason = 297
+	SFlowDropDIPIsLocalNetwork            SFlowDropReason = 298
+	SFlowDropDIPIsLinkLocal               SFlowDropReason = 299
+	SFlowDropOverlaySMACIsDMAC            SFlowDropReason = 300
+	SFlowDropEgressVLANFilter             SFlowDropReason = 301
+	SFlowDropUCReversePathForwarding      SFlowDropReason = 302
+	SFlowDropSplitHorizon                 SFlowDropReason = 303
+)
+
+func (r SFlowDropReason) String() string {
+	switch r {
+	case SFlowDropNetUnreachable:
+		return "net_unreachable"
+	case SFlowDropHostUnreachable:
+		return "host_unreachable"
+	case SFlowDropProtocolUnreachable:
+		return "protocol_unreachable"
+	case SFlowDropPortUnreachable:
+		return "port_unreachable"
+	case SFlowDropFragNeeded:
+		return "frag_needed"
+	case SFlowDropSrcRouteFailed:
+		return "src_route_failed"
+	case SFlowDropDstNetUnknown:
+		return "dst_net_unknown"
+	case SFlowDropDstHostUnknown:
+		return "dst_host_unknown"
+	case SFlowDropSrcHostIsolated:
+		return "src_host_isolated"
+	case SFlowDropDstNetProhibited:
+		return "dst_net_prohibited"
+	case SFlowDropDstHostProhibited:
+		return "dst_host_prohibited"
+	case SFlowDropDstNetTOSUnreachable:
+		return "dst_net_tos_unreachable"
+	case SFlowDropDstHostTOSUnreachable:
+		return "dst_host_tos_unreachable"
+	case SFlowDropCommAdminProhibited:
+		return "comm_admin_prohibited"
+	case SFlowDropHostPrecedenceViolation:
+		return "host_precedence_violation"
+	case SFlowDropPrecedenceCutoff:
+		return "precedence_cutoff"
+	case SFlowDropUnknown:
+		return "unknown"
+	case SFlowDropTTLExceeded:
+		return "ttl_exceeded"
+	case SFlowDropACL:
+		return "acl"
+	case SFlowDropNoBufferSpace:
+		return "no_buffer_space"
+	case SFlowDropRED:
+		return "red"
+	case SFlowDropTrafficShaping:
+		return "traffic_shaping"
+	case SFlowDropPktTooBig:
+		return "pkt_too_big"
+	case SFlowDropSrcMACIsMulticast:
+		return "src_mac_is_multicast"
+	case SFlowDropVLANTagMismatch:
+		return "vlan_tag_mismatch"
+	case SFlowDropIngressVLANFilter:
+		return "ingress_vlan_filter"
+	case SFlowDropIngressSpanningTreeFilter:
+		return "ingress_spanning_tree_filter"
+	case SFlowDropPortListIsEmpty:
+		return "port_list_is_empty"
+	case SFlowDropPortLoopbackFilter:
+		return "port_loopback_filter"
+	case SFlowDropBlackholeRoute:
+		return "blackhole_route"
+	case SFlowDropNonIP:
+		return "non_ip"
+	case SFlowDropUCDIPOverMCDMAC:
+		return "uc_dip_over_mc_dmac"
+	case SFlowDropDIPIsLoopback:
+		return "dip_is_loopback"
+	case SFlowDropSIPIsMC:
+		return "sip_is_mc"
+	case SFlowDropSIPIsLoopback:
+		return "sip_is_loopback"
+	case SFlowDropIPHeaderCorrupted:
+		return "ip_header_corrupted"
+	case SFlowDropIPv4SIPIsLimitedBC:
+		return "ipv4_sip_is_limited_bc"
+	case SFlowDropIPv6MCDIPReservedScope:
+		return "ipv6_mc_dip_reserved_scope"
+	case SFlowDropIPv6MCDIPInterfaceLocalScope:
+		return "ipv6_mc_dip_interface_local_scope"
+	case SFlowDropUnresolvedNeigh:
+		return "unresolved_neigh"
+	case SFlowDropMCReversePathForwarding:
+		return "mc_reverse_path_forwarding"
+	case SFlowDropNonRoutablePacket:
+		return "non_routable_packet"
+	case SFlowDropDecapError:
+		return "decap_error"
+	case SFlowDropOverlaySMACIsMC:
+		return "overlay_smac_is_mc"
+	case SFlowDropUnknownL2:
+		return "unknown_l2"
+	case SFlowDropUnknownL3:
+		return "unknown_l3"
+	case SFlowDropUnknownL3Exception:
+		return "unknown_l3_exception"
+	case SFlowDropUnknownBuffer:
+		return "unknown_buffer"
+	case SFlowDropUnknownTunnel:
+		return "unknown_tunnel"
+	case SFlowDropUnknownL4:
+		return "unknown_l4"
+	case SFlowDropSIPIsUnspecified:
+		return "sip_is_unspecified"
+	case SFlowDropMLAGPortIsolation:
+		return "mlag_port_isolation"
+	case SFlowDropBlackholeARPNeigh:
+		return "blackhole_arp_neigh"
+	case SFlowDropSrcMACIsDMAC:
+		return "src_mac_is_dmac"
+	case SFlowDropDMACIsReserved:
+		return "dmac_is_reserved"
+	case SFlowDropSIPIsClassE:
+		return "sip_is_class_e"
+	case SFlowDropMCDMACMismatch:
+		return "mc_dmac_mismatch"
+	case SFlowDropSIPIsDIP:
+		return "sip_is_dip"
+	case SFlowDropDIPIsLocalNetwork:
+		return "dip_is_local_network"
+	case SFlowDropDIPIsLinkLocal:
+		return "dip_is_link_local"
+	case SFlowDropOverlaySMACIsDMAC:
+		return "overlay_smac_is_dmac"
+	case SFlowDropEgressVLANFilter:
+		return "egress_vlan_filter"
+	case SFlowDropUCReversePathForwarding:
+		return "uc_reverse_path_forwarding"
+	case SFlowDropSplitHorizon:
+		return "split_horizon"
+	default:
+		return "reason_" + strconv.Itoa(int(r))
+	}
+}
+
+// SFlowDataSource encodes an 8-bit SFlowSourceFormat in its most significant
+// 8 bits, and an SFlowSourceValue in its least significant 24 bits.
+// These types and values define the meaning of the inteface information
+// presented in the sample metadata.
+type SFlowDataSource int32
+
+type SFlowDataFormat uint32
+
+type SFlowEnterpriseID uint32
+
+const (
+	SFlowStandard SFlowEnterpriseID = 0
+)
+
+/*
+SFLFLOW_SAMPLE = 1,               enterprise = 0 : format = 1
+SFLCOUNTERS_SAMPLE = 2,           enterprise = 0 : format = 2
+SFLFLOW_SAMPLE_EXPANDED = 3,      enterprise = 0 : format = 3
+SFLCOUNTERS_SAMPLE_EXPANDED = 4,  enterprise = 0 : format = 4
+SFLEVENT_DISCARDED_PACKET = 5,    enterprise = 0 : format = 5
+*/
+type SFlowSampleType uint32
+
+const (
+	SFlowTypeFlowSample            SFlowSampleType = 1
+	SFlowTypeCounterSample         SFlowSampleType = 2
+	SFlowTypeExpandedFlowSample    SFlowSampleType = 3
+	SFlowTypeExpandedCounterSample SFlowSampleType = 4
+	SFlowTypeDiscardSample         SFlowSampleType = 5
+)
+
+type SFlowSourceFormat uint32
+type SFlowSourceValue uint32
+type SFlowDataSourceExpanded struct {
+	SourceIDClass SFlowSourceFormat
+	SourceIDIndex SFlowSourceValue
+}
+
+// *********************************************************************
+//  SFLOW FLOW
+// *********************************************************************
+
+type SFlowFlowDataFormat uint32
+
+// SFlowBaseFlowRecord holds the fields common to all records
+// of type SFlowFlowRecordType
+type SFlowBaseFlowRecord struct {
+	EnterpriseID   SFlowEnterpriseID
+	Format         SFlowFlowRecordType
+	FlowDataLength uint32
+}
+
+func (r SFlowBaseFlowRecord) RecordEnterprise() SFlowEnterpriseID { return r.EnterpriseID }
+func (r SFlowBaseFlowRecord) RecordFormat() uint32                { return uint32(r.Format) }
+func (r SFlowBaseFlowRecord) RecordLength() uint32                { return r.FlowDataLength }
+
+func (r SFlowBaseFlowRecord) RecordType() string { return flowRecordType(r.EnterpriseID, r.Format) }
+
+// SFlowFlowRecordType denotes what kind of Flow Record is
+// represented. See RFC 3176
+type SFlowFlowRecordType uint32
+
+const (
+	SFlowTypeRawPacketFlow                  SFlowFlowRecordType = 1
+	SFlowTypeEthernetFrameFlow              SFlowFlowRecordType = 2
+	SFlowTypeIpv4Flow                       SFlowFlowRecordType = 3
+	SFlowTypeIpv6Flow                       SFlowFlowRecordType = 4
+	SFlowTypeExtendedSwitchFlow             SFlowFlowRecordType = 1001
+	SFlowTypeExtendedRouterFlow             SFlowFlowRecordType = 1002
+	SFlowTypeExtendedGatewayFlow            SFlowFlowRecordType = 1003
+	SFlowTypeExtendedUserFlow               SFlowFlowRecordType = 1004
+	SFlowTypeExtendedUrlFlow                SFlowFlowRecordType = 1005
+	SFlowTypeExtendedMlpsFlow               SFlowFlowRecordType = 1006
+	SFlowTypeExtendedNatFlow                SFlowFlowRecordType = 1007
+	SFlowTypeExtendedMlpsTunnelFlow         SFlowFlowRecordType = 1008
+	SFlowTypeExtendedMlpsVcFlow             SFlowFlowRecordType = 1009
+	SFlowTypeExtendedMlpsFecFlow            SFlowFlowRecordType = 1010
+	SFlowTypeExtendedMlpsLvpFecFlow         SFlowFlowRecordType = 1011
+	SFlowTypeExtendedVlanFlow               SFlowFlowRecordType = 1012
+	SFlowTypeExtended80211PayloadFlow       SFlowFlowRecordType = 1013
+	SFlowTypeExtended80211RxFlow            SFlowFlowRecordType = 1014
+	SFlowTypeExtended80211TxFlow            SFlowFlowRecordType = 1015
+	SFlowTypeExtended80211AggregationFlow   SFlowFlowRecordType = 1016
+	SFlowTypeExtendedIpv4TunnelEgressFlow   SFlowFlowRecordType = 1023
+	SFlowTypeExtendedIpv4TunnelIngressFlow  SFlowFlowRecordType = 1024
+	SFlowTypeExtendedIpv6TunnelEgressFlow   SFlowFlowRecordType = 1025
+	SFlowTypeExtendedIpv6TunnelIngressFlow  SFlowFlowRecordType = 1026
+	SFlowTypeExtendedDecapsulateEgressFlow  SFlowFlowRecordType = 1027
+	SFlowTypeExtendedDecapsulateIngressFlow SFlowFlowRecordType = 1028
+	SFlowTypeExtendedVniEgressFlow          SFlowFlowRecordType = 1029
+	SFlowTypeExtendedVniIngressFlow         SFlowFlowRecordType = 1030
+	SFlowTypeExtendedEgressQueueFlow        SFlowFlowRecordType = 1036
+	SFlowTypeExtendedTransitFlow            SFlowFlowRecordType = 1039
+	SFlowTypeExtendedQueueFlow              SFlowFlowRecordType = 1040
+	SFlowTypeExtendedFunctionFlow           SFlowFlowRecordType = 1041
+	SFlowTypeExtendedSocketIpv4Flow         SFlowFlowRecordType = 2100
+	SFlowTypeExtendedSocketIpv6Flow         SFlowFlowRecordType = 2101
+	SFlowTypeMemcacheFlow                   SFlowFlowRecordType = 2200
+	SFlowTypeHTTPFlow                       SFlowFlowRecordType = 2201
+	SFlowTypeAppOperationFlow               SFlowFlowRecordType = 2202
+	SFlowTypeAppParentContextFlow           SFlowFlowRecordType = 2203
+	SFlowTypeAppInitiatorFlow               SFlowFlowRecordType = 2204
+	SFlowTypeAppTargetFlow                  SFlowFlowRecordType = 2205
+	SFlowTypeHTTP2Flow                      SFlowFlowRecordType = 2206
+	SFlowTypeExtendedTCPInfoFlow            SFlowFlowRecordType = 2209
+)
+
+// *********************************************************************
+//  SFLOW COUNTER
+// *********************************************************************
+
+/*
+SFLCOUNTERS_GENERIC      = 1,
+SFLCOUNTERS_ETHERNET     = 2,
+SFLCOUNTERS_TOKENRING    = 3,
+SFLCOUNTERS_VG           = 4,
+SFLCOUNTERS_VLAN         = 5,
+SFLCOUNTERS_80211        = 6,
+SFLCOUNTERS_LACP         = 7,
+SFLCOUNTERS_SFP          = 10,
+SFLCOUNTERS_PROCESSOR    = 1001,
+SFLCOUNTERS_RADIO        = 1002,
+SFLCOUNTERS_OFPORT       = 1004,
+SFLCOUNTERS_PORTNAME     = 1005,
+SFLCOUNTERS_HOST_HID     = 2000, // host id
+SFLCOUNTERS_ADAPTORS     = 2001, // host adaptors
+SFLCOUNTERS_HOST_PAR     = 2002, // host parent
+SFLCOUNTERS_HOST_CPU     = 2003, // host cpu
+SFLCOUNTERS_HOST_MEM     = 2004, // host memory
+SFLCOUNTERS_HOST_DSK     = 2005, // host storage I/O
+SFLCOUNTERS_HOST_NIO     = 2006, // host network I/O
+SFLCOUNTERS_HOST_IP      = 2007,
+SFLCOUNTERS_HOST_ICMP    = 2008,
+SFLCOUNTERS_HOST_TCP     = 2009,
+SFLCOUNTERS_HOST_UDP     = 2010,
+SFLCOUNTERS_HOST_VRT_NODE = 2100, // host virt node
+SFLCOUNTERS_HOST_VRT_CPU  = 2101, // host virt cpu
+SFLCOUNTERS_HOST_VRT_MEM  = 2102, // host virt mem
+SFLCOUNTERS_HOST_VRT_DSK  = 2103, // host virt storage
+SFLCOUNTERS_HOST_VRT_NIO  = 2104, // host virt network I/O
+SFLCOUNTERS_JVM           = 2105, // java runtime
+SFLCOUNTERS_JMX           = 2106, // java JMX stats
+SFLCOUNTERS_MEMCACHE      = 2200, // memcached (deprecated)
+SFLCOUNTERS_HTTP          = 2201, // http
+SFLCOUNTERS_APP           = 2202,
+SFLCOUNTERS_APP_RESOURCE  = 2203,
+SFLCOUNTERS_MEMCACHE2     = 2204, // memcached
+SFLCOUNTERS_VDI           = 2205,
+SFLCOUNTERS_APP_WORKERS   = 2206,
+SFLCOUNTERS_OVSDP         = 2207,
+SFLCOUNTERS_HOST_GPU_NVML = (5703 << 12) + 1, // = 23359489
+SFLCOUNTERS_BCM_TABLES    = (4413 << 12) + 3,
+*/
+type SFlowCounterDataFormat uint32
+type SFlowCounterRecordType uint32
+
+const (
+	SFlowTypeGenericInterfaceCounters   SFlowCounterRecordType = 1
+	SFlowTypeEthernetInterfaceCounters  SFlowCounterRecordType = 2
+	SFlowTypeTokenRingInterfaceCounters SFlowCounterRecordType = 3
+	SFlowType100BaseVGInterfaceCounters SFlowCounterRecordType = 4
+	SFlowTypeVLANCounters               SFlowCounterRecordType = 5
+	SFlowType80211Counters              SFlowCounterRecordType = 6
+	SFlowTypeLACPCounters               SFlowCounterRecordType = 7
+	SFlowTypeSFPCounters                SFlowCounterRecordType = 10
+	SFlowTypeProcessorCounters          SFlowCounterRecordType = 1001
+	SFlowTypeRadioCounters              SFlowCounterRecordType = 1002
+	SFlowTypeOFPortCounter              SFlowCounterRecordType = 1004
+	SFlowTypeOFPortNameCounter          SFlowCounterRecordType = 1005
+	SFlowTypeHostDescrCounters          SFlowCounterRecordType = 2000
+	SFlowTypeHostAdaptersCounters       SFlowCounterRecordType = 2001
+	SFlowTypeHostParentCounters         SFlowCounterRecordType = 2002
+	SFlowTypeHostCPUCounters            SFlowCounterRecordType = 2003
+	SFlowTypeHostMemoryCounters         SFlowCounterRecordType = 2004
+	SFlowTypeHostDiskCounters           SFlowCounterRecordType = 2005
+	SFlowTypeHostNetIOCounters          SFlowCounterRecordType = 2006
+	SFlowTypeHostIPCounters             SFlowCounterRecordType = 2007
+	SFlowTypeHostICMPCounters           SFlowCounterRecordType = 2008
+	SFlowTypeHostTCPCounters            SFlowCounterRecordType = 2009
+	SFlowTypeHostUDPCounters            SFlowCounterRecordType = 2010
+	SFlowTypeVirtNodeCounters           SFlowCounterRecordType = 2100
+	SFlowTypeVirtCPUCounters            SFlowCounterRecordType = 2101
+	SFlowTypeVirtMemoryCounters         SFlowCounterRecordType = 2102
+	SFlowTypeVirtDiskCounters           SFlowCounterRecordType = 2103
+	SFlowTypeVirtNetIOCounters          SFlowCounterRecordType = 2104
+	SFlowTypeMemcacheLegacyCounters     SFlowCounterRecordType = 2200
+	SFlowTypeHTTPCounters               SFlowCounterRecordType = 2201
+	SFlowTypeAppCounters                SFlowCounterRecordType = 2202
+	SFlowTypeAppResourcesCounters       SFlowCounterRecordType = 2203
+	SFlowTypeMemcacheCounters           SFlowCounterRecordType = 2204
+	SFlowTypeVDICounters                SFlowCounterRecordType = 2205
+	SFlowTypeAppWorkersCounters         SFlowCounterRecordType = 2206
+	SFlowTypeOVSDPCounters              SFlowCounterRecordType = 2207
+)
+
+//-------------------------- SFlowRawPacketFlowRecord --------------------//
+
+/*
+SFlowRawPacketFlowRecords hold information about a sampled
+packet grabbed as it transited the agent. This is
+perhaps the most useful and interesting record type,
+as it holds the headers of the sampled packet and
+can be used to build up a complete picture of the
+traffic patterns on a network.
+The raw packet header is sent back into gopacket for
+decoding, and the resulting gopackt.Packet is stored
+in the Header member
+*/
+type SFlowRawPacketFlowRecord struct {
+	SFlowBaseFlowRecord
+	HeaderProtocol SFlowRawHeaderProtocol
+	FrameLength    uint32
+	PayloadRemoved uint32
+	HeaderLength   uint32
+	// Header is the sampled header decoded according to HeaderProtocol, nil
+	// when the protocol is not supported. ParsedHeader has the details.
+	Header       gopacket.Packet
+	ParsedHeader SFlowRawHeader
+}
+
+// Raw packet record types have the following structure:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Header Protocol               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Frame Length                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Payload Removed               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Header Length                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                     Header                    \
+//  \                                               \
+//  \                                               \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowRawHeaderProtocol uint32
+
+const (
+	SFlowProtoEthernet   SFlowRawHeaderProtocol = 1
+	SFlowProtoISO88024   SFlowRawHeaderProtocol = 2
+	SFlowProtoISO88025   SFlowRawHeaderProtocol = 3
+	SFlowProtoFDDI       SFlowRawHeaderProtocol = 4
+	SFlowProtoFrameRelay SFlowRawHeaderProtocol = 5
+	SFlowProtoX25        SFlowRawHeaderProtocol = 6
+	SFlowProtoPPP        SFlowRawHeaderProtocol = 7
+	SFlowProtoSMDS       SFlowRawHeaderProtocol = 8
+	SFlowProtoAAL5       SFlowRawHeaderProtocol = 9
+	SFlowProtoAAL5_IP    SFlowRawHeaderProtocol = 10 /* e.g. Cisco AAL5 mux */
+	SFlowProtoIPv4       SFlowRawHeaderProtocol = 11
+	SFlowProtoIPv6       SFlowRawHeaderProtocol = 12
+	SFlowProtoMPLS       SFlowRawHeaderProtocol = 13
+	SFlowProtoPOS        SFlowRawHeaderProtocol = 14 /* RFC 1662, 2615 */
+	SFlowProto80211MAC   SFlowRawHeaderProtocol = 15 /* 802.11 PDU */
+	SFlowProto80211AMPDU SFlowRawHeaderProtocol = 16 /* 802.11n Aggregated MPDU */
+	SFlowProto80211AMSDU SFlowRawHeaderProtocol = 17 /* 802.11n Aggregated MSDU */
+)
+
+// LayerType returns the gopacket layer a header of this protocol starts
+// with. ok is false for protocols gopacket has no decoder for.
+func (sfhp SFlowRawHeaderProtocol) LayerType() (layerType gopacket.LayerType, ok bool) {
+	switch sfhp {
+	case SFlowProtoEthernet:
+		return layers.LayerTypeEthernet, true
+	case SFlowProtoFDDI:
+		return layers.LayerTypeFDDI, true
+	case SFlowProtoPPP, SFlowProtoPOS:
+		// POS carries PPP in HDLC-like framing, the PPP decoder skips the 0xff03 prefix
+		return layers.LayerTypePPP, true
+	case SFlowProtoIPv4:
+		return layers.LayerTypeIPv4, true
+	case SFlowProtoIPv6:
+		return layers.LayerTypeIPv6, true
+	case SFlowProtoMPLS:
+		return layers.LayerTypeMPLS, true
+	case SFlowProto80211MAC:
+		return layers.LayerTypeDot11, true
+	}
+	return gopacket.LayerTypeZero, false
+}
+
+// SFlowRawHeader is the typed result of decoding the header of a raw
+// packet flow record with the decoder matching its HeaderProtocol
+type SFlowRawHeader struct {
+	Protocol SFlowRawHeaderProtocol
+	// Supported is false when gopacket has no decoder for Protocol, Packet
+	// is nil then and only Data is available
+	Supported bool
+	Data      []byte
+	Packet    gopacket.Packet `json:"-"`
+}
+
+func (h SFlowRawHeader) layer(layerType gopacket.LayerType) gopacket.Layer {
+	if h.Packet == nil {
+		return nil
+	}
+	return h.Packet.Layer(layerType)
+}
+
+// Ethernet returns the outermost Ethernet layer of the header, if any
+func (h SFlowRawHeader) Ethernet() *layers.Ethernet {
+	l, _ := h.layer(layers.LayerTypeEthernet).(*layers.Ethernet)
+	return l
+}
+
+// Dot11 returns the 802.11 layer of the header, if any
+func (h SFlowRawHeader) Dot11() *layers.Dot11 {
+	l, _ := h.layer(layers.LayerTypeDot11).(*layers.Dot11)
+	return l
+}
+
+// MPLS returns the outermost MPLS label of the header, if any
+func (h SFlowRawHeader) MPLS() *layers.MPLS {
+	l, _ := h.layer(layers.LayerTypeMPLS).(*layers.MPLS)
+	return l
+}
+
+// IPv4 returns the outermost IPv4 layer of the header, if any
+func (h SFlowRawHeader) IPv4() *layers.IPv4 {
+	l, _ := h.layer(layers.LayerTypeIPv4).(*layers.IPv4)
+	return l
+}
+
+// IPv6 returns the outermost IPv6 layer of the header, if any
+func (h SFlowRawHeader) IPv6() *layers.IPv6 {
+	l, _ := h.layer(layers.LayerTypeIPv6).(*layers.IPv6)
+	return l
+}
+
+// TCP returns the outermost TCP layer of the header, if any
+func (h SFlowRawHeader) TCP() *layers.TCP {
+	l, _ := h.layer(layers.LayerTypeTCP).(*layers.TCP)
+	return l
+}
+
+// UDP returns the outermost UDP layer of the header, if any
+func (h SFlowRawHeader) UDP() *layers.UDP {
+	l, _ := h.layer(layers.LayerTypeUDP).(*layers.UDP)
+	return l
+}
+
+//-------------------------- SFlowEthernetFrameFlowRecord --------------------//
+
+// SFlowEthernetFrameFlowRecord is the summary of a sampled Ethernet
+// frame some agents send instead of (or next to) the raw header
+type SFlowEthernetFrameFlowRecord struct {
+	SFlowBaseFlowRecord
+	// The length of the MAC packet received on the network, excluding
+	// lower layer encapsulations and framing bits but including FCS octets
+	FrameLength uint32
+	SrcMac      net.HardwareAddr
+	DstMac      net.HardwareAddr
+	// Ethernet packet type
+	Type uint32
+}
+
+// Ethernet frame flow records have the following structure:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Frame Length                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |       Source Mac Address (padded to 8)        |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |    Destination Mac Address (padded to 8)      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               Ethernet Packet Type            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+//-------------------------- Extended User Flow Record --------------------//
+
+// **************************************************
+//  Extended User Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Source Character Set           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Source User Id                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              Destination Character Set        |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               Destination User ID             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowExtendedUserFlow struct {
+	SFlowBaseFlowRecord
+	SourceCharSet      SFlowCharSet
+	SourceUserID       string
+	DestinationCharSet SFlowCharSet
+	DestinationUserID  string
+}
+
+type SFlowCharSet uint32
+
+const (
+	SFlowCSunknown                 SFlowCharSet = 2
+	SFlowCSASCII                   SFlowCharSet = 3
+	SFlowCSISOLatin1               SFlowCharSet = 4
+	SFlowCSISOLatin2               SFlowCharSet = 5
+	SFlowCSISOLatin3               SFlowCharSet = 6
+	SFlowCSISOLatin4               SFlowCharSet = 7
+	SFlowCSISOLatinCyrillic        SFlowCharSet = 8
+	SFlowCSISOLatinArabic          SFlowCharSet = 9
+	SFlowCSISOLatinGreek           SFlowCharSet = 10
+	SFlowCSISOLatinHebrew          SFlowCharSet = 11
+	SFlowCSISOLatin5               SFlowCharSet = 12
+	SFlowCSISOLatin6               SFlowCharSet = 13
+	SFlowCSISOTextComm             SFlowCharSet = 14
+	SFlowCSHalfWidthKatakana       SFlowCharSet = 15
+	SFlowCSJISEncoding             SFlowCharSet = 16
+	SFlowCSShiftJIS                SFlowCharSet = 17
+	SFlowCSEUCPkdFmtJapanese       SFlowCharSet = 18
+	SFlowCSEUCFixWidJapanese       SFlowCharSet = 19
+	SFlowCSISO4UnitedKingdom       SFlowCharSet = 20
+	SFlowCSISO11SwedishForNames    SFlowCharSet = 21
+	SFlowCSISO15Italian            SFlowCharSet = 22
+	SFlowCSISO17Spanish            SFlowCharSet = 23
+	SFlowCSISO21German             SFlowCharSet = 24
+	SFlowCSISO60DanishNorwegian    SFlowCharSet = 25
+	SFlowCSISO69French             SFlowCharSet = 26
+	SFlowCSISO10646UTF1            SFlowCharSet = 27
+	SFlowCSISO646basic1983         SFlowCharSet = 28
+	SFlowCSINVARIANT               SFlowCharSet = 29
+	SFlowCSISO2IntlRefVersion      SFlowCharSet = 30
+	SFlowCSNATSSEFI                SFlowCharSet = 31
+	SFlowCSNATSSEFIADD             SFlowCharSet = 32
+	SFlowCSNATSDANO                SFlowCharSet = 33
+	SFlowCSNATSDANOADD             SFlowCharSet = 34
+	SFlowCSISO10Swedish            SFlowCharSet = 35
+	SFlowCSKSC56011987             SFlowCharSet = 36
+	SFlowCSISO2022KR               SFlowCharSet = 37
+	SFlowCSEUCKR                   SFlowCharSet = 38
+	SFlowCSISO2022JP               SFlowCharSet = 39
+	SFlowCSISO2022JP2              SFlowCharSet = 40
+	SFlowCSISO13JISC6220jp         SFlowCharSet = 41
+	SFlowCSISO14JISC6220ro         SFlowCharSet = 42
+	SFlowCSISO16Portuguese         SFlowCharSet = 43
+	SFlowCSISO18Greek7Old          SFlowCharSet = 44
+	SFlowCSISO19LatinGreek         SFlowCharSet = 45
+	SFlowCSISO25French             SFlowCharSet = 46
+	SFlowCSISO27LatinGreek1        SFlowCharSet = 47
+	SFlowCSISO5427Cyrillic         SFlowCharSet = 48
+	SFlowCSISO42JISC62261978       SFlowCharSet = 49
+	SFlowCSISO47BSViewdata         SFlowCharSet = 50
+	SFlowCSISO49INIS               SFlowCharSet = 51
+	SFlowCSISO50INIS8              SFlowCharSet = 52
+	SFlowCSISO51INISCyrillic       SFlowCharSet = 53
+	SFlowCSISO54271981             SFlowCharSet = 54
+	SFlowCSISO5428Greek            SFlowCharSet = 55
+	SFlowCSISO57GB1988             SFlowCharSet = 56
+	SFlowCSISO58GB231280           SFlowCharSet = 57
+	SFlowCSISO61Norwegian2         SFlowCharSet = 58
+	SFlowCSISO70VideotexSupp1      SFlowCharSet = 59
+	SFlowCSISO84Portuguese2        SFlowCharSet = 60
+	SFlowCSISO85Spanish2           SFlowCharSet = 61
+	SFlowCSISO86Hungarian          SFlowCharSet = 62
+	SFlowCSISO87JISX0208           SFlowCharSet = 63
+	SFlowCSISO88Greek7             SFlowCharSet = 64
+	SFlowCSISO89ASMO449            SFlowCharSet = 65
+	SFlowCSISO90                   SFlowCharSet = 66
+	SFlowCSISO91JISC62291984a      SFlowCharSet = 67
+	SFlowCSISO92JISC62991984b      SFlowCharSet = 68
+	SFlowCSISO93JIS62291984badd    SFlowCharSet = 69
+	SFlowCSISO94JIS62291984hand    SFlowCharSet = 70
+	SFlowCSISO95JIS62291984handadd SFlowCharSet = 71
+	SFlowCSISO96JISC62291984kana   SFlowCharSet = 72
+	SFlowCSISO2033                 SFlowCharSet = 73
+	SFlowCSISO99NAPLPS             SFlowCharSet = 74
+	SFlowCSISO102T617bit           SFlowCharSet = 75
+	SFlowCSISO103T618bit           SFlowCharSet = 76
+	SFlowCSISO111ECMACyrillic      SFlowCharSet = 77
+	SFlowCSa71                     SFlowCharSet = 78
+	SFlowCSa72                     SFlowCharSet = 79
+	SFlowCSISO123CSAZ24341985gr    SFlowCharSet = 80
+	SFlowCSISO88596E               SFlowCharSet = 81
+	SFlowCSISO88596I               SFlowCharSet = 82
+	SFlowCSISO128T101G2            SFlowCharSet = 83
+	SFlowCSISO88598E               SFlowCharSet = 84
+	SFlowCSISO88598I               SFlowCharSet = 85
+	SFlowCSISO139CSN369103         SFlowCharSet = 86
+	SFlowCSISO141JUSIB1002         SFlowCharSet = 87
+	SFlowCSISO143IECP271           SFlowCharSet = 88
+	SFlowCSISO146Serbian           SFlowCharSet = 89
+	SFlowCSISO147Macedonian        SFlowCharSet = 90
+	SFlowCSISO150                  SFlowCharSet = 91
+	SFlowCSISO151Cuba              SFlowCharSet = 92
+	SFlowCSISO6937Add              SFlowCharSet = 93
+	SFlowCSISO153GOST1976874       SFlowCharSet = 94
+	SFlowCSISO8859Supp             SFlowCharSet = 95
+	SFlowCSISO10367Box             SFlowCharSet = 96
+	SFlowCSISO158Lap               SFlowCharSet = 97
+	SFlowCSISO159JISX02121990      SFlowCharSet = 98
+	SFlowCSISO646Danish            SFlowCharSet = 99
+	SFlowCSUSDK                    SFlowCharSet = 100
+	SFlowCSDKUS                    SFlowCharSet = 101
+	SFlowCSKSC5636                 SFlowCharSet = 102
+	SFlowCSUnicode11UTF7           SFlowCharSet = 103
+	SFlowCSISO2022CN               SFlowCharSet = 104
+	SFlowCSISO2022CNEXT            SFlowCharSet = 105
+	SFlowCSUTF8                    SFlowCharSet = 106
+	SFlowCSISO885913               SFlowCharSet = 109
+	SFlowCSISO885914               SFlowCharSet = 110
+	SFlowCSISO885915               SFlowCharSet = 111
+	SFlowCSISO885916               SFlowCharSet = 112
+	SFlowCSGBK                     SFlowCharSet = 113
+	SFlowCSGB18030                 SFlowCharSet = 114
+	SFlowCSOSDEBCDICDF0415         SFlowCharSet = 115
+	SFlowCSOSDEBCDICDF03IRV        SFlowCharSet = 116
+	SFlowCSOSDEBCDICDF041          SFlowCharSet = 117
+	SFlowCSISO115481               SFlowCharSet = 118
+	SFlowCSKZ1048                  SFlowCharSet = 119
+	SFlowCSUnicode                 SFlowCharSet = 1000
+	SFlowCSUCS4                    SFlowCharSet = 1001
+	SFlowCSUnicodeASCII            SFlowCharSet = 1002
+	SFlowCSUnicodeLatin1           SFlowCharSet = 1003
+	SFlowCSUnicodeJapanese         SFlowCharSet = 1004
+	SFlowCSUnicodeIBM1261          SFlowCharSet = 1005
+	SFlowCSUnicodeIBM1268          SFlowCharSet = 1006
+	SFlowCSUnicodeIBM1276          SFlowCharSet = 1007
+	SFlowCSUnicodeIBM1264          SFlowCharSet = 1008
+	SFlowCSUnicodeIBM1265          SFlowCharSet = 1009
+	SFlowCSUnicode11               SFlowCharSet = 1010
+	SFlowCSSCSU                    SFlowCharSet = 1011
+	SFlowCSUTF7                    SFlowCharSet = 1012
+	SFlowCSUTF16BE                 SFlowCharSet = 1013
+	SFlowCSUTF16LE                 SFlowCharSet = 1014
+	SFlowCSUTF16                   SFlowCharSet = 1015
+	SFlowCSCESU8                   SFlowCharSet = 1016
+	SFlowCSUTF32                   SFlowCharSet = 1017
+	SFlowCSUTF32BE                 SFlowCharSet = 1018
+	SFlowCSUTF32LE                 SFlowCharSet = 1019
+	SFlowCSBOCU1                   SFlowCharSet = 1020
+	SFlowCSWindows30Latin1         SFlowCharSet = 2000
+	SFlowCSWindows31Latin1         SFlowCharSet = 2001
+	SFlowCSWindows31Latin2         SFlowCharSet = 2002
+	SFlowCSWindows31Latin5         SFlowCharSet = 2003
+	SFlowCSHPRoman8                SFlowCharSet = 2004
+	SFlowCSAdobeStandardEncoding   SFlowCharSet = 2005
+	SFlowCSVenturaUS               SFlowCharSet = 2006
+	SFlowCSVenturaInternational    SFlowCharSet = 2007
+	SFlowCSDECMCS                  SFlowCharSet = 2008
+	SFlowCSPC850Multilingual       SFlowCharSet = 2009
+	SFlowCSPCp852                  SFlowCharSet = 2010
+	SFlowCSPC8CodePage437          SFlowCharSet = 2011
+	SFlowCSPC8DanishNorwegian      SFlowCharSet = 2012
+	SFlowCSPC862LatinHebrew        SFlowCharSet = 2013
+	SFlowCSPC8Turkish              SFlowCharSet = 2014
+	SFlowCSIBMSymbols              SFlowCharSet = 2015
+	SFlowCSIBMThai                 SFlowCharSet = 2016
+	SFlowCSHPLegal                 SFlowCharSet = 2017
+	SFlowCSHPPiFont                SFlowCharSet = 2018
+	SFlowCSHPMath8                 SFlowCharSet = 2019
+	SFlowCSHPPSMath                SFlowCharSet = 2020
+	SFlowCSHPDesktop               SFlowCharSet = 2021
+	SFlowCSVenturaMath             SFlowCharSet = 2022
+	SFlowCSMicrosoftPublishing     SFlowCharSet = 2023
+	SFlowCSWindows31J              SFlowCharSet = 2024
+	SFlowCSGB2312                  SFlowCharSet = 2025
+	SFlowCSBig5                    SFlowCharSet = 2026
+	SFlowCSMacintosh               SFlowCharSet = 2027
+	SFlowCSIBM037                  SFlowCharSet = 2028
+	SFlowCSIBM038                  SFlowCharSet = 2029
+	SFlowCSIBM273                  SFlowCharSet = 2030
+	SFlowCSIBM274                  SFlowCharSet = 2031
+	SFlowCSIBM275                  SFlowCharSet = 2032
+	SFlowCSIBM277                  SFlowCharSet = 2033
+	SFlowCSIBM278                  SFlowCharSet = 2034
+	SFlowCSIBM280                  SFlowCharSet = 2035
+	SFlowCSIBM281                  SFlowCharSet = 2036
+	SFlowCSIBM284                  SFlowCharSet = 2037
+	SFlowCSIBM285                  SFlowCharSet = 2038
+	SFlowCSIBM290                  SFlowCharSet = 2039
+	SFlowCSIBM297                  SFlowCharSet = 2040
+	SFlowCSIBM420                  SFlowCharSet = 2041
+	SFlowCSIBM423                  SFlowCharSet = 2042
+	SFlowCSIBM424                  SFlowCharSet = 2043
+	SFlowCSIBM500                  SFlowCharSet = 2044
+	SFlowCSIBM851                  SFlowCharSet = 2045
+	SFlowCSIBM855                  SFlowCharSet = 2046
+	SFlowCSIBM857                  SFlowCharSet = 2047
+	SFlowCSIBM860                  SFlowCharSet = 2048
+	SFlowCSIBM861                  SFlowCharSet = 2049
+	SFlowCSIBM863                  SFlowCharSet = 2050
+	SFlowCSIBM864                  SFlowCharSet = 2051
+	SFlowCSIBM865                  SFlowCharSet = 2052
+	SFlowCSIBM868                  SFlowCharSet = 2053
+	SFlowCSIBM869                  SFlowCharSet = 2054
+	SFlowCSIBM870                  SFlowCharSet = 2055
+	SFlowCSIBM871                  SFlowCharSet = 2056
+	SFlowCSIBM880                  SFlowCharSet = 2057
+	SFlowCSIBM891                  SFlowCharSet = 2058
+	SFlowCSIBM903                  SFlowCharSet = 2059
+	SFlowCSIBBM904                 SFlowCharSet = 2060
+	SFlowCSIBM905                  SFlowCharSet = 2061
+	SFlowCSIBM918                  SFlowCharSet = 2062
+	SFlowCSIBM1026                 SFlowCharSet = 2063
+	SFlowCSIBMEBCDICATDE           SFlowCharSet = 2064
+	SFlowCSEBCDICATDEA             SFlowCharSet = 2065
+	SFlowCSEBCDICCAFR              SFlowCharSet = 2066
+	SFlowCSEBCDICDKNO              SFlowCharSet = 2067
+	SFlowCSEBCDICDKNOA             SFlowCharSet = 2068
+	SFlowCSEBCDICFISE              SFlowCharSet = 2069
+	SFlowCSEBCDICFISEA             SFlowCharSet = 2070
+	SFlowCSEBCDICFR                SFlowCharSet = 2071
+	SFlowCSEBCDICIT                SFlowCharSet = 2072
+	SFlowCSEBCDICPT                SFlowCharSet = 2073
+	SFlowCSEBCDICES                SFlowCharSet = 2074
+	SFlowCSEBCDICESA               SFlowCharSet = 2075
+	SFlowCSEBCDICESS               SFlowCharSet = 2076
+	SFlowCSEBCDICUK                SFlowCharSet = 2077
+	SFlowCSEBCDICUS                SFlowCharSet = 2078
+	SFlowCSUnknown8BiT             SFlowCharSet = 2079
+	SFlowCSMnemonic                SFlowCharSet = 2080
+	SFlowCSMnem                    SFlowCharSet = 2081
+	SFlowCSVISCII                  SFlowCharSet = 2082
+	SFlowCSVIQR                    SFlowCharSet = 2083
+	SFlowCSKOI8R                   SFlowCharSet = 2084
+	SFlowCSHZGB2312                SFlowCharSet = 2085
+	SFlowCSIBM866                  SFlowCharSet = 2086
+	SFlowCSPC775Baltic             SFlowCharSet = 2087
+	SFlowCSKOI8U                   SFlowCharSet = 2088
+	SFlowCSIBM00858                SFlowCharSet = 2089
+	SFlowCSIBM00924                SFlowCharSet = 2090
+	SFlowCSIBM01140                SFlowCharSet = 2091
+	SFlowCSIBM01141                SFlowCharSet = 2092
+	SFlowCSIBM01142                SFlowCharSet = 2093
+	SFlowCSIBM01143                SFlowCharSet = 2094
+	SFlowCSIBM01144                SFlowCharSet = 2095
+	SFlowCSIBM01145                SFlowCharSet = 2096
+	SFlowCSIBM01146                SFlowCharSet = 2097
+	SFlowCSIBM01147                SFlowCharSet = 2098
+	SFlowCSIBM01148                SFlowCharSet = 2099
+	SFlowCSIBM01149                SFlowCharSet = 2100
+	SFlowCSBig5HKSCS               SFlowCharSet = 2101
+	SFlowCSIBM1047                 SFlowCharSet = 2102
+	SFlowCSPTCP154                 SFlowCharSet = 2103
+	SFlowCSAmiga1251               SFlowCharSet = 2104
+	SFlowCSKOI7switched            SFlowCharSet = 2105
+	SFlowCSBRF                     SFlowCharSet = 2106
+	SFlowCSTSCII                   SFlowCharSet = 2107
+	SFlowCSCP51932                 SFlowCharSet = 2108
+	SFlowCSWindows874              SFlowCharSet = 2109
+	SFlowCSWindows1250             SFlowCharSet = 2250
+	SFlowCSWindows1251             SFlowCharSet = 2251
+	SFlowCSWindows1252             SFlowCharSet = 2252
+	SFlowCSWindows1253             SFlowCharSet = 2253
+	SFlowCSWindows1254             SFlowCharSet = 2254
+	SFlowCSWindows1255             SFlowCharSet = 2255
+	SFlowCSWindows1256             SFlowCharSet = 2256
+	SFlowCSWindows1257             SFlowCharSet = 2257
+	SFlowCSWindows1258             SFlowCharSet = 2258
+	SFlowCSTIS620                  SFlowCharSet = 2259
+	SFlowCS50220                   SFlowCharSet = 2260
+	SFlowCSreserved                SFlowCharSet = 3000
+)
+
+// **************************************************
+//  Extended URL Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   direction                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      URL                      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      Host                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowURLDirection uint32
+
+const (
+	SFlowURLsrc SFlowURLDirection = 1
+	SFlowURLdst SFlowURLDirection = 2
+)
+
+type SFlowExtendedURLRecord struct {
+	SFlowBaseFlowRecord
+	Direction SFlowURLDirection
+	URL       string
+	Host      string
+}
+
+/*
+SFlowExtendedSwitchFlowRecord give additional information
+about the sampled packet if it's available. It's mainly
+useful for getting at the incoming and outgoing VLANs
+An agent may or may not provide this information.
+*/
+type SFlowExtendedSwitchFlowRecord struct {
+	SFlowBaseFlowRecord
+	IncomingVLAN         uint32
+	IncomingVLANPriority uint32
+	OutgoingVLAN         uint32
+	OutgoingVLANPriority uint32
+}
+
+// Extended switch records have the following structure:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   Incoming VLAN               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Incoming VLAN Priority         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   Outgoing VLAN               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Outgoing VLAN Priority         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedRouterFlowRecord gives additional information
+// about the layer 3 routing information used to forward
+// the packet
+type SFlowExtendedRouterFlowRecord struct {
+	SFlowBaseFlowRecord
+	NextHop                net.IP
+	NextHopSourceMask      uint32
+	NextHopDestinationMask uint32
+}
+
+// Extended router records have the following structure:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   IP version of next hop router (1=v4|2=v6)   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /     Next Hop address (v4=4byte|v6=16byte)     /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              Next Hop Source Mask             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              Next Hop Destination Mask        |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+//------------------------------
+
+// SFlowExtendedGatewayFlowRecord describes information treasured by
+// nework engineers everywhere: AS path information listing which
+// BGP peer sent the packet, and various other BGP related info.
+// This information is vital because it gives a picture of how much
+// traffic is being sent from / received by various BGP peers.
+
+// Extended gateway records have the following structure:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   IP version of next hop router (1=v4|2=v6)   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /     Next Hop address (v4=4byte|v6=16byte)     /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                       AS                      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Source AS                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Peer AS                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  AS Path Count                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                AS Path / Sequence             /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                   Communities                 /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Local Pref                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// AS Path / Sequence:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     AS Source Type (Path=1 / Sequence=2)      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              Path / Sequence length           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /              Path / Sequence Members          /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// Communities:
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                communitiy length              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /              communitiy Members               /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowExtendedGatewayFlowRecord struct {
+	SFlowBaseFlowRecord
+	NextHop     net.IP
+	AS          uint32
+	SourceAS    uint32
+	PeerAS      uint32
+	ASPathCount uint32
+	ASPath      []SFlowASDestination
+	Communities []uint32
+	LocalPref   uint32
+}
+
+type SFlowASPathType uint32
+
+const (
+	SFlowASSet      SFlowASPathType = 1
+	SFlowASSequence SFlowASPathType = 2
+)
+
+type SFlowASDestination struct {
+	Type    SFlowASPathType
+	Count   uint32
+	Members []uint32
+}
+
+// **************************************************
+//  Packet IP version 4 Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                     Length                    |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                    Protocol                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  Source IPv4                  |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                Destination IPv4               |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   Source Port                 |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                Destionation Port              |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   TCP Flags                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                      TOS                      |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowIpv4Record struct {
+	// The length of the IP packet excluding ower layer encapsulations
+	Length uint32
+	// IP Protocol type (for example, TCP = 6, UDP = 17)
+	Protocol uint32
+	// Source IP Address
+	IPSrc net.IP
+	// Destination IP Address
+	IPDst net.IP
+	// TCP/UDP source port number or equivalent
+	PortSrc uint32
+	// TCP/UDP destination port number or equivalent
+	PortDst uint32
+	// TCP flags
+	TCPFlags uint32
+	// IP type of service
+	TOS uint32
+}
+
+// **************************************************
+//  Packet IP version 6 Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                     Length                    |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                    Protocol                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  Source IPv4                  |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                Destination IPv4               |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   Source Port                 |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                Destionation Port              |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   TCP Flags                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                    Priority                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowIpv6Record struct {
+	// The length of the IP packet excluding ower layer encapsulations
+	Length uint32
+	// IP Protocol type (for example, TCP = 6, UDP = 17)
+	Protocol uint32
+	// Source IP Address
+	IPSrc net.IP
+	// Destination IP Address
+	IPDst net.IP
+	// TCP/UDP source port number or equivalent
+	PortSrc uint32
+	// TCP/UDP destination port number or equivalent
+	PortDst uint32
+	// TCP flags
+	TCPFlags uint32
+	// IP priority
+	Priority uint32
+}
+
+// **************************************************
+//  IPv4 / IPv6 Flow Records
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /       Packet IP version 4 / 6 Record          /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowIpv4FlowRecord (format 3) summarizes a sampled IPv4 packet
+type SFlowIpv4FlowRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv4Record
+}
+
+// SFlowIpv6FlowRecord (format 4) summarizes a sampled IPv6 packet
+type SFlowIpv6FlowRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv6Record
+}
+
+// **************************************************
+//  Extended IPv4 Tunnel Egress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /           Packet IP version 4 Record          /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedIpv4TunnelEgressRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv4Record SFlowIpv4Record
+}
+
+// **************************************************
+//  Extended IPv4 Tunnel Ingress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /           Packet IP version 4 Record          /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedIpv4TunnelIngressRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv4Record SFlowIpv4Record
+}
+
+// **************************************************
+//  Extended IPv6 Tunnel Egress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /           Packet IP version 6 Record          /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedIpv6TunnelEgressRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv6Record
+}
+
+// **************************************************
+//  Extended IPv6 Tunnel Ingress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /           Packet IP version 6 Record          /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedIpv6TunnelIngressRecord struct {
+	SFlowBaseFlowRecord
+	SFlowIpv6Record
+}
+
+// **************************************************
+//  Extended Decapsulate Egress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |               Inner Header Offset             |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedDecapsulateEgressRecord struct {
+	SFlowBaseFlowRecord
+	InnerHeaderOffset uint32
+}
+
+// **************************************************
+//  Extended Decapsulate Ingress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |               Inner Header Offset             |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedDecapsulateIngressRecord struct {
+	SFlowBaseFlowRecord
+	InnerHeaderOffset uint32
+}
+
+// **************************************************
+//  Extended VNI Egress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                       VNI                     |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedVniEgressRecord struct {
+	SFlowBaseFlowRecord
+	VNI uint32
+}
+
+// **************************************************
+//  Extended VNI Ingress
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                       VNI                     |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedVniIngressRecord struct {
+	SFlowBaseFlowRecord
+	VNI uint32
+}
+
+// **************************************************
+//  Extended 802.11 Payload Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Cipher Suite                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Data Length                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                      Data                     \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtended80211PayloadFlowRecord is the start of the unencrypted
+// payload of a sampled 802.11 frame. CipherSuite is the OUI of the suite
+// followed by its type, e.g. 0x000fac04 for CCMP.
+type SFlowExtended80211PayloadFlowRecord struct {
+	SFlowBaseFlowRecord
+	CipherSuite uint32
+	Data        []byte
+}
+
+// **************************************************
+//  Extended 802.11 RX / TX Flow Records
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                  SSID (string)                \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |          BSSID (mac, padded to 8)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Version                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  RX: Channel / Speed (64 bit) / RSNI / RCPI / Packet Duration (micro s)
+//  TX: Transmissions / Packet Duration (micro s) / Retransmission Duration
+//      (micro s) / Channel / Speed (64 bit) / Power (mW)
+
+// SFlowExtended80211RxFlowRecord describes how a sampled 802.11 frame
+// was received
+type SFlowExtended80211RxFlowRecord struct {
+	SFlowBaseFlowRecord
+	SSID           string
+	BSSID          net.HardwareAddr
+	Version        SFlowIEEE80211Version
+	Channel        uint32
+	Speed          uint64 // bits per second
+	RSNI           uint32 // received signal to noise ratio, dot11FrameRprtRSNI
+	RCPI           uint32 // received channel power, dot11FrameRprtLastRCPI
+	PacketDuration uint32 // microseconds the frame occupied the medium
+}
+
+// SFlowExtended80211TxFlowRecord describes how a sampled 802.11 frame
+// was transmitted. Transmissions is 0 when unknown, 1 when the frame went
+// through at the first attempt and n for n-1 retransmissions.
+type SFlowExtended80211TxFlowRecord struct {
+	SFlowBaseFlowRecord
+	SSID            string
+	BSSID           net.HardwareAddr
+	Version         SFlowIEEE80211Version
+	Transmissions   uint32
+	PacketDuration  uint32 // microseconds the frame occupied the medium
+	RetransDuration uint32 // microseconds spent on failed attempts
+	Channel         uint32
+	Speed           uint64 // bits per second
+	Power           uint32 // transmit power in mW
+}
+
+type SFlowIEEE80211Version uint32
+
+const (
+	SFlowIEEE80211a SFlowIEEE80211Version = 1
+	SFlowIEEE80211b SFlowIEEE80211Version = 2
+	SFlowIEEE80211g SFlowIEEE80211Version = 3
+	SFlowIEEE80211n SFlowIEEE80211Version = 4
+)
+
+func (v SFlowIEEE80211Version) String() string {
+	switch v {
+	case SFlowIEEE80211a:
+		return "802.11a"
+	case SFlowIEEE80211b:
+		return "802.11b"
+	case SFlowIEEE80211g:
+		return "802.11g"
+	case SFlowIEEE80211n:
+		return "802.11n"
+	default:
+		return "802.11-" + strconv.Itoa(int(v))
+	}
+}
+
+// **************************************************
+//  Extended 802.11 Aggregation Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   PDU Count                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              PDU 1 Record Count               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \              PDU 1 Flow Records               \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                      ...                      \
+
+// SFlowExtended80211AggregationFlowRecord holds the flow records of each
+// PDU of a sampled 802.11n aggregated frame
+type SFlowExtended80211AggregationFlowRecord struct {
+	SFlowBaseFlowRecord
+	PDUs []SFlow80211PDU
+}
+
+type SFlow80211PDU struct {
+	Records SFlowRecords
+}
+
+// **************************************************
+//  Extended Egress Queue / Transit Delay / Queue Depth Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      Queue / Delay (ns) / Depth (bytes)       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedEgressQueueRecord is the egress queue selected for the
+// sampled packet
+type SFlowExtendedEgressQueueRecord struct {
+	SFlowBaseFlowRecord
+	Queue uint32
+}
+
+// SFlowExtendedTransitRecord is the time the sampled packet spent in the
+// switch, in nanoseconds. SFlowTransitDelayOverflow stands for more than
+// 4.29 seconds.
+type SFlowExtendedTransitRecord struct {
+	SFlowBaseFlowRecord
+	Delay uint32
+}
+
+const SFlowTransitDelayOverflow = 0xffffffff
+
+// SFlowExtendedQueueRecord is the depth, in bytes, of the egress queue
+// when the sampled packet was enqueued
+type SFlowExtendedQueueRecord struct {
+	SFlowBaseFlowRecord
+	Depth uint32
+}
+
+// **************************************************
+//  Extended Function Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                 Symbol (string)               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedFunctionFlowRecord names the function that dropped the
+// packet of a discard sample, e.g. a kernel symbol reported by the Linux
+// drop monitor
+type SFlowExtendedFunctionFlowRecord struct {
+	SFlowBaseFlowRecord
+	Symbol string
+}
+
+// **************************************************
+//  Extended MPLS Flow Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |   IP version of next hop router (1=v4|2=v6)   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /     Next Hop address (v4=4byte|v6=16byte)     /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |               In Label Stack Count            |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /                 In Label Stack                /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |              Out Label Stack Count            |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /                 Out Label Stack               /
+// /                                               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedMPLSFlowRecord struct {
+	SFlowBaseFlowRecord
+	NextHop       net.IP
+	InLabelStack  []uint32
+	OutLabelStack []uint32
+}
+
+// **************************************************
+//  Extended NAT Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   IP version of source address (1=v4|2=v6)    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /      Source address (v4=4byte|v6=16byte)      /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  | IP version of destination address (1=v4|2=v6) |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /    Destination address (v4=4byte|v6=16byte)  /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedNATFlowRecord holds the translated addresses of the
+// sampled packet, the original ones are in the packet header
+type SFlowExtendedNATFlowRecord struct {
+	SFlowBaseFlowRecord
+	SourceAddress      net.IP
+	DestinationAddress net.IP
+}
+
+// **************************************************
+//  Extended MPLS Tunnel Flow Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /                Tunnel LSP Name                /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   Tunnel ID                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   Tunnel COS                  |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedMPLSTunnelFlowRecord struct {
+	SFlowBaseFlowRecord
+	TunnelLSPName string
+	TunnelID      uint32
+	TunnelCOS     uint32
+}
+
+// **************************************************
+//  Extended MPLS VC Flow Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /                VC Instance Name               /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                   VLL VC ID                   |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  VC Label COS                 |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedMPLSVCFlowRecord struct {
+	SFlowBaseFlowRecord
+	VCInstanceName string
+	VLLVCID        uint32
+	VCLabelCOS     uint32
+}
+
+// **************************************************
+//  Extended MPLS FEC (FTN) Flow Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// /                 MPLS FTN Descr                /
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                 MPLS FTN Mask                 |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedMPLSFECFlowRecord struct {
+	SFlowBaseFlowRecord
+	FTNDescr string
+	FTNMask  uint32
+}
+
+// **************************************************
+//  Extended MPLS LVP FEC (LDP FEC) Flow Record
+// **************************************************
+
+// 0                      15                      31
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |      20 bit Interprise (0)     |12 bit format |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |                  record length                |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+// |        MPLS FEC Address Prefix Length         |
+// +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowExtendedMPLSLVPFECFlowRecord struct {
+	SFlowBaseFlowRecord
+	FECAddrPrefixLength uint32
+}
+
+// **************************************************
+//  Extended VLAN Tunnel Flow Record
+// **************************************************
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Stack Count                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /     Stripped 802.1Q Layers (TPID << 16|TCI)   /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedVLANTunnelFlowRecord lists the 802.1Q tags stripped from
+// the packet (QinQ), outermost first. Each entry is the TPID followed by
+// the TCI, see SFlowVLANTag.
+type SFlowExtendedVLANTunnelFlowRecord struct {
+	SFlowBaseFlowRecord
+	Stack []SFlowVLANTag
+}
+
+type SFlowVLANTag uint32
+
+// TPID returns the tag protocol identifier (0x8100, 0x88a8, ...)
+func (t SFlowVLANTag) TPID() uint16 { return uint16(t >> 16) }
+
+// Priority returns the 802.1p priority code point
+func (t SFlowVLANTag) Priority() uint8 { return uint8((t >> 13) & 0x7) }
+
+// VLAN returns the 12 bit VLAN identifier
+func (t SFlowVLANTag) VLAN() uint16 { return uint16(t & 0xFFF) }
+
+// **************************************************
+//  Extended Socket IPv4 / IPv6 Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IP Protocol                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |          Local IP (4 or 16 bytes)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |          Remote IP (4 or 16 bytes)            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Local Port                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Remote Port                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedSocketFlowRecord is the local socket a host sampled packet
+// belongs to. The IPv4 (2100) and IPv6 (2101) records only differ by the
+// address length, Format tells which one it was.
+type SFlowExtendedSocketFlowRecord struct {
+	SFlowBaseFlowRecord
+	Protocol   uint32
+	LocalIP    net.IP
+	RemoteIP   net.IP
+	LocalPort  uint32
+	RemotePort uint32
+}
+
+// **************************************************
+//  Memcache Operation Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Protocol                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Command                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                  Key (string)                 /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Number of Keys               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   Value Bytes                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Duration (micro s)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Status                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowMemcacheFlowRecord is a sampled memcached operation
+type SFlowMemcacheFlowRecord struct {
+	SFlowBaseFlowRecord
+	Protocol   uint32 // 1 other, 2 ascii, 3 binary
+	Command    uint32 // memcache_cmd of the sFlow memcache spec: 1 set, 7 get, ...
+	Key        string
+	NumKeys    uint32
+	ValueBytes uint32
+	Duration   uint32 // microseconds
+	Status     SFlowMemcacheStatus
+}
+
+type SFlowMemcacheStatus uint32
+
+const (
+	SFlowMemcacheUnknown     SFlowMemcacheStatus = 0
+	SFlowMemcacheOK          SFlowMemcacheStatus = 1
+	SFlowMemcacheError       SFlowMemcacheStatus = 2
+	SFlowMemcacheClientError SFlowMemcacheStatus = 3
+	SFlowMemcacheServerError SFlowMemcacheStatus = 4
+	SFlowMemcacheStored      SFlowMemcacheStatus = 5
+	SFlowMemcacheNotStored   SFlowMemcacheStatus = 6
+	SFlowMemcacheExists      SFlowMemcacheStatus = 7
+	SFlowMemcacheNotFound    SFlowMemcacheStatus = 8
+	SFlowMemcacheDeleted     SFlowMemcacheStatus = 9
+)
+
+func (s SFlowMemcacheStatus) String() string {
+	switch s {
+	case SFlowMemcacheOK:
+		return "OK"
+	case SFlowMemcacheError:
+		return "ERROR"
+	case SFlowMemcacheClientError:
+		return "CLIENT_ERROR"
+	case SFlowMemcacheServerError:
+		return "SERVER_ERROR"
+	case SFlowMemcacheStored:
+		return "STORED"
+	case SFlowMemcacheNotStored:
+		return "NOT_STORED"
+	case SFlowMemcacheExists:
+		return "EXISTS"
+	case SFlowMemcacheNotFound:
+		return "NOT_FOUND"
+	case SFlowMemcacheDeleted:
+		return "DELETED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// **************************************************
+//  HTTP Request Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Method                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |         Protocol (major * 1000 + minor)       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /    URI / Host / Referer / User Agent (string) /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /   X-Forwarded-For (string, record 2206 only)  /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /        Auth User / MIME Type (string)         /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      Request Bytes / Response Bytes (uint64)  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Duration (micro s)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Status Code                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHTTPRequestFlowRecord is a sampled HTTP request. Record 2201 is
+// the first version of the record, without the X-Forwarded-For header.
+type SFlowHTTPRequestFlowRecord struct {
+	SFlowBaseFlowRecord
+	Method        SFlowHTTPMethod
+	Protocol      uint32
+	URI           string
+	Host          string
+	Referer       string
+	UserAgent     string
+	XFF           string
+	AuthUser      string
+	MimeType      string
+	RequestBytes  uint64
+	ResponseBytes uint64
+	Duration      uint32 // microseconds
+	Status        int32
+}
+
+type SFlowHTTPMethod uint32
+
+const (
+	SFlowHTTPMethodOther   SFlowHTTPMethod = 0
+	SFlowHTTPMethodOptions SFlowHTTPMethod = 1
+	SFlowHTTPMethodGet     SFlowHTTPMethod = 2
+	SFlowHTTPMethodHead    SFlowHTTPMethod = 3
+	SFlowHTTPMethodPost    SFlowHTTPMethod = 4
+	SFlowHTTPMethodPut     SFlowHTTPMethod = 5
+	SFlowHTTPMethodDelete  SFlowHTTPMethod = 6
+	SFlowHTTPMethodTrace   SFlowHTTPMethod = 7
+	SFlowHTTPMethodConnect SFlowHTTPMethod = 8
+)
+
+func (m SFlowHTTPMethod) String() string {
+	switch m {
+	case SFlowHTTPMethodOptions:
+		return "OPTIONS"
+	case SFlowHTTPMethodGet:
+		return "GET"
+	case SFlowHTTPMethodHead:
+		return "HEAD"
+	case SFlowHTTPMethodPost:
+		return "POST"
+	case SFlowHTTPMethodPut:
+		return "PUT"
+	case SFlowHTTPMethodDelete:
+		return "DELETE"
+	case SFlowHTTPMethodTrace:
+		return "TRACE"
+	case SFlowHTTPMethodConnect:
+		return "CONNECT"
+	default:
+		return "OTHER"
+	}
+}
+
+// **************************************************
+//  Application Operation Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /  Application / Operation / Attributes (string)/
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /          Status Description (string)          /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      Request Bytes / Response Bytes (uint64)  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Duration (micro s)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Status                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppContext names an application operation
+type SFlowAppContext struct {
+	Application string
+	Operation   string
+	Attributes  string
+}
+
+// SFlowAppOperationFlowRecord is a sampled application operation
+type SFlowAppOperationFlowRecord struct {
+	SFlowBaseFlowRecord
+	Context       SFlowAppContext
+	StatusDescr   string
+	RequestBytes  uint64
+	ResponseBytes uint64
+	Duration      uint32 // microseconds
+	Status        SFlowAppStatus
+}
+
+type SFlowAppStatus uint32
+
+const (
+	SFlowAppStatusOK             SFlowAppStatus = 0
+	SFlowAppStatusOther          SFlowAppStatus = 1
+	SFlowAppStatusTimeout        SFlowAppStatus = 2
+	SFlowAppStatusInternalError  SFlowAppStatus = 3
+	SFlowAppStatusBadRequest     SFlowAppStatus = 4
+	SFlowAppStatusForbidden      SFlowAppStatus = 5
+	SFlowAppStatusTooLarge       SFlowAppStatus = 6
+	SFlowAppStatusNotImplemented SFlowAppStatus = 7
+	SFlowAppStatusNotFound       SFlowAppStatus = 8
+	SFlowAppStatusUnavailable    SFlowAppStatus = 9
+	SFlowAppStatusUnauthorized   SFlowAppStatus = 10
+)
+
+func (s SFlowAppStatus) String() string {
+	switch s {
+	case SFlowAppStatusOK:
+		return "OK"
+	case SFlowAppStatusTimeout:
+		return "TIMEOUT"
+	case SFlowAppStatusInternalError:
+		return "INTERNAL_ERROR"
+	case SFlowAppStatusBadRequest:
+		return "BAD_REQUEST"
+	case SFlowAppStatusForbidden:
+		return "FORBIDDEN"
+	case SFlowAppStatusTooLarge:
+		return "TOO_LARGE"
+	case SFlowAppStatusNotImplemented:
+		return "NOT_IMPLEMENTED"
+	case SFlowAppStatusNotFound:
+		return "NOT_FOUND"
+	case SFlowAppStatusUnavailable:
+		return "UNAVAILABLE"
+	case SFlowAppStatusUnauthorized:
+		return "UNAUTHORIZED"
+	default:
+		return "OTHER"
+	}
+}
+
+// **************************************************
+//  Application Parent Context Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /  Application / Operation / Attributes (string)/
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppParentContextFlowRecord is the operation that caused the
+// sampled operation
+type SFlowAppParentContextFlowRecord struct {
+	SFlowBaseFlowRecord
+	Context SFlowAppContext
+}
+
+// **************************************************
+//  Application Initiator / Target Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                 Actor (string)                /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppActorFlowRecord is the initiator (2204) or the target (2205) of
+// the sampled operation, Format tells which
+type SFlowAppActorFlowRecord struct {
+	SFlowBaseFlowRecord
+	Actor string
+}
+
+// **************************************************
+//  Extended TCP Info Flow Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  record length                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Direction                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     Send MSS / Receive MSS / Unacked /        |
+//  |     Lost / Retransmitted / Path MTU           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     RTT / RTT Variance (micro s)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     Send Cwnd / Reordering                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     Min RTT (micro s)                         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowExtendedTCPInfoFlowRecord is the Linux tcp_info of the socket a
+// host sampled packet belongs to
+type SFlowExtendedTCPInfoFlowRecord struct {
+	SFlowBaseFlowRecord
+	Direction  SFlowPacketDirection
+	SndMSS     uint32
+	RcvMSS     uint32
+	Unacked    uint32 // packets in flight
+	Lost       uint32
+	Retrans    uint32
+	PMTU       uint32
+	RTT        uint32 // smoothed, microseconds
+	RTTVar     uint32 // microseconds
+	SndCwnd    uint32
+	Reordering uint32
+	MinRTT     uint32 // microseconds
+}
+
+type SFlowPacketDirection uint32
+
+const (
+	SFlowPacketDirectionUnknown  SFlowPacketDirection = 0
+	SFlowPacketDirectionReceived SFlowPacketDirection = 1
+	SFlowPacketDirectionSent     SFlowPacketDirection = 2
+)
+
+func (d SFlowPacketDirection) String() string {
+	switch d {
+	case SFlowPacketDirectionReceived:
+		return "received"
+	case SFlowPacketDirectionSent:
+		return "sent"
+	default:
+		return "unknown"
+	}
+}
+
+// ****************************************************************************************************
+//  Counter Record
+// ****************************************************************************************************
+
+type SFlowBaseCounterRecord struct {
+	EnterpriseID   SFlowEnterpriseID
+	Format         SFlowCounterRecordType
+	FlowDataLength uint32
+}
+
+func (r SFlowBaseCounterRecord) RecordEnterprise() SFlowEnterpriseID { return r.EnterpriseID }
+func (r SFlowBaseCounterRecord) RecordFormat() uint32                { return uint32(r.Format) }
+func (r SFlowBaseCounterRecord) RecordLength() uint32                { return r.FlowDataLength }
+
+func (r SFlowBaseCounterRecord) RecordType() string {
+	return counterRecordType(r.EnterpriseID, r.Format)
+}
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfIndex                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfType                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfSpeed                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfDirection                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfStatus                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IFInOctets                  |
+//  |                                               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfInUcastPkts               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IfInMulticastPkts            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IfInBroadcastPkts            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfInDiscards               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    InInErrors                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IfInUnknownProtos            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfOutOctets                 |
+//  |                                               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfOutUcastPkts              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IfOutMulticastPkts           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IfOutBroadcastPkts           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IfOutDiscards               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfOUtErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IfPromiscouousMode            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowGenericInterfaceCounters struct {
+	SFlowBaseCounterRecord
+	IfIndex            uint32
+	IfType             uint32
+	IfSpeed            uint64
+	IfDirection        uint32
+	IfStatus           uint32
+	IfInOctets         uint64
+	IfInUcastPkts      uint32
+	IfInMulticastPkts  uint32
+	IfInBroadcastPkts  uint32
+	IfInDiscards       uint32
+	IfInErrors         uint32
+	IfInUnknownProtos  uint32
+	IfOutOctets        uint64
+	IfOutUcastPkts     uint32
+	IfOutMulticastPkts uint32
+	IfOutBroadcastPkts uint32
+	IfOutDiscards      uint32
+	IfOutErrors        uint32
+	IfPromiscuousMode  uint32
+}
+
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                   counter data                /
+//  /                                               /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowEthernetCounters struct {
+	SFlowBaseCounterRecord
+	AlignmentErrors           uint32
+	FCSErrors                 uint32
+	SingleCollisionFrames     uint32
+	MultipleCollisionFrames   uint32
+	SQETestErrors             uint32
+	DeferredTransmissions     uint32
+	LateCollisions            uint32
+	ExcessiveCollisions       uint32
+	InternalMacTransmitErrors uint32
+	CarrierSenseErrors        uint32
+	FrameTooLongs             uint32
+	InternalMacReceiveErrors  uint32
+	SymbolErrors              uint32
+}
+
+// **************************************************
+//  VLAN Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    VLAN ID                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Octets                    |
+//  |                                               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   UcastPkts                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 MulticastPkts                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 BroadcastPkts                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Discards                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVLANCounters holds the traffic a switch forwarded on one VLAN
+type SFlowVLANCounters struct {
+	SFlowBaseCounterRecord
+	VlanID        uint32
+	Octets        uint64
+	UcastPkts     uint32
+	MulticastPkts uint32
+	BroadcastPkts uint32
+	Discards      uint32
+}
+
+// **************************************************
+//  802.11 Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   20 counters of the IEEE 802.11 MIB, in the  |
+//  |         order of the struct below             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlow80211Counters holds the dot11CountersTable entry of a radio
+// interface. AssociatedStationCount is a gauge, the others are counters.
+type SFlow80211Counters struct {
+	SFlowBaseCounterRecord
+	TransmittedFragmentCount       uint32
+	MulticastTransmittedFrameCount uint32
+	FailedCount                    uint32
+	RetryCount                     uint32
+	MultipleRetryCount             uint32
+	FrameDuplicateCount            uint32
+	RTSSuccessCount                uint32
+	RTSFailureCount                uint32
+	ACKFailureCount                uint32
+	ReceivedFragmentCount          uint32
+	MulticastReceivedFrameCount    uint32
+	FCSErrorCount                  uint32
+	TransmittedFrameCount          uint32
+	WEPUndecryptableCount          uint32
+	QoSDiscardedFragmentCount      uint32
+	AssociatedStationCount         uint32
+	QoSCFPollsReceivedCount        uint32
+	QoSCFPollsUnusedCount          uint32
+	QoSCFPollsUnusableCount        uint32
+	QoSCFPollsLostCount            uint32
+}
+
+// **************************************************
+//  LACP Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      Actor System ID (mac, padded to 8)       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     Partner System ID (mac, padded to 8)      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               Attached Agg ID                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  | ActorAdmin | ActorOper |PartnerAdmin|PartnerOper|
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |  LACPDUsRx / MarkerPDUsRx / MarkerResponse-   |
+//  |      PDUsRx / UnknownRx / IllegalRx           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   LACPDUsTx / MarkerPDUsTx / MarkerResponse-  |
+//  |                    PDUsTx                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowLACPCounters holds the IEEE 802.1AX state and PDU counters of a
+// LAG member port
+type SFlowLACPCounters struct {
+	SFlowBaseCounterRecord
+	ActorSystemID        net.HardwareAddr
+	PartnerSystemID      net.HardwareAddr
+	AttachedAggID        uint32
+	ActorAdminState      SFlowLACPState
+	ActorOperState       SFlowLACPState
+	PartnerAdminState    SFlowLACPState
+	PartnerOperState     SFlowLACPState
+	LACPDUsRx            uint32
+	MarkerPDUsRx         uint32
+	MarkerResponsePDUsRx uint32
+	UnknownRx            uint32
+	IllegalRx            uint32
+	LACPDUsTx            uint32
+	MarkerPDUsTx         uint32
+	MarkerResponsePDUsTx uint32
+}
+
+// SFlowLACPState is the 802.1AX port state octet
+type SFlowLACPState uint8
+
+const (
+	SFlowLACPActivity        SFlowLACPState = 0x01
+	SFlowLACPTimeout         SFlowLACPState = 0x02
+	SFlowLACPAggregation     SFlowLACPState = 0x04
+	SFlowLACPSynchronization SFlowLACPState = 0x08
+	SFlowLACPCollecting      SFlowLACPState = 0x10
+	SFlowLACPDistributing    SFlowLACPState = 0x20
+	SFlowLACPDefaulted       SFlowLACPState = 0x40
+	SFlowLACPExpired         SFlowLACPState = 0x80
+)
+
+// InSync reports whether the port is synchronized, collecting and
+// distributing, i.e. actually carrying traffic for its aggregator
+func (state SFlowLACPState) InSync() bool {
+	const inSync = SFlowLACPSynchronization | SFlowLACPCollecting | SFlowLACPDistributing
+	return state&inSync == inSync
+}
+
+// **************************************************
+//  SFP Optical Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   Module ID                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               Module Total Lanes              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |             Supply Voltage (mV)               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |       Temperature (thousandths of a C)        |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Lane Count                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                     Lanes                     /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//
+// each lane is
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Lane Index                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |             TX Bias Current (uA)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      TX Power / Min / Max (uW)                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              TX Wavelength (nm)               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      RX Power / Min / Max (uW)                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              RX Wavelength (nm)               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowSFPCounters holds the digital optical monitoring values of the
+// SFP / QSFP module of a port
+type SFlowSFPCounters struct {
+	SFlowBaseCounterRecord
+	ModuleID          uint32
+	ModuleTotalLanes  uint32
+	ModuleSupplyVolts uint32 // millivolts
+	ModuleTemperature int32  // thousandths of a degree Celsius
+	Lanes             []SFlowSFPLane
+}
+
+// SFlowSFPLane holds the optical levels of one lane. Min and Max are the
+// alarm thresholds of the module, 0 when the module does not report them.
+type SFlowSFPLane struct {
+	LaneIndex     uint32
+	TxBiasCurrent uint32 // microamps
+	TxPower       uint32 // microwatts
+	TxPowerMin    uint32
+	TxPowerMax    uint32
+	TxWavelength  uint32 // nanometers
+	RxPower       uint32 // microwatts
+	RxPowerMin    uint32
+	RxPowerMax    uint32
+	RxWavelength  uint32
+}
+
+// **************************************************
+//  Processor Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    FiveSecCpu                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    OneMinCpu                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    GiveMinCpu                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TotalMemory                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    FreeMemory                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowProcessorCounters struct {
+	SFlowBaseCounterRecord
+	FiveSecCpu  uint32 // 5 second average CPU utilization
+	OneMinCpu   uint32 // 1 minute average CPU utilization
+	FiveMinCpu  uint32 // 5 minute average CPU utilization
+	TotalMemory uint64 // total memory (in bytes)
+	FreeMemory  uint64 // free memory (in bytes)
+}
+
+// **************************************************
+//  Radio Utilization Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Elapsed Time (ms)             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               On Channel Time (ms)            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |             On Channel Busy Time (ms)         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowRadioCounters holds how long a radio was on its channel and how
+// long the channel was busy, all in milliseconds
+type SFlowRadioCounters struct {
+	SFlowBaseCounterRecord
+	ElapsedTime       uint32
+	OnChannelTime     uint32
+	OnChannelBusyTime uint32
+}
+
+// **************************************************
+//
+//	OpenFlow Counter Record
+//
+// **************************************************
+//
+//	0                      15                      31
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|           openflow datapath id  LLLLLLLL      |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|           openflow datapath id  HHHHHHHH      |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//	|                  openflow port                |
+//	+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+type SFlowOFPortCounters struct {
+	SFlowBaseCounterRecord
+	OfDataPathId []byte // OpenFlow Data Path ID For each OVS bridge instances
+	OfPort       uint32 // OpenFlow Port
+}
+
+// **************************************************
+//  OpenFlow Port Name Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 record length                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   skipBytes                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   Port Name                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+type SFlowOFPortNameCounters struct {
+	SFlowBaseCounterRecord
+	OfPortName string // OpenFlow Port Name For each OVS bridge instances
+}
+
+// **************************************************
+//  Host Description Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Hostname Length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                    Hostname                   \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  UUID (16 bytes)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  Machine Type                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    OS Name                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                OS Release Length              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  \                   OS Release                  \
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostDescrCounters identifies the host the agent runs on
+type SFlowHostDescrCounters struct {
+	SFlowBaseCounterRecord
+	Hostname    string
+	UUID        []byte
+	MachineType SFlowMachineType
+	OSName      SFlowOSName
+	OSRelease   string
+}
+
+type SFlowMachineType uint32
+
+const (
+	SFlowMachineUnknown SFlowMachineType = 0
+	SFlowMachineOther   SFlowMachineType = 1
+	SFlowMachineX86     SFlowMachineType = 2
+	SFlowMachineX86_64  SFlowMachineType = 3
+	SFlowMachineIA64    SFlowMachineType = 4
+	SFlowMachineSparc   SFlowMachineType = 5
+	SFlowMachineAlpha   SFlowMachineType = 6
+	SFlowMachinePowerPC SFlowMachineType = 7
+	SFlowMachineM68K    SFlowMachineType = 8
+	SFlowMachineMIPS    SFlowMachineType = 9
+	SFlowMachineARM     SFlowMachineType = 10
+	SFlowMachineHPPA    SFlowMachineType = 11
+	SFlowMachineS390    SFlowMachineType = 12
+)
+
+type SFlowOSName uint32
+
+const (
+	SFlowOSUnknown   SFlowOSName = 0
+	SFlowOSOther     SFlowOSName = 1
+	SFlowOSLinux     SFlowOSName = 2
+	SFlowOSWindows   SFlowOSName = 3
+	SFlowOSDarwin    SFlowOSName = 4
+	SFlowOSHPUX      SFlowOSName = 5
+	SFlowOSAIX       SFlowOSName = 6
+	SFlowOSDragonfly SFlowOSName = 7
+	SFlowOSFreeBSD   SFlowOSName = 8
+	SFlowOSNetBSD    SFlowOSName = 9
+	SFlowOSOpenBSD   SFlowOSName = 10
+	SFlowOSOSF       SFlowOSName = 11
+	SFlowOSSolaris   SFlowOSName = 12
+)
+
+// **************************************************
+//  Host Adapters Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Adapter Count                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /                   Adapters                    /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//
+// each adapter is
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    IfIndex                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   MAC Count                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /         MAC Addresses (padded to 8)           /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostAdaptersCounters lists the network adapters of the host with
+// the ifIndex their interface counters are reported with
+type SFlowHostAdaptersCounters struct {
+	SFlowBaseCounterRecord
+	Adapters []SFlowHostAdapter
+}
+
+type SFlowHostAdapter struct {
+	IfIndex      uint32
+	MacAddresses []net.HardwareAddr
+}
+
+// **************************************************
+//  Host Parent Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 Container Type                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Container Index                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostParentCounters names the data source (sFlow DS class and
+// index) of the physical host a virtual machine runs on
+type SFlowHostParentCounters struct {
+	SFlowBaseCounterRecord
+	ContainerType  SFlowSourceFormat
+	ContainerIndex uint32
+}
+
+// **************************************************
+//  Host CPU Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |        LoadOne / LoadFive / LoadFifteen       |
+//  |                (3 x float32)                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      ProcRun / ProcTotal / CPUNum / CPUSpeed  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Uptime                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |  CPUUser / CPUNice / CPUSystem / CPUIdle (ms) |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   CPUWio / CPUIntr / CPUSintr (ms)            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |             Interrupts / Contexts             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   CPUSteal / CPUGuest / CPUGuestNice (ms)     |
+//  |        (optional, newer agents only)          |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostCPUCounters holds the load averages and the CPU time counters
+// of the host. The CPU times are in milliseconds.
+type SFlowHostCPUCounters struct {
+	SFlowBaseCounterRecord
+	LoadOne      float32
+	LoadFive     float32
+	LoadFifteen  float32
+	ProcRun      uint32
+	ProcTotal    uint32
+	CPUNum       uint32
+	CPUSpeed     uint32 // MHz
+	Uptime       uint32 // seconds
+	CPUUser      uint32
+	CPUNice      uint32
+	CPUSystem    uint32
+	CPUIdle      uint32
+	CPUWio       uint32
+	CPUIntr      uint32
+	CPUSintr     uint32
+	Interrupts   uint32
+	Contexts     uint32
+	CPUSteal     uint32
+	CPUGuest     uint32
+	CPUGuestNice uint32
+}
+
+// **************************************************
+//  Host Memory Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   MemTotal / MemFree / MemShared / MemBuffers |
+//  |    MemCached / SwapTotal / SwapFree (uint64)  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |    PageIn / PageOut / SwapIn / SwapOut        |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostMemoryCounters holds the memory usage of the host in bytes
+type SFlowHostMemoryCounters struct {
+	SFlowBaseCounterRecord
+	MemTotal   uint64
+	MemFree    uint64
+	MemShared  uint64
+	MemBuffers uint64
+	MemCached  uint64
+	SwapTotal  uint64
+	SwapFree   uint64
+	PageIn     uint32
+	PageOut    uint32
+	SwapIn     uint32
+	SwapOut    uint32
+}
+
+// **************************************************
+//  Host Disk I/O Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |         DiskTotal / DiskFree (uint64)         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  PartMaxUsed                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Reads / BytesRead (uint64) / ReadTime       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Writes / BytesWritten (uint64) / WriteTime  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostDiskCounters holds the storage usage and I/O of the host.
+// PartMaxUsed is the utilization of the fullest partition in hundredths
+// of a percent, ReadTime and WriteTime are in milliseconds.
+type SFlowHostDiskCounters struct {
+	SFlowBaseCounterRecord
+	DiskTotal    uint64
+	DiskFree     uint64
+	PartMaxUsed  uint32
+	Reads        uint32
+	BytesRead    uint64
+	ReadTime     uint32
+	Writes       uint32
+	BytesWritten uint64
+	WriteTime    uint32
+}
+
+// **************************************************
+//  Host Network I/O Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                BytesIn (uint64)               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |        PacketsIn / ErrorsIn / DropsIn         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                BytesOut (uint64)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |       PacketsOut / ErrorsOut / DropsOut       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostNetIOCounters holds the traffic of the host summed over all
+// its adapters
+type SFlowHostNetIOCounters struct {
+	SFlowBaseCounterRecord
+	BytesIn    uint64
+	PacketsIn  uint32
+	ErrorsIn   uint32
+	DropsIn    uint32
+	BytesOut   uint64
+	PacketsOut uint32
+	ErrorsOut  uint32
+	DropsOut   uint32
+}
+
+// **************************************************
+//  Host IP Counter Record (MIB-2 ip group)
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPForwarding                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPDefaultTTL                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPInReceives                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPInHdrErrors                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPInAddrErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                IPForwDatagrams                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               IPInUnknownProtos               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPInDiscards                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPInDelivers                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPOutRequests                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPOutDiscards                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPOutNoRoutes                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPReasmTimeout                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPReasmReqds                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IPReasmOKs                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPReasmFails                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   IPFragOKs                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  IPFragFails                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 IPFragCreates                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostIPCounters holds the MIB-2 ip group of the host (RFC 2011)
+type SFlowHostIPCounters struct {
+	SFlowBaseCounterRecord
+	IPForwarding      uint32
+	IPDefaultTTL      uint32
+	IPInReceives      uint32
+	IPInHdrErrors     uint32
+	IPInAddrErrors    uint32
+	IPForwDatagrams   uint32
+	IPInUnknownProtos uint32
+	IPInDiscards      uint32
+	IPInDelivers      uint32
+	IPOutRequests     uint32
+	IPOutDiscards     uint32
+	IPOutNoRoutes     uint32
+	IPReasmTimeout    uint32
+	IPReasmReqds      uint32
+	IPReasmOKs        uint32
+	IPReasmFails      uint32
+	IPFragOKs         uint32
+	IPFragFails       uint32
+	IPFragCreates     uint32
+}
+
+// **************************************************
+//  Host ICMP Counter Record (MIB-2 icmp group)
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   ICMPInMsgs                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  ICMPInErrors                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               ICMPInDestUnreachs              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInTimeExcds                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInParamProbs               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInSrcQuenchs               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInRedirects                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  ICMPInEchos                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 ICMPInEchoReps                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInTimestamps               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPInAddrMasks                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               ICMPInAddrMaskReps              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  ICMPOutMsgs                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 ICMPOutErrors                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              ICMPOutDestUnreachs              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPOutTimeExcds               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               ICMPOutParamProbs               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               ICMPOutSrcQuenchs               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPOutRedirects               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  ICMPOutEchos                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPOutEchoReps                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               ICMPOutTimestamps               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              ICMPOutTimestampReps             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                ICMPOutAddrMasks               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              ICMPOutAddrMaskReps              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostICMPCounters holds the MIB-2 icmp group of the host (RFC 2011)
+type SFlowHostICMPCounters struct {
+	SFlowBaseCounterRecord
+	ICMPInMsgs           uint32
+	ICMPInErrors         uint32
+	ICMPInDestUnreachs   uint32
+	ICMPInTimeExcds      uint32
+	ICMPInParamProbs     uint32
+	ICMPInSrcQuenchs     uint32
+	ICMPInRedirects      uint32
+	ICMPInEchos          uint32
+	ICMPInEchoReps       uint32
+	ICMPInTimestamps     uint32
+	ICMPInAddrMasks      uint32
+	ICMPInAddrMaskReps   uint32
+	ICMPOutMsgs          uint32
+	ICMPOutErrors        uint32
+	ICMPOutDestUnreachs  uint32
+	ICMPOutTimeExcds     uint32
+	ICMPOutParamProbs    uint32
+	ICMPOutSrcQuenchs    uint32
+	ICMPOutRedirects     uint32
+	ICMPOutEchos         uint32
+	ICMPOutEchoReps      uint32
+	ICMPOutTimestamps    uint32
+	ICMPOutTimestampReps uint32
+	ICMPOutAddrMasks     uint32
+	ICMPOutAddrMaskReps  uint32
+}
+
+// **************************************************
+//  Host TCP Counter Record (MIB-2 tcp group)
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                TCPRtoAlgorithm                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPRtoMin                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPRtoMax                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPMaxConn                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 TCPActiveOpens                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                TCPPassiveOpens                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                TCPAttemptFails                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 TCPEstabResets                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  TCPCurrEstab                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPInSegs                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPOutSegs                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 TCPRetransSegs                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPInErrs                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   TCPOutRsts                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                TCPInCsumErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostTCPCounters holds the MIB-2 tcp group of the host (RFC 2012).
+// TCPMaxConn is -1 when the maximum is dynamic.
+type SFlowHostTCPCounters struct {
+	SFlowBaseCounterRecord
+	TCPRtoAlgorithm uint32
+	TCPRtoMin       uint32
+	TCPRtoMax       uint32
+	TCPMaxConn      int32
+	TCPActiveOpens  uint32
+	TCPPassiveOpens uint32
+	TCPAttemptFails uint32
+	TCPEstabResets  uint32
+	TCPCurrEstab    uint32
+	TCPInSegs       uint32
+	TCPOutSegs      uint32
+	TCPRetransSegs  uint32
+	TCPInErrs       uint32
+	TCPOutRsts      uint32
+	TCPInCsumErrors uint32
+}
+
+// **************************************************
+//  Host UDP Counter Record (MIB-2 udp group)
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                 UDPInDatagrams                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   UDPNoPorts                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  UDPInErrors                  |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                UDPOutDatagrams                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                UDPRcvbufErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                UDPSndbufErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                UDPInCsumErrors                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHostUDPCounters holds the MIB-2 udp group of the host (RFC 2013)
+type SFlowHostUDPCounters struct {
+	SFlowBaseCounterRecord
+	UDPInDatagrams  uint32
+	UDPNoPorts      uint32
+	UDPInErrors     uint32
+	UDPOutDatagrams uint32
+	UDPRcvbufErrors uint32
+	UDPSndbufErrors uint32
+	UDPInCsumErrors uint32
+}
+
+// **************************************************
+//  Host Virtual Node Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      MHz                      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      CPUs                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Memory (uint64)                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |              MemoryFree (uint64)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  NumDomains                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVirtNodeCounters describes the hypervisor of a host: its CPUs,
+// the memory available to virtual machines and the number of domains
+type SFlowVirtNodeCounters struct {
+	SFlowBaseCounterRecord
+	MHz        uint32
+	CPUs       uint32
+	Memory     uint64
+	MemoryFree uint64
+	NumDomains uint32
+}
+
+// **************************************************
+//  Host Virtual CPU Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     State                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    CPUTime                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                   NrVirtCPU                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVirtCPUCounters holds the libvirt state (virDomainState) of a
+// virtual machine and the CPU time it used, in milliseconds
+type SFlowVirtCPUCounters struct {
+	SFlowBaseCounterRecord
+	State     uint32
+	CPUTime   uint32
+	NrVirtCPU uint32
+}
+
+// **************************************************
+//  Host Virtual Memory Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Memory (uint64)                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |               MaxMemory (uint64)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVirtMemoryCounters holds the memory used by a virtual machine and
+// the memory it may use, in bytes
+type SFlowVirtMemoryCounters struct {
+	SFlowBaseCounterRecord
+	Memory    uint64
+	MaxMemory uint64
+}
+
+// **************************************************
+//  Host Virtual Disk I/O Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  | Capacity / Allocation / Available (uint64)    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |       ReadRequests / BytesRead (uint64)       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |     WriteRequests / BytesWritten (uint64)     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    Errors                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVirtDiskCounters holds the storage of a virtual machine in bytes
+// and its I/O counters
+type SFlowVirtDiskCounters struct {
+	SFlowBaseCounterRecord
+	Capacity      uint64
+	Allocation    uint64
+	Available     uint64
+	ReadRequests  uint32
+	BytesRead     uint64
+	WriteRequests uint32
+	BytesWritten  uint64
+	Errors        uint32
+}
+
+// **************************************************
+//  Host Virtual Network I/O Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                BytesIn (uint64)               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |        PacketsIn / ErrorsIn / DropsIn         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                BytesOut (uint64)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |       PacketsOut / ErrorsOut / DropsOut       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVirtNetIOCounters holds the traffic of a virtual machine summed
+// over its virtual adapters
+type SFlowVirtNetIOCounters struct {
+	SFlowBaseCounterRecord
+	BytesIn    uint64
+	PacketsIn  uint32
+	ErrorsIn   uint32
+	DropsIn    uint32
+	BytesOut   uint64
+	PacketsOut uint32
+	ErrorsOut  uint32
+	DropsOut   uint32
+}
+
+// **************************************************
+//  Memcache Counter Record (deprecated, 2200)
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Uptime / RusageUser / RusageSystem /        |
+//  |   CurrConnections / TotalConnections /        |
+//  |   ConnectionStructures / CmdGet / CmdSet /    |
+//  |   CmdFlush / Get, Delete, Incr, Decr and      |
+//  |   Cas Misses and Hits / CasBadval /           |
+//  |   AuthCmds / AuthErrors                       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |    BytesRead / BytesWritten (uint64)          |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   LimitMaxbytes / AcceptingConns /            |
+//  |   ListenDisabledNum / Threads / ConnYields    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                Bytes (uint64)                 |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      CurrItems / TotalItems / Evictions       |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowMemcacheLegacyCounters is the first version of the memcached
+// counters, still sent by old agents
+type SFlowMemcacheLegacyCounters struct {
+	SFlowBaseCounterRecord
+	Uptime               uint32
+	RusageUser           uint32
+	RusageSystem         uint32
+	CurrConnections      uint32
+	TotalConnections     uint32
+	ConnectionStructures uint32
+	CmdGet               uint32
+	CmdSet               uint32
+	CmdFlush             uint32
+	GetHits              uint32
+	GetMisses            uint32
+	DeleteMisses         uint32
+	DeleteHits           uint32
+	IncrMisses           uint32
+	IncrHits             uint32
+	DecrMisses           uint32
+	DecrHits             uint32
+	CasMisses            uint32
+	CasHits              uint32
+	CasBadval            uint32
+	AuthCmds             uint32
+	AuthErrors           uint32
+	BytesRead            uint64
+	BytesWritten         uint64
+	LimitMaxbytes        uint32
+	AcceptingConns       uint32
+	ListenDisabledNum    uint32
+	Threads              uint32
+	ConnYields           uint32
+	Bytes                uint64
+	CurrItems            uint32
+	TotalItems           uint32
+	Evictions            uint32
+}
+
+// **************************************************
+//  HTTP Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Method OPTIONS / GET / HEAD / POST / PUT /  |
+//  |   DELETE / TRACE / CONNECT / other counts     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Status 1XX / 2XX / 3XX / 4XX / 5XX /        |
+//  |   other counts                                |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowHTTPCounters counts the requests of an HTTP server by method and
+// by status class
+type SFlowHTTPCounters struct {
+	SFlowBaseCounterRecord
+	MethodOptionCount  uint32
+	MethodGetCount     uint32
+	MethodHeadCount    uint32
+	MethodPostCount    uint32
+	MethodPutCount     uint32
+	MethodDeleteCount  uint32
+	MethodTraceCount   uint32
+	MethodConnectCount uint32
+	MethodOtherCount   uint32
+	Status1XXCount     uint32
+	Status2XXCount     uint32
+	Status3XXCount     uint32
+	Status4XXCount     uint32
+	Status5XXCount     uint32
+	StatusOtherCount   uint32
+}
+
+// **************************************************
+//  Application Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  /              Application (string)             /
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Status OK / OTHER / TIMEOUT /               |
+//  |   INTERNAL_ERROR / BAD_REQUEST / FORBIDDEN /  |
+//  |   TOO_LARGE / NOT_IMPLEMENTED / NOT_FOUND /   |
+//  |   UNAVAILABLE / UNAUTHORIZED counts           |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppCounters counts the operations of an application by status
+type SFlowAppCounters struct {
+	SFlowBaseCounterRecord
+	Application          string
+	StatusOK             uint32
+	StatusOther          uint32
+	StatusTimeout        uint32
+	StatusInternalError  uint32
+	StatusBadRequest     uint32
+	StatusForbidden      uint32
+	StatusTooLarge       uint32
+	StatusNotImplemented uint32
+	StatusNotFound       uint32
+	StatusUnavailable    uint32
+	StatusUnauthorized   uint32
+}
+
+// **************************************************
+//  Application Resources Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |             UserTime / SystemTime             |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |        MemUsed / MemMax (uint64)              |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |  FdOpen / FdMax / ConnOpen / ConnMax          |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppResourcesCounters holds the resources used by an application.
+// UserTime and SystemTime are in milliseconds, the memory in bytes.
+type SFlowAppResourcesCounters struct {
+	SFlowBaseCounterRecord
+	UserTime   uint32
+	SystemTime uint32
+	MemUsed    uint64
+	MemMax     uint64
+	FdOpen     uint32
+	FdMax      uint32
+	ConnOpen   uint32
+	ConnMax    uint32
+}
+
+// **************************************************
+//  Memcache Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   CmdSet / CmdTouch / CmdFlush / Get, Delete, |
+//  |   Incr, Decr and Cas Hits and Misses /        |
+//  |   CasBadval / AuthCmds / AuthErrors /         |
+//  |   Threads / ConnYields / ListenDisabledNum /  |
+//  |   Curr, Rejected and Total Connections /      |
+//  |   ConnectionStructures / Evictions /          |
+//  |   Reclaimed / CurrItems / TotalItems          |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   BytesRead / BytesWritten / Bytes /          |
+//  |   LimitMaxbytes (uint64)                      |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowMemcacheCounters holds the statistics of a memcached server
+type SFlowMemcacheCounters struct {
+	SFlowBaseCounterRecord
+	CmdSet               uint32
+	CmdTouch             uint32
+	CmdFlush             uint32
+	GetHits              uint32
+	GetMisses            uint32
+	DeleteHits           uint32
+	DeleteMisses         uint32
+	IncrHits             uint32
+	IncrMisses           uint32
+	DecrHits             uint32
+	DecrMisses           uint32
+	CasHits              uint32
+	CasMisses            uint32
+	CasBadval            uint32
+	AuthCmds             uint32
+	AuthErrors           uint32
+	Threads              uint32
+	ConnYields           uint32
+	ListenDisabledNum    uint32
+	CurrConnections      uint32
+	RejectedConnections  uint32
+	TotalConnections     uint32
+	ConnectionStructures uint32
+	Evictions            uint32
+	Reclaimed            uint32
+	CurrItems            uint32
+	TotalItems           uint32
+	BytesRead            uint64
+	BytesWritten         uint64
+	Bytes                uint64
+	LimitMaxbytes        uint64
+}
+
+// **************************************************
+//  VDI Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Sessions Current / Total / Duration         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Rx, Tx Bytes / Rx, Tx Packets /             |
+//  |   Rx, Tx Packets Lost                         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   RTT Min / Max / Avg (ms)                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Audio Rx, Tx Bytes / Audio Tx Limit         |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   Image Rx, Tx Bytes / Frames /               |
+//  |   Quality Min / Max / Avg                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   USB Rx, Tx Bytes                            |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowVDICounters holds the session statistics of a virtual desktop
+// server
+type SFlowVDICounters struct {
+	SFlowBaseCounterRecord
+	SessionsCurrent  uint32
+	SessionsTotal    uint32
+	SessionsDuration uint32
+	RxBytes          uint32
+	TxBytes          uint32
+	RxPackets        uint32
+	TxPackets        uint32
+	RxPacketsLost    uint32
+	TxPacketsLost    uint32
+	RTTMinMs         uint32
+	RTTMaxMs         uint32
+	RTTAvgMs         uint32
+	AudioRxBytes     uint32
+	AudioTxBytes     uint32
+	AudioTxLimit     uint32
+	ImgRxBytes       uint32
+	ImgTxBytes       uint32
+	ImgFrames        uint32
+	ImgQualMin       uint32
+	ImgQualMax       uint32
+	ImgQualAvg       uint32
+	UsbRxBytes       uint32
+	UsbTxBytes       uint32
+}
+
+// **************************************************
+//  Application Workers Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |   WorkersActive / WorkersIdle / WorkersMax    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |         RequestsDelayed / RequestsDropped     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowAppWorkersCounters holds the worker pool of an application
+type SFlowAppWorkersCounters struct {
+	SFlowBaseCounterRecord
+	WorkersActive   uint32
+	WorkersIdle     uint32
+	WorkersMax      uint32
+	RequestsDelayed uint32
+	RequestsDropped uint32
+}
+
+// **************************************************
+//  Open vSwitch Datapath Counter Record
+// **************************************************
+//  0                      15                      31
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |      20 bit Interprise (0)     |12 bit format |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                  counter length               |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      Hits                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Misses                    |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                      Lost                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                    MaskHits                   |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Flows                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+//  |                     Masks                     |
+//  +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+
+// SFlowOVSDPCounters holds the kernel datapath statistics of Open
+// vSwitch: packets that hit the flow cache, missed it (an upcall to
+// ovs-vswitchd) or were lost before reaching userspace, the masks looked
+// up, and the number of cached flows and masks
+type SFlowOVSDPCounters struct {
+	SFlowBaseCounterRecord
+	Hits     uint32
+	Misses   uint32
+	Lost     uint32
+	MaskHits uint32
+	Flows    uint32
+	Masks    uint32
+}
+
+// ****************************************************************************************************
+//  Decode Flow and Counters type
+// ****************************************************************************************************
+
+func (sdf SFlowDataFormat) decode() (SFlowEnterpriseID, SFlowSampleType) {
+	leftField := sdf >> 12
+	rightField := uint32(0xFFF) & uint32(sdf)
+	return SFlowEnterpriseID(leftField), SFlowSampleType(rightField)
+}
+
+// the expanded data source keeps the format and the value in a word each
+func (sdce SFlowDataSourceExpanded) decode() (SFlowSourceFormat, SFlowSourceValue) {
+	return sdce.SourceIDClass, sdce.SourceIDIndex
+}
+
+func (sdc SFlowDataSource) decode() (SFlowSourceFormat, SFlowSourceValue) {
+	leftField := uint32(sdc) >> 24
+	rightField := uint32(0x00FFFFFF) & uint32(sdc)
+	return SFlowSourceFormat(leftField), SFlowSourceValue(rightField)
+}
+
+func (cdf SFlowCounterDataFormat) decode() (SFlowEnterpriseID, SFlowCounterRecordType) {
+	leftField := cdf >> 12
+	rightField := uint32(0xFFF) & uint32(cdf)
+	return SFlowEnterpriseID(leftField), SFlowCounterRecordType(rightField)
+}
+
+func (fdf SFlowFlowDataFormat) decode() (SFlowEnterpriseID, SFlowFlowRecordType) {
+	leftField := fdf >> 12
+	rightField := uint32(0xFFF) & uint32(fdf)
+	return SFlowEnterpriseID(leftField), SFlowFlowRecordType(rightField)
+}
+
+func (ad *SFlowASDestination) decodePath(data *[]byte) error {
+	*data, ad.Type = (*data)[4:], SFlowASPathType(binary.BigEndian.Uint32((*data)[:4]))
+	*data, ad.Count = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+	if err := checkSFlowArray(*data, ad.Count, 4, "AS path"); err != nil {
+		return err
+	}
+	ad.Members = make([]uint32, ad.Count)
+	for i := uint32(0); i < ad.Count; i++ {
+		var member uint32
+		*data, member = (*data)[4:], binary.BigEndian.Uint32((*data)[:4])
+		ad.Members[i] = member
+	}
+	return nil
+}
+
+// *********************************************************************
+//
+//	SFLOW To String
+//
+// *********************************************************************
+func (rt SFlowFlowRecordType) String() string {
+	switch rt {
+	case SFlowTypeRawPacketFlow:
+		return "Raw Packet Flow Record"
+	case SFlowTypeEthernetFrameFlow:
+		return "Ethernet Frame Flow Record"
+	case SFlowTypeIpv4Flow:
+		return "IPv4 Flow Record"
+	case SFlowTypeIpv6Flow:
+		return "IPv6 Flow Record"
+	case SFlowTypeExtendedSwitchFlow:
+		return "Extended Switch Flow Record"
+	case SFlowTypeExtendedRouterFlow:
+		return "Extended Router Flow Record"
+	case SFlowTypeExtendedGatewayFlow:
+		return "Extended Gateway Flow Record"
+	case SFlowTypeExtendedUserFlow:
+		return "Extended User Flow Record"
+	case SFlowTypeExtendedUrlFlow:
+		return "Extended URL Flow Record"
+	case SFlowTypeExtendedMlpsFlow:
+		return "Extended MPLS Flow Record"
+	case SFlowTypeExtendedNatFlow:
+		return "Extended NAT Flow Record"
+	case SFlowTypeExtendedMlpsTunnelFlow:
+		return "Extended MPLS Tunnel Flow Record"
+	case SFlowTypeExtendedMlpsVcFlow:
+		return "Extended MPLS VC Flow Record"
+	case SFlowTypeExtendedMlpsFecFlow:
+		return "Extended MPLS FEC Flow Record"
+	case SFlowTypeExtendedMlpsLvpFecFlow:
+		return "Extended MPLS LVP FEC Flow Record"
+	case SFlowTypeExtendedVlanFlow:
+		return "Extended VLAN Flow Record"
+	case SFlowTypeExtendedIpv4TunnelEgressFlow:
+		return "Extended IPv4 Tunnel Egress Record"
+	case SFlowTypeExtendedIpv4TunnelIngressFlow:
+		return "Extended IPv4 Tunnel Ingress Record"
+	case SFlowTypeExtendedIpv6TunnelEgressFlow:
+		return "Extended IPv6 Tunnel Egress Record"
+	case SFlowTypeExtendedIpv6TunnelIngressFlow:
+		return "Extended IPv6 Tunnel Ingress Record"
+	case SFlowTypeExtendedDecapsulateEgressFlow:
+		return "Extended Decapsulate Egress Record"
+	case SFlowTypeExtendedDecapsulateIngressFlow:
+		return "Extended Decapsulate Ingress Record"
+	case SFlowTypeExtendedVniEgressFlow:
+		return "Extended VNI Ingress Record"
+	case SFlowTypeExtendedVniIngressFlow:
+		return "Extended VNI Ingress Record"
+	case SFlowTypeExtended80211PayloadFlow:
+		return "Extended 802.11 Payload Record"
+	case SFlowTypeExtended80211RxFlow:
+		return "Extended 802.11 RX Record"
+	case SFlowTypeExtended80211TxFlow:
+		return "Extended 802.11 TX Record"
+	case SFlowTypeExtended80211AggregationFlow:
+		return "Extended 802.11 Aggregation Record"
+	case SFlowTypeExtendedEgressQueueFlow:
+		return "Extended Egress Queue Record"
+	case SFlowTypeExtendedTransitFlow:
+		return "Extended Transit Delay Record"
+	case SFlowTypeExtendedQueueFlow:
+		return "Extended Queue Depth Record"
+	case SFlowTypeExtendedFunctionFlow:
+		return "Extended Function Record"
+	case SFlowTypeExtendedSocketIpv4Flow:
+		return "Extended Socket IPv4 Record"
+	case SFlowTypeExtendedSocketIpv6Flow:
+		return "Extended Socket IPv6 Record"
+	case SFlowTypeMemcacheFlow:
+		return "Memcache Operation Record"
+	case SFlowTypeHTTPFlow, SFlowTypeHTTP2Flow:
+		return "HTTP Request Record"
+	case SFlowTypeAppOperationFlow:
+		return "Application Operation Record"
+	case SFlowTypeAppParentContextFlow:
+		return "Application Parent Context Record"
+	case SFlowTypeAppInitiatorFlow:
+		return "Application Initiator Record"
+	case SFlowTypeAppTargetFlow:
+		return "Application Target Record"
+	case SFlowTypeExtendedTCPInfoFlow:
+		return "Extended TCP Info Record"
+	default:
+		return ""
+	}
+}
+
+// Name is the RecordType of the records of this format, "" for the
+// formats this collector does not know
+func (rt SFlowFlowRecordType) Name() string {
+	switch rt {
+	case SFlowTypeRawPacketFlow:
+		return "raw_packet"
+	case SFlowTypeEthernetFrameFlow:
+		return "ethernet_frame"
+	case SFlowTypeIpv4Flow:
+		return "ipv4"
+	case SFlowTypeIpv6Flow:
+		return "ipv6"
+	case SFlowTypeExtendedSwitchFlow:
+		return "extended_switch"
+	case SFlowTypeExtendedRouterFlow:
+		return "extended_router"
+	case SFlowTypeExtendedGatewayFlow:
+		return "extended_gateway"
+	case SFlowTypeExtendedUserFlow:
+		return "extended_user"
+	case SFlowTypeExtendedUrlFlow:
+		return "extended_url"
+	case SFlowTypeExtendedMlpsFlow:
+		return "extended_mpls"
+	case SFlowTypeExtendedNatFlow:
+		return "extended_nat"
+	case SFlowTypeExtendedMlpsTunnelFlow:
+		return "extended_mpls_tunnel"
+	case SFlowTypeExtendedMlpsVcFlow:
+		return "extended_mpls_vc"
+	case SFlowTypeExtendedMlpsFecFlow:
+		return "extended_mpls_fec"
+	case SFlowTypeExtendedMlpsLvpFecFlow:
+		return "extended_mpls_lvp_fec"
+	case SFlowTypeExtendedVlanFlow:
+		return "extended_vlan_tunnel"
+	case SFlowTypeExtended80211PayloadFlow:
+		return "extended_80211_payload"
+	case SFlowTypeExtended80211RxFlow:
+		return "extended_80211_rx"
+	case SFlowTypeExtended80211TxFlow:
+		return "extended_80211_tx"
+	case SFlowTypeExtended80211AggregationFlow:
+		return "extended_80211_aggregation"
+	case SFlowTypeExtendedIpv4TunnelEgressFlow:
+		return "extended_ipv4_tunnel_egress"
+	case SFlowTypeExtendedIpv4TunnelIngressFlow:
+		return "extended_ipv4_tunnel_ingress"
+	case SFlowTypeExtendedIpv6TunnelEgressFlow:
+		return "extended_ipv6_tunnel_egress"
+	case SFlowTypeExtendedIpv6TunnelIngressFlow:
+		return "extended_ipv6_tunnel_ingress"
+	case SFlowTypeExtendedDecapsulateEgressFlow:
+		return "extended_decapsulate_egress"
+	case SFlowTypeExtendedDecapsulateIngressFlow:
+		return "extended_decapsulate_ingress"
+	case SFlowTypeExtendedVniEgressFlow:
+		return "extended_vni_egress"
+	case SFlowTypeExtendedVniIngressFlow:
+		return "extended_vni_ingress"
+	case SFlowTypeExtendedEgressQueueFlow:
+		return "extended_egress_queue"
+	case SFlowTypeExtendedTransitFlow:
+		return "extended_transit"
+	case SFlowTypeExtendedQueueFlow:
+		return "extended_queue"
+	case SFlowTypeExtendedFunctionFlow:
+		return "extended_function"
+	case SFlowTypeExtendedSocketIpv4Flow:
+		return "extended_socket_ipv4"
+	case SFlowTypeExtendedSocketIpv6Flow:
+		return "extended_socket_ipv6"
+	case SFlowTypeMemcacheFlow:
+		return "memcache"
+	case SFlowTypeHTTPFlow:
+		return "http_request"
+	case SFlowTypeAppOperationFlow:
+		return "app_operation"
+	case SFlowTypeAppParentContextFlow:
+		return "app_parent_context"
+	case SFlowTypeAppInitiatorFlow:
+		return "app_initiator"
+	case SFlowTypeAppTargetFlow:
+		return "app_target"
+	case SFlowTypeHTTP2Flow:
+		return "http_request_xff"
+	case SFlowTypeExtendedTCPInfoFlow:
+		return "extended_tcp_info"
+	default:
+		return ""
+	}
+}
+
+// Name is the RecordType of the records of this format, "" for the
+// formats this collector does not know
+func (ct SFlowCounterRecordType) Name() string {
+	switch ct {
+	case SFlowTypeGenericInterfaceCounters:
+		return "generic_interface_counters"
+	case SFlowTypeEthernetInterfaceCounters:
+		return "ethernet_interface_counters"
+	case SFlowTypeTokenRingInterfaceCounters:
+		return "token_ring_counters"
+	case SFlowType100BaseVGInterfaceCounters:
+		return "100basevg_interface_counters"
+	case SFlowTypeVLANCounters:
+		return "vlan_counters"
+	case SFlowType80211Counters:
+		return "80211_counters"
+	case SFlowTypeLACPCounters:
+		return "lacp_counters"
+	case SFlowTypeSFPCounters:
+		return "sfp_counters"
+	case SFlowTypeProcessorCounters:
+		return "processor_counters"
+	case SFlowTypeRadioCounters:
+		return "radio_counters"
+	case SFlowTypeOFPortCounter:
+		return "of_port_counters"
+	case SFlowTypeOFPortNameCounter:
+		return "of_port_name_counters"
+	case SFlowTypeHostDescrCounters:
+		return "host_descr_counters"
+	case SFlowTypeHostAdaptersCounters:
+		return "host_adapters_counters"
+	case SFlowTypeHostParentCounters:
+		return "host_parent_counters"
+	case SFlowTypeHostCPUCounters:
+		return "host_cpu_counters"
+	case SFlowTypeHostMemoryCounters:
+		return "host_memory_counters"
+	case SFlowTypeHostDiskCounters:
+		return "host_disk_counters"
+	case SFlowTypeHostNetIOCounters:
+		return "host_net_io_counters"
+	case SFlowTypeHostIPCounters:
+		return "host_ip_counters"
+	case SFlowTypeHostICMPCounters:
+		return "host_icmp_counters"
+	case SFlowTypeHostTCPCounters:
+		return "host_tcp_counters"
+	case SFlowTypeHostUDPCounters:
+		return "host_udp_counters"
+	case SFlowTypeVirtNodeCounters:
+		return "virt_node_counters"
+	case SFlowTypeVirtCPUCounters:
+		return "virt_cpu_counters"
+	case SFlowTypeVirtMemoryCounters:
+		return "virt_memory_counters"
+	case SFlowTypeVirtDiskCounters:
+		return "virt_disk_counters"
+	case SFlowTypeVirtNetIOCounters:
+		return "virt_net_io_counters"
+	case SFlowTypeMemcacheLegacyCounters:
+		return "memcache_legacy_counters"
+	case SFlowTypeHTTPCounters:
+		return "http_counters"
+	case SFlowTypeAppCounters:
+		return "app_counters"
+	case SFlowTypeAppResourcesCounters:
+		return "app_resources_counters"
+	case SFlowTypeMemcacheCounters:
+		return "memcache_counters"
+	case SFlowTypeVDICounters:
+		return "vdi_counters"
+	case SFlowTypeAppWorkersCounters:
+		return "app_workers_counters"
+	case SFlowTypeOVSDPCounters:
+		return "ovs_dp_counters"
+	default:
+		return ""
+	}
+}
+
+func (sfhp SFlowRawHeaderProtocol) String() string {
+	switch sfhp {
+	case SFlowProtoEthernet:
+		return "ETHERNET-ISO88023"
+	case SFlowProtoISO88024:
+		return "ISO88024-TOKENBUS"
+	case SFlowProtoISO88025:
+		return "ISO88025-TOKENRING"
+	case SFlowProtoFDDI:
+		return "FDDI"
+	case SFlowProtoFrameRelay:
+		return "FRAME-RELAY"
+	case SFlowProtoX25:
+		return "X25"
+	case SFlowProtoPPP:
+		return "PPP"
+	case SFlowProtoSMDS:
+		return "SMDS"
+	case SFlowProtoAAL5:
+		return "AAL5"
+	case SFlowProtoAAL5_IP:
+		return "AAL5-IP"
+	case SFlowProtoIPv4:
+		return "IPv4"
+	case SFlowProtoIPv6:
+		return "IPv6"
+	case SFlowProtoMPLS:
+		return "MPLS"
+	case SFlowProtoPOS:
+		return "POS"
+	case SFlowProto80211MAC:
+		return "80211-MAC"
+	case SFlowProto80211AMPDU:
+		return "80211-AMPDU"
+	case SFlowProto80211AMSDU:
+		return "80211-AMSDU"
+	}
+	return "UNKNOWN"
+}
+
+func (urld SFlowURLDirection) String() string {
+	switch urld {
+	case SFlowURLsrc:
+		return "Source address is the server"
+	case SFlowURLdst:
+		return "Destination address is the server"
+	default:
+		return ""
+	}
+}
+
+func (apt SFlowASPathType) String() string {
+	switch apt {
+	case SFlowASSet:
+		return "AS Set"
+	case SFlowASSequence:
+		return "AS Sequence"
+	default:
+		return ""
+	}
+}
+
+func (asd SFlowASDestination) String() string {
+	switch asd.Type {
+	case SFlowASSet:
+		return fmt.Sprint("AS Set:", asd.Members)
+	case SFlowASSequence:
+		return fmt.Sprint("AS Sequence:", asd.Members)
+	default:
+		return ""
+	}
+}
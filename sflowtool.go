@@ -0,0 +1,564 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  sflowtool Output
+// ****************************************************************************************************
+
+// The -output text and line datagrams are printed in the formats of sflowtool, so the scripts
+// written for it read the collector output:
+//
+//   text    sflowtool's default key / value lines, one block per datagram
+//           and sample, one "flowBlock_tag" / "counterBlock_tag" per record
+//   line    sflowtool -l, a FLOW line per flow sample and a CNTR line per
+//           generic interface counters record
+//
+// The records sflowtool does not know (host, application, wireless, ...)
+// are printed with their field names. The line format adds an OFPORT line
+// per counter sample with an OpenFlow port record:
+//
+//   OFPORT,agent,ifIndex,datapathId,ofPort,portName
+
+// xnfvSFlowToolWriter writes a key / value text line for each field,
+// after indent
+type xnfvSFlowToolWriter struct {
+	w      *bufio.Writer
+	indent string
+}
+
+func (t xnfvSFlowToolWriter) field(key string, value interface{}) {
+	fmt.Fprintf(t.w, "%s%s %v\n", t.indent, key, value)
+}
+
+// writeSFlowToolText writes the datagram in the text format of sflowtool,
+// source and size are the sender and the length of its UDP payload, the
+// samples keep their order in the datagram
+func writeSFlowToolText(w io.Writer, source net.IP, size int, received time.Time, datagram *sflow.Datagram) error {
+	t := xnfvSFlowToolWriter{w: bufio.NewWriter(w)}
+	t.field("startDatagram", "=================================")
+	t.field("datagramSourceIP", source)
+	t.field("datagramSize", size)
+	t.field("unixSecondsUTC", received.Unix())
+	t.field("localtime", received.Format("2006-01-02T15:04:05-0700"))
+	t.field("datagramVersion", datagram.DatagramVersion)
+	t.field("agentSubId", datagram.SubAgentID)
+	t.field("agent", sflowToolAddress(datagram.AgentAddress))
+	t.field("packetSequenceNo", datagram.SequenceNumber)
+	t.field("sysUpTime", datagram.AgentUptime)
+	t.field("samplesInPacket", datagram.SampleCount)
+	datagram.EachSample(t.flowSample, t.counterSample, t.discardSample)
+	t.field("endDatagram", "  =================================")
+	return t.w.Flush()
+}
+
+func (t xnfvSFlowToolWriter) flowSample(s sflow.SFlowFlowSample) error {
+	t.field("startSample", "----------------------")
+	t.field("sampleType_tag", fmt.Sprintf("%d:%d", s.EnterpriseID, s.Format))
+	expanded := s.Format == sflow.SFlowTypeExpandedFlowSample
+	if expanded {
+		t.field("sampleType", "FLOWSAMPLE_EXPANDED")
+	} else {
+		t.field("sampleType", "FLOWSAMPLE")
+	}
+	t.field("sampleSequenceNo", s.SequenceNumber)
+	t.field("sourceId", fmt.Sprintf("%d:%d", s.SourceIDClass, s.SourceIDIndex))
+	t.field("meanSkipCount", s.SamplingRate)
+	t.field("samplePool", s.SamplePool)
+	t.field("dropEvents", s.Dropped)
+	if expanded {
+		t.field("inputPortFormat", s.InputInterfaceFormat)
+		t.field("inputPort", s.InputInterface)
+		t.field("outputPortFormat", s.OutputInterfaceFormat)
+		t.field("outputPort", s.OutputInterface)
+	} else {
+		t.field("inputPort", s.InputInterface)
+		// the top bits of the compact output port flag multiple outputs
+		if s.OutputInterface&0x80000000 != 0 {
+			if outputs := s.OutputInterface & 0x7fffffff; outputs > 0 {
+				t.field("outputPort", fmt.Sprintf("multiple %d", outputs))
+			} else {
+				t.field("outputPort", "multiple >1")
+			}
+		} else {
+			t.field("outputPort", s.OutputInterface)
+		}
+	}
+	t.flowRecords(s.Records)
+	t.field("endSample", "  ----------------------")
+	return nil
+}
+
+func (t xnfvSFlowToolWriter) counterSample(s sflow.SFlowCounterSample) error {
+	t.field("startSample", "----------------------")
+	t.field("sampleType_tag", fmt.Sprintf("%d:%d", s.EnterpriseID, s.Format))
+	if s.Format == sflow.SFlowTypeExpandedCounterSample {
+		t.field("sampleType", "COUNTERSSAMPLE_EXPANDED")
+	} else {
+		t.field("sampleType", "COUNTERSSAMPLE")
+	}
+	t.field("sampleSequenceNo", s.SequenceNumber)
+	t.field("sourceId", fmt.Sprintf("%d:%d", s.SourceIDClass, s.SourceIDIndex))
+	for _, record := range s.Records {
+		t.field("counterBlock_tag", fmt.Sprintf("%d:%d", record.RecordEnterprise(), record.RecordFormat()))
+		t.counterRecord(record)
+	}
+	t.field("endSample", "  ----------------------")
+	return nil
+}
+
+func (t xnfvSFlowToolWriter) discardSample(s sflow.SFlowDiscardSample) error {
+	t.field("startSample", "----------------------")
+	t.field("sampleType_tag", fmt.Sprintf("%d:%d", s.EnterpriseID, s.Format))
+	t.field("sampleType", "EVENT_DISCARDED_PACKET")
+	t.field("sampleSequenceNo", s.SequenceNumber)
+	t.field("sourceId", fmt.Sprintf("%d:%d", s.SourceIDClass, s.SourceIDIndex))
+	t.field("dropEvents", s.Drops)
+	t.field("inputPort", s.InputInterface)
+	t.field("outputPort", s.OutputInterface)
+	t.field("discardCode", uint32(s.Reason))
+	t.field("discardReason", s.Reason)
+	t.flowRecords(s.Records)
+	t.field("endSample", "  ----------------------")
+	return nil
+}
+
+func (t xnfvSFlowToolWriter) flowRecords(records sflow.SFlowRecords) {
+	for _, record := range records {
+		t.field("flowBlock_tag", fmt.Sprintf("%d:%d", record.RecordEnterprise(), record.RecordFormat()))
+		t.flowRecord(record)
+	}
+}
+
+func (t xnfvSFlowToolWriter) flowRecord(record sflow.SFlowRecord) {
+	switch r := record.(type) {
+	case sflow.SFlowRawPacketFlowRecord:
+		t.field("flowSampleType", "HEADER")
+		t.field("headerProtocol", uint32(r.HeaderProtocol))
+		t.field("sampledPacketSize", r.FrameLength)
+		t.field("strippedBytes", r.PayloadRemoved)
+		t.field("headerLen", r.HeaderLength)
+		t.field("headerBytes", sflowToolHex(r.ParsedHeader.Data))
+		if r.ParsedHeader.Packet != nil {
+			t.header(r.ParsedHeader.Packet)
+		}
+	case sflow.SFlowEthernetFrameFlowRecord:
+		t.field("flowSampleType", "ETHERNET")
+		t.field("ethernet_type", r.Type)
+		t.field("ethernet_len", r.FrameLength)
+		t.field("ethernet_src", sflowToolMAC(r.SrcMac))
+		t.field("ethernet_dst", sflowToolMAC(r.DstMac))
+	case sflow.SFlowIpv4FlowRecord:
+		t.field("flowSampleType", "IPV4")
+		t.ipRecord(r.SFlowIpv4Record.Length, r.IPSrc, r.IPDst, "srcIP", "dstIP", r.Protocol, r.PortSrc, r.PortDst, r.TCPFlags)
+		t.field("IPTOS", r.TOS)
+	case sflow.SFlowIpv6FlowRecord:
+		t.field("flowSampleType", "IPV6")
+		t.ipRecord(r.SFlowIpv6Record.Length, r.IPSrc, r.IPDst, "srcIP6", "dstIP6", r.Protocol, r.PortSrc, r.PortDst, r.TCPFlags)
+		t.field("IPPriority", r.Priority)
+	case sflow.SFlowExtendedSwitchFlowRecord:
+		t.field("extendedType", "SWITCH")
+		t.field("in_vlan", r.IncomingVLAN)
+		t.field("in_priority", r.IncomingVLANPriority)
+		t.field("out_vlan", r.OutgoingVLAN)
+		t.field("out_priority", r.OutgoingVLANPriority)
+	case sflow.SFlowExtendedRouterFlowRecord:
+		t.field("extendedType", "ROUTER")
+		t.field("nextHop", sflowToolAddress(r.NextHop))
+		t.field("srcSubnetMask", r.NextHopSourceMask)
+		t.field("dstSubnetMask", r.NextHopDestinationMask)
+	case sflow.SFlowExtendedGatewayFlowRecord:
+		t.field("extendedType", "GATEWAY")
+		t.field("nextHop", sflowToolAddress(r.NextHop))
+		t.field("my_as", r.AS)
+		t.field("src_as", r.SourceAS)
+		t.field("src_peer_as", r.PeerAS)
+		t.field("dst_as_path_len", r.ASPathCount)
+		var path []string
+		for _, segment := range r.ASPath {
+			for _, member := range segment.Members {
+				path = append(path, fmt.Sprint(member))
+			}
+		}
+		t.field("dst_as_path", strings.Join(path, "-"))
+		t.field("BGP_communities", sflowToolUint32s(r.Communities))
+		t.field("BGP_localpref", r.LocalPref)
+	case sflow.SFlowExtendedUserFlow:
+		t.field("extendedType", "USER")
+		t.field("src_user_charset", uint32(r.SourceCharSet))
+		t.field("src_user", r.SourceUserID)
+		t.field("dst_user_charset", uint32(r.DestinationCharSet))
+		t.field("dst_user", r.DestinationUserID)
+	case sflow.SFlowExtendedURLRecord:
+		t.field("extendedType", "URL")
+		t.field("url_direction", uint32(r.Direction))
+		t.field("url", r.URL)
+		t.field("host", r.Host)
+	default:
+		t.field("flowSampleType", record.RecordType())
+		t.fields("", reflect.ValueOf(record))
+	}
+}
+
+// header writes the fields sflowtool decodes from a sampled header
+func (t xnfvSFlowToolWriter) header(packet gopacket.Packet) {
+	if eth, ok := packet.Layer(layers.LayerTypeEthernet).(*layers.Ethernet); ok {
+		t.field("dstMAC", sflowToolMAC(eth.DstMAC))
+		t.field("srcMAC", sflowToolMAC(eth.SrcMAC))
+	}
+	if dot1q, ok := packet.Layer(layers.LayerTypeDot1Q).(*layers.Dot1Q); ok {
+		t.field("decodedVLAN", dot1q.VLANIdentifier)
+		t.field("decodedPriority", dot1q.Priority)
+	}
+	if ip, ok := packet.Layer(layers.LayerTypeIPv4).(*layers.IPv4); ok {
+		t.field("IPSize", ip.Length)
+		t.field("ip.tot_len", ip.Length)
+		t.field("srcIP", ip.SrcIP)
+		t.field("dstIP", ip.DstIP)
+		t.field("IPProtocol", uint8(ip.Protocol))
+		t.field("IPTOS", ip.TOS)
+		t.field("IPTTL", ip.TTL)
+		t.field("IPID", ip.Id)
+	} else if ip, ok := packet.Layer(layers.LayerTypeIPv6).(*layers.IPv6); ok {
+		t.field("IPSize", uint32(ip.Length)+40)
+		t.field("ip.tot_len", uint32(ip.Length)+40)
+		t.field("srcIP6", ip.SrcIP)
+		t.field("dstIP6", ip.DstIP)
+		t.field("IPProtocol", uint8(ip.NextHeader))
+		t.field("IPTOS", ip.TrafficClass)
+		t.field("IPTTL", ip.HopLimit)
+		t.field("IPFlowLabel", ip.FlowLabel)
+	}
+	if tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP); ok {
+		t.field("TCPSrcPort", uint16(tcp.SrcPort))
+		t.field("TCPDstPort", uint16(tcp.DstPort))
+		t.field("TCPFlags", sflowToolTCPFlags(tcp))
+	} else if udp, ok := packet.Layer(layers.LayerTypeUDP).(*layers.UDP); ok {
+		t.field("UDPSrcPort", uint16(udp.SrcPort))
+		t.field("UDPDstPort", uint16(udp.DstPort))
+		t.field("UDPBytes", udp.Length)
+	} else if icmp, ok := packet.Layer(layers.LayerTypeICMPv4).(*layers.ICMPv4); ok {
+		t.field("ICMPType", icmp.TypeCode.Type())
+		t.field("ICMPCode", icmp.TypeCode.Code())
+	} else if icmp, ok := packet.Layer(layers.LayerTypeICMPv6).(*layers.ICMPv6); ok {
+		t.field("ICMPType", icmp.TypeCode.Type())
+		t.field("ICMPCode", icmp.TypeCode.Code())
+	}
+}
+
+func (t xnfvSFlowToolWriter) ipRecord(length uint32, src, dst net.IP, srcKey, dstKey string, protocol, srcPort, dstPort, tcpFlags uint32) {
+	t.field("sampledPacketSize", length)
+	t.field("IPSize", length)
+	t.field(srcKey, sflowToolAddress(src))
+	t.field(dstKey, sflowToolAddress(dst))
+	t.field("IPProtocol", protocol)
+	switch layers.IPProtocol(protocol) {
+	case layers.IPProtocolTCP:
+		t.field("TCPSrcPort", srcPort)
+		t.field("TCPDstPort", dstPort)
+		t.field("TCPFlags", tcpFlags)
+	case layers.IPProtocolUDP:
+		t.field("UDPSrcPort", srcPort)
+		t.field("UDPDstPort", dstPort)
+	}
+}
+
+func (t xnfvSFlowToolWriter) counterRecord(record sflow.SFlowRecord) {
+	switch r := record.(type) {
+	case sflow.SFlowGenericInterfaceCounters:
+		t.field("ifIndex", r.IfIndex)
+		t.field("networkType", r.IfType)
+		t.field("ifSpeed", r.IfSpeed)
+		t.field("ifDirection", r.IfDirection)
+		t.field("ifStatus", r.IfStatus)
+		t.field("ifInOctets", r.IfInOctets)
+		t.field("ifInUcastPkts", r.IfInUcastPkts)
+		t.field("ifInMulticastPkts", r.IfInMulticastPkts)
+		t.field("ifInBroadcastPkts", r.IfInBroadcastPkts)
+		t.field("ifInDiscards", r.IfInDiscards)
+		t.field("ifInErrors", r.IfInErrors)
+		t.field("ifInUnknownProtos", r.IfInUnknownProtos)
+		t.field("ifOutOctets", r.IfOutOctets)
+		t.field("ifOutUcastPkts", r.IfOutUcastPkts)
+		t.field("ifOutMulticastPkts", r.IfOutMulticastPkts)
+		t.field("ifOutBroadcastPkts", r.IfOutBroadcastPkts)
+		t.field("ifOutDiscards", r.IfOutDiscards)
+		t.field("ifOutErrors", r.IfOutErrors)
+		t.field("ifPromiscuousMode", r.IfPromiscuousMode)
+	case sflow.SFlowEthernetCounters:
+		t.field("dot3StatsAlignmentErrors", r.AlignmentErrors)
+		t.field("dot3StatsFCSErrors", r.FCSErrors)
+		t.field("dot3StatsSingleCollisionFrames", r.SingleCollisionFrames)
+		t.field("dot3StatsMultipleCollisionFrames", r.MultipleCollisionFrames)
+		t.field("dot3StatsSQETestErrors", r.SQETestErrors)
+		t.field("dot3StatsDeferredTransmissions", r.DeferredTransmissions)
+		t.field("dot3StatsLateCollisions", r.LateCollisions)
+		t.field("dot3StatsExcessiveCollisions", r.ExcessiveCollisions)
+		t.field("dot3StatsInternalMacTransmitErrors", r.InternalMacTransmitErrors)
+		t.field("dot3StatsCarrierSenseErrors", r.CarrierSenseErrors)
+		t.field("dot3StatsFrameTooLongs", r.FrameTooLongs)
+		t.field("dot3StatsInternalMacReceiveErrors", r.InternalMacReceiveErrors)
+		t.field("dot3StatsSymbolErrors", r.SymbolErrors)
+	case sflow.SFlowVLANCounters:
+		t.field("in_vlan", r.VlanID)
+		t.field("octets", r.Octets)
+		t.field("ucastPkts", r.UcastPkts)
+		t.field("multicastPkts", r.MulticastPkts)
+		t.field("broadcastPkts", r.BroadcastPkts)
+		t.field("discards", r.Discards)
+	case sflow.SFlowProcessorCounters:
+		t.field("5s_cpu", r.FiveSecCpu)
+		t.field("1m_cpu", r.OneMinCpu)
+		t.field("5m_cpu", r.FiveMinCpu)
+		t.field("total_memory_bytes", r.TotalMemory)
+		t.field("free_memory_bytes", r.FreeMemory)
+	case sflow.SFlowOFPortCounters:
+		t.field("openflow_datapath_id", sflowToolDataPath(r.OfDataPathId))
+		t.field("openflow_port", r.OfPort)
+	case sflow.SFlowOFPortNameCounters:
+		t.field("portName", r.OfPortName)
+	default:
+		t.field("counterBlockType", record.RecordType())
+		t.fields("", reflect.ValueOf(record))
+	}
+}
+
+// fields writes the fields of the records sflowtool does not know, keyed
+// by their lower camel case name, the fields of nested structures and
+// lists are prefixed with the name of their parent
+func (t xnfvSFlowToolWriter) fields(prefix string, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		// the base record is already printed as the block tag
+		if field.Anonymous && (field.Type == reflect.TypeOf(sflow.SFlowBaseFlowRecord{}) || field.Type == reflect.TypeOf(sflow.SFlowBaseCounterRecord{})) {
+			continue
+		}
+		if field.Anonymous && value.Kind() == reflect.Struct {
+			t.fields(prefix, value)
+			continue
+		}
+		t.value(prefix+sflowToolKey(field.Name), value)
+	}
+}
+
+func (t xnfvSFlowToolWriter) value(key string, value reflect.Value) {
+	switch v := value.Interface().(type) {
+	case net.IP:
+		t.field(key, sflowToolAddress(v))
+		return
+	case net.HardwareAddr:
+		t.field(key, sflowToolMAC(v))
+		return
+	case []byte:
+		t.field(key, sflowToolHex(v))
+		return
+	case []uint32:
+		t.field(key, sflowToolUint32s(v))
+		return
+	case sflow.SFlowRecords:
+		t.flowRecords(v)
+		return
+	case fmt.Stringer:
+		t.field(key, v)
+		return
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		t.fields(key+".", value)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			t.value(fmt.Sprintf("%s.%d", key, i), value.Index(i))
+		}
+	case reflect.String:
+		t.field(key, value.String())
+	default:
+		t.field(key, value.Interface())
+	}
+}
+
+// writeSFlowToolLines writes the datagram in the line format of sflowtool
+// (-l), CNTR and FLOW lines, and the OFPORT lines of the OpenFlow ports,
+// in the order of the samples in the datagram
+func writeSFlowToolLines(w io.Writer, datagram *sflow.Datagram) error {
+	out := bufio.NewWriter(w)
+	agent := sflowToolAddress(datagram.AgentAddress)
+	counterLines := func(s sflow.SFlowCounterSample) error {
+		for _, counters := range s.GenericInterfaceCounters() {
+			fmt.Fprintf(out, "CNTR,%s,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d,%d\n",
+				agent, counters.IfIndex, counters.IfType, counters.IfSpeed, counters.IfDirection, counters.IfStatus,
+				counters.IfInOctets, counters.IfInUcastPkts, counters.IfInMulticastPkts, counters.IfInBroadcastPkts,
+				counters.IfInDiscards, counters.IfInErrors, counters.IfInUnknownProtos,
+				counters.IfOutOctets, counters.IfOutUcastPkts, counters.IfOutMulticastPkts, counters.IfOutBroadcastPkts,
+				counters.IfOutDiscards, counters.IfOutErrors, counters.IfPromiscuousMode)
+		}
+		if port, ok := s.OFPortCounters(); ok {
+			name, _ := s.OFPortNameCounters()
+			fmt.Fprintf(out, "OFPORT,%s,%d,%s,%d,%s\n",
+				agent, s.SourceIDIndex, sflowToolDataPath(port.OfDataPathId), port.OfPort, name.OfPortName)
+		}
+		return nil
+	}
+	flowLine := func(s sflow.SFlowFlowSample) error {
+		line := newSFlowToolFlowLine(s)
+		fmt.Fprintf(out, "FLOW,%s,%d,%d,%s,%s,0x%04x,%d,%d,%s,%s,%d,%d,%d,%d,%d,0x%02x,%d,%d,%d\n",
+			agent, s.InputInterface, s.OutputInterface,
+			sflowToolMAC(line.srcMAC), sflowToolMAC(line.dstMAC), line.ethernetType, line.inVLAN, line.outVLAN,
+			sflowToolAddress(line.srcIP), sflowToolAddress(line.dstIP), line.ipProtocol, line.ipTOS, line.ipTTL,
+			line.srcPort, line.dstPort, line.tcpFlags, line.packetSize, line.ipSize, s.SamplingRate)
+		return nil
+	}
+	// sflowtool -l has no line for the discarded packets
+	noLine := func(sflow.SFlowDiscardSample) error { return nil }
+	datagram.EachSample(flowLine, counterLines, noLine)
+	return out.Flush()
+}
+
+// sflowToolFlowLine is what a FLOW line reports of a flow sample, taken
+// from its sampled header or its Ethernet, IPv4 and IPv6 records
+type sflowToolFlowLine struct {
+	srcMAC, dstMAC   net.HardwareAddr
+	ethernetType     uint32
+	inVLAN, outVLAN  uint32
+	srcIP, dstIP     net.IP
+	ipProtocol       uint32
+	ipTOS, ipTTL     uint32
+	srcPort, dstPort uint32
+	tcpFlags         uint32
+	packetSize       uint32
+	ipSize           uint32
+}
+
+func newSFlowToolFlowLine(s sflow.SFlowFlowSample) sflowToolFlowLine {
+	line := sflowToolFlowLine{}
+	for _, record := range s.Records {
+		switch r := record.(type) {
+		case sflow.SFlowRawPacketFlowRecord:
+			line.packetSize = r.FrameLength
+			if r.ParsedHeader.Packet != nil {
+				line.header(r.ParsedHeader.Packet, r.FrameLength-r.PayloadRemoved)
+			}
+		case sflow.SFlowEthernetFrameFlowRecord:
+			line.srcMAC, line.dstMAC, line.ethernetType = r.SrcMac, r.DstMac, r.Type
+			line.packetSize = r.FrameLength
+		case sflow.SFlowIpv4FlowRecord:
+			line.srcIP, line.dstIP, line.ipProtocol, line.ipTOS = r.IPSrc, r.IPDst, r.Protocol, r.TOS
+			line.srcPort, line.dstPort, line.tcpFlags, line.ipSize = r.PortSrc, r.PortDst, r.TCPFlags, r.SFlowIpv4Record.Length
+		case sflow.SFlowIpv6FlowRecord:
+			line.srcIP, line.dstIP, line.ipProtocol, line.ipTOS = r.IPSrc, r.IPDst, r.Protocol, r.Priority
+			line.srcPort, line.dstPort, line.tcpFlags, line.ipSize = r.PortSrc, r.PortDst, r.TCPFlags, r.SFlowIpv6Record.Length
+		case sflow.SFlowExtendedSwitchFlowRecord:
+			line.inVLAN, line.outVLAN = r.IncomingVLAN, r.OutgoingVLAN
+		}
+	}
+	return line
+}
+
+// header fills the line from a sampled header, size is the frame length
+// without the stripped bytes
+func (line *sflowToolFlowLine) header(packet gopacket.Packet, size uint32) {
+	offset := uint32(0)
+	if eth, ok := packet.Layer(layers.LayerTypeEthernet).(*layers.Ethernet); ok {
+		line.srcMAC, line.dstMAC, line.ethernetType = eth.SrcMAC, eth.DstMAC, uint32(eth.EthernetType)
+		offset = uint32(len(eth.Contents))
+	}
+	if dot1q, ok := packet.Layer(layers.LayerTypeDot1Q).(*layers.Dot1Q); ok {
+		line.ethernetType = uint32(dot1q.Type)
+		offset += uint32(len(dot1q.Contents))
+	}
+	if ip, ok := packet.Layer(layers.LayerTypeIPv4).(*layers.IPv4); ok {
+		line.srcIP, line.dstIP, line.ipProtocol = ip.SrcIP, ip.DstIP, uint32(ip.Protocol)
+		line.ipTOS, line.ipTTL = uint32(ip.TOS), uint32(ip.TTL)
+	} else if ip, ok := packet.Layer(layers.LayerTypeIPv6).(*layers.IPv6); ok {
+		line.srcIP, line.dstIP, line.ipProtocol = ip.SrcIP, ip.DstIP, uint32(ip.NextHeader)
+		line.ipTOS, line.ipTTL = uint32(ip.TrafficClass), uint32(ip.HopLimit)
+	}
+	if size > offset {
+		line.ipSize = size - offset
+	}
+	if tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP); ok {
+		line.srcPort, line.dstPort, line.tcpFlags = uint32(tcp.SrcPort), uint32(tcp.DstPort), sflowToolTCPFlags(tcp)
+	} else if udp, ok := packet.Layer(layers.LayerTypeUDP).(*layers.UDP); ok {
+		line.srcPort, line.dstPort = uint32(udp.SrcPort), uint32(udp.DstPort)
+	} else if icmp, ok := packet.Layer(layers.LayerTypeICMPv4).(*layers.ICMPv4); ok {
+		// sflowtool reports the ICMP type and code as the ports
+		line.srcPort, line.dstPort = uint32(icmp.TypeCode.Type()), uint32(icmp.TypeCode.Code())
+	}
+}
+
+func sflowToolTCPFlags(tcp *layers.TCP) uint32 {
+	flags := uint32(0)
+	for i, set := range []bool{tcp.FIN, tcp.SYN, tcp.RST, tcp.PSH, tcp.ACK, tcp.URG, tcp.ECE, tcp.CWR} {
+		if set {
+			flags |= 1 << uint(i)
+		}
+	}
+	return flags
+}
+
+// sflowToolAddress prints the IPv4 addresses in dotted form, also the
+// IPv4-mapped ones, and an unset address as 0.0.0.0
+func sflowToolAddress(ip net.IP) string {
+	if len(ip) == 0 {
+		return "0.0.0.0"
+	}
+	return ip.String()
+}
+
+// sflowToolMAC prints a MAC address as 12 hex digits
+func sflowToolMAC(mac net.HardwareAddr) string {
+	if len(mac) == 0 {
+		return "000000000000"
+	}
+	return fmt.Sprintf("%x", []byte(mac))
+}
+
+// sflowToolHex prints bytes as dash separated upper case hex digits
+func sflowToolHex(data []byte) string {
+	hex := make([]string, len(data))
+	for i, b := range data {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, "-")
+}
+
+func sflowToolDataPath(dataPath []byte) string {
+	return fmt.Sprintf("%016x", dataPath)
+}
+
+func sflowToolUint32s(values []uint32) string {
+	var s []string
+	for _, value := range values {
+		s = append(s, fmt.Sprint(value))
+	}
+	return strings.Join(s, "-")
+}
+
+// sflowToolKey lower cases the leading capitals of a field name, IPSrc
+// becomes ipSrc and SSID ssid
+func sflowToolKey(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || (i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+var xnfvUpdateGolden = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// xnfvTestGolden compares got with the golden file testdata/name, or
+// rewrites it with -update
+func xnfvTestGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *xnfvUpdateGolden {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s differs from the golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// xnfvTestSFlowToolDatagram is a datagram of the agent 192.0.2.1 with a
+// counter, a flow, a discard and a flow sample in this order, decoded from
+// its encoding like a received one
+func xnfvTestSFlowToolDatagram(t *testing.T) *sflow.Datagram {
+	header := xnfvTestUDPHeader(t, net.ParseIP("10.0.0.1").To4(), net.ParseIP("10.0.0.2").To4(), 53, make([]byte, 32))
+	rawPacket := func(frameLength uint32) sflow.SFlowRawPacketFlowRecord {
+		return sflow.SFlowRawPacketFlowRecord{
+			HeaderProtocol: sflow.SFlowProtoEthernet,
+			FrameLength:    frameLength,
+			PayloadRemoved: 4,
+			ParsedHeader:   sflow.SFlowRawHeader{Data: header.Data()},
+		}
+	}
+	datagram := &sflow.Datagram{
+		DatagramVersion: 5,
+		AgentAddress:    net.ParseIP("192.0.2.1").To4(),
+		SubAgentID:      1,
+		SequenceNumber:  42,
+		AgentUptime:     123456,
+		CounterSamples: []sflow.SFlowCounterSample{{
+			SequenceNumber: 7,
+			SourceIDIndex:  3,
+			Records: sflow.SFlowRecords{
+				sflow.SFlowGenericInterfaceCounters{
+					IfIndex: 3, IfType: 6, IfSpeed: 1000000000, IfDirection: 1, IfStatus: 3,
+					IfInOctets: 1000, IfInUcastPkts: 10, IfInMulticastPkts: 1, IfInBroadcastPkts: 2,
+					IfInDiscards: 3, IfInErrors: 4, IfInUnknownProtos: 5,
+					IfOutOctets: 2000, IfOutUcastPkts: 20, IfOutMulticastPkts: 6, IfOutBroadcastPkts: 7,
+					IfOutDiscards: 8, IfOutErrors: 9, IfPromiscuousMode: 0,
+				},
+				sflow.SFlowOFPortCounters{OfDataPathId: []byte{0, 0, 0, 0, 0, 0, 0, 1}, OfPort: 3},
+				sflow.SFlowOFPortNameCounters{OfPortName: "s1-eth3"},
+			},
+		}},
+		FlowSamples: []sflow.SFlowFlowSample{
+			{
+				SequenceNumber: 8,
+				SourceIDIndex:  3,
+				SamplingRate:   1024,
+				SamplePool:     4096,
+				InputInterface: 3,
+				// sent to two ports
+				OutputInterface: 0x80000002,
+				Records: sflow.SFlowRecords{
+					rawPacket(78),
+					sflow.SFlowExtendedSwitchFlowRecord{IncomingVLAN: 10, OutgoingVLAN: 20},
+				},
+			},
+			{
+				SequenceNumber:  9,
+				SourceIDIndex:   3,
+				SamplingRate:    1024,
+				SamplePool:      5120,
+				InputInterface:  3,
+				OutputInterface: 4,
+				Records:         sflow.SFlowRecords{rawPacket(78)},
+			},
+		},
+		DiscardSamples: []sflow.SFlowDiscardSample{{
+			SequenceNumber:  1,
+			SourceIDIndex:   3,
+			Drops:           2,
+			InputInterface:  3,
+			OutputInterface: 4,
+			Reason:          sflow.SFlowDropACL,
+			Records:         sflow.SFlowRecords{rawPacket(78)},
+		}},
+		SampleOrder: []sflow.SFlowSampleType{
+			sflow.SFlowTypeCounterSample, sflow.SFlowTypeFlowSample, sflow.SFlowTypeDiscardSample, sflow.SFlowTypeFlowSample,
+		},
+	}
+	payload, err := sflow.Encode(datagram)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := sflow.Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestWriteSFlowToolText(t *testing.T) {
+	received := time.Date(2021, 3, 1, 12, 0, 0, 0, time.FixedZone("", 3600))
+	var out bytes.Buffer
+	if err := writeSFlowToolText(&out, net.ParseIP("192.0.2.1").To4(), 400, received, xnfvTestSFlowToolDatagram(t)); err != nil {
+		t.Fatal(err)
+	}
+	xnfvTestGolden(t, "sflowtool.golden.txt", out.Bytes())
+}
+
+func TestWriteSFlowToolLines(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeSFlowToolLines(&out, xnfvTestSFlowToolDatagram(t)); err != nil {
+		t.Fatal(err)
+	}
+	xnfvTestGolden(t, "sflowtool.golden.line", out.Bytes())
+}
+
+func TestSFlowToolKey(t *testing.T) {
+	keys := map[string]string{
+		"IPSrc":        "ipSrc",
+		"SSID":         "ssid",
+		"TxFrameCount": "txFrameCount",
+		"ifIndex":      "ifIndex",
+	}
+	for name, want := range keys {
+		if key := sflowToolKey(name); key != want {
+			t.Errorf("sflowToolKey(%q) = %q, want %q", name, key, want)
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Traffic Profiles
+// ****************************************************************************************************
+
+// XnfvTrafficFlow is a kind of traffic of a profile: the packets of a flow
+// share the protocol and destination port, their frame sizes are drawn
+// from Sizes
+type XnfvTrafficFlow struct {
+	Weight   int
+	Protocol layers.IPProtocol
+	DstPort  uint16
+	Sizes    []int
+	// SYN sends TCP SYN packets only, Spoofed draws a random source address
+	// for every packet (SYN / reflection floods)
+	SYN     bool
+	Spoofed bool
+}
+
+// XnfvTrafficProfile is the traffic mix the simulated hosts send
+type XnfvTrafficProfile struct {
+	Name  string
+	Flows []XnfvTrafficFlow
+}
+
+var xnfvTrafficProfiles = map[string]XnfvTrafficProfile{
+	"web": {Name: "web", Flows: []XnfvTrafficFlow{
+		{Weight: 6, Protocol: layers.IPProtocolTCP, DstPort: 443, Sizes: []int{66, 583, 1514, 1514, 1514}},
+		{Weight: 3, Protocol: layers.IPProtocolTCP, DstPort: 80, Sizes: []int{66, 420, 1514, 1514}},
+		{Weight: 1, Protocol: layers.IPProtocolUDP, DstPort: 53, Sizes: []int{78, 124}},
+	}},
+	"dns": {Name: "dns", Flows: []XnfvTrafficFlow{
+		{Weight: 9, Protocol: layers.IPProtocolUDP, DstPort: 53, Sizes: []int{74, 78, 124, 480}},
+		{Weight: 1, Protocol: layers.IPProtocolTCP, DstPort: 53, Sizes: []int{66, 590}},
+	}},
+	"bulk": {Name: "bulk", Flows: []XnfvTrafficFlow{
+		{Weight: 8, Protocol: layers.IPProtocolTCP, DstPort: 5001, Sizes: []int{1514}},
+		{Weight: 2, Protocol: layers.IPProtocolTCP, DstPort: 22, Sizes: []int{66, 1514}},
+	}},
+	"mixed": {Name: "mixed", Flows: []XnfvTrafficFlow{
+		{Weight: 5, Protocol: layers.IPProtocolTCP, DstPort: 443, Sizes: []int{66, 583, 1514, 1514}},
+		{Weight: 2, Protocol: layers.IPProtocolTCP, DstPort: 80, Sizes: []int{66, 420, 1514}},
+		{Weight: 1, Protocol: layers.IPProtocolTCP, DstPort: 22, Sizes: []int{90, 150}},
+		{Weight: 1, Protocol: layers.IPProtocolUDP, DstPort: 53, Sizes: []int{78, 124}},
+		{Weight: 1, Protocol: layers.IPProtocolUDP, DstPort: 123, Sizes: []int{90}},
+	}},
+	"synflood": {Name: "synflood", Flows: []XnfvTrafficFlow{
+		{Weight: 9, Protocol: layers.IPProtocolTCP, DstPort: 80, Sizes: []int{60}, SYN: true, Spoofed: true},
+		{Weight: 1, Protocol: layers.IPProtocolTCP, DstPort: 443, Sizes: []int{66, 1514}},
+	}},
+}
+
+func (p XnfvTrafficProfile) pick(rng *rand.Rand) XnfvTrafficFlow {
+	total := 0
+	for _, flow := range p.Flows {
+		total += flow.Weight
+	}
+	n := rng.Intn(total)
+	for _, flow := range p.Flows {
+		if n < flow.Weight {
+			return flow
+		}
+		n -= flow.Weight
+	}
+	return p.Flows[len(p.Flows)-1]
+}
+
+// meanFrameSize is the expected frame size of the profile, the interface
+// counters grow by it
+func (p XnfvTrafficProfile) meanFrameSize() float64 {
+	var total, sum float64
+	for _, flow := range p.Flows {
+		var sizes float64
+		for _, size := range flow.Sizes {
+			sizes += float64(size)
+		}
+		total += float64(flow.Weight)
+		sum += float64(flow.Weight) * sizes / float64(len(flow.Sizes))
+	}
+	return sum / total
+}
+
+// ****************************************************************************************************
+//  sFlow Agent Simulator
+// ****************************************************************************************************
+
+type XnfvSimulatorConfig struct {
+	// Collector is the UDP address the datagrams are sent to
+	Collector string
+	// Switches and PortsPerSwitch size the simulated OVS bridges, each
+	// switch is an agent of its own
+	Switches       int
+	PortsPerSwitch int
+	// PacketRate is the packets per second each port receives
+	PacketRate   float64
+	SamplingRate uint32
+	// PollingInterval is how often the counters of a port are exported
+	PollingInterval time.Duration
+	// Loss is the fraction of datagrams dropped instead of being sent
+	Loss        float64
+	Profile     string
+	HeaderBytes int
+	// Duration stops the simulation, 0 runs it forever
+	Duration time.Duration
+	Seed     int64
+}
+
+func DefaultXnfvSimulatorConfig() XnfvSimulatorConfig {
+	return XnfvSimulatorConfig{
+		Collector:       "127.0.0.1:6343",
+		Switches:        4,
+		PortsPerSwitch:  8,
+		PacketRate:      2000,
+		SamplingRate:    1024,
+		PollingInterval: 20 * time.Second,
+		Profile:         "mixed",
+		HeaderBytes:     128,
+		Seed:            1,
+	}
+}
+
+// xnfvSimulatorMaxDatagram keeps the datagrams below a 1500 bytes MTU
+const xnfvSimulatorMaxDatagram = 1400
+
+type xnfvSimPort struct {
+	ifIndex uint32
+	ofPort  uint32
+	name    string
+	mac     net.HardwareAddr
+	ip      net.IP
+	// next counter export, staggered over the polling interval
+	nextPoll time.Time
+
+	flowSequence    uint32
+	counterSequence uint32
+	samplePool      uint32
+	// packets carried over to the next tick, for the fractions of samples
+	pending  float64
+	counters sflow.SFlowGenericInterfaceCounters
+	ethernet sflow.SFlowEthernetCounters
+}
+
+type xnfvSimSwitch struct {
+	agent    net.IP
+	dataPath []byte
+	ports    []*xnfvSimPort
+	sequence uint32
+}
+
+// XnfvSimulator emits the sFlow v5 datagrams of OVS switches: per port
+// generic, Ethernet, OpenFlow port and port name counters, and raw header
+// flow samples of the traffic of a profile
+type XnfvSimulator struct {
+	config   XnfvSimulatorConfig
+	profile  XnfvTrafficProfile
+	meanSize float64
+	rng      *rand.Rand
+	start    time.Time
+	switches []*xnfvSimSwitch
+
+	sent, lost uint64
+}
+
+func NewXnfvSimulator(config XnfvSimulatorConfig) (*XnfvSimulator, error) {
+	profile, ok := xnfvTrafficProfiles[config.Profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown traffic profile %q (%s)", config.Profile, strings.Join(xnfvTrafficProfileNames(), ", "))
+	}
+	if config.Switches < 1 || config.Switches > 250 || config.PortsPerSwitch < 1 || config.PortsPerSwitch > 250 {
+		return nil, fmt.Errorf("switches and ports per switch must be between 1 and 250")
+	}
+	if config.SamplingRate == 0 {
+		return nil, fmt.Errorf("sampling rate must be at least 1")
+	}
+	if config.PacketRate < 0 || config.PollingInterval <= 0 {
+		return nil, fmt.Errorf("packet rate must not be negative and polling interval must be positive")
+	}
+	if config.Loss < 0 || config.Loss > 1 {
+		return nil, fmt.Errorf("loss must be between 0 and 1")
+	}
+	if config.HeaderBytes < 1 {
+		return nil, fmt.Errorf("header bytes must be at least 1")
+	}
+	s := &XnfvSimulator{
+		config:   config,
+		profile:  profile,
+		meanSize: profile.meanFrameSize(),
+		rng:      rand.New(rand.NewSource(config.Seed)),
+		start:    time.Now(),
+	}
+	for i := 0; i < config.Switches; i++ {
+		sw := &xnfvSimSwitch{
+			agent: net.IPv4(192, 0, 2, byte(i+1)).To4(),
+			// OVS derives the datapath ID from the bridge MAC address
+			dataPath: []byte{0, 0, 0x02, 0, 0, 0, 0, byte(i + 1)},
+		}
+		for j := 0; j < config.PortsPerSwitch; j++ {
+			port := &xnfvSimPort{
+				ifIndex:  uint32(10 + j),
+				ofPort:   uint32(j + 1),
+				name:     fmt.Sprintf("s%d-eth%d", i+1, j+1),
+				mac:      net.HardwareAddr{0x02, 0, 0, byte(i + 1), byte(j + 1), 1},
+				ip:       net.IPv4(10, byte(i+1), byte(j+1), 1).To4(),
+				nextPoll: s.start.Add(time.Duration(s.rng.Int63n(int64(config.PollingInterval) + 1))),
+			}
+			port.counters = sflow.SFlowGenericInterfaceCounters{
+				IfIndex:     port.ifIndex,
+				IfType:      6, // ethernetCsmacd
+				IfSpeed:     10000000000,
+				IfDirection: 1, // full duplex
+				IfStatus:    3, // admin and operational status up
+			}
+			sw.ports = append(sw.ports, port)
+		}
+		s.switches = append(s.switches, sw)
+	}
+	return s, nil
+}
+
+func xnfvTrafficProfileNames() []string {
+	var names []string
+	for name := range xnfvTrafficProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run sends the datagrams every second until Duration has elapsed
+func (s *XnfvSimulator) Run() error {
+	conn, err := net.Dial("udp", s.config.Collector)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	log.Printf("simulating %d switches with %d ports (%s traffic, 1 in %d sampling) to %s",
+		s.config.Switches, s.config.PortsPerSwitch, s.profile.Name, s.config.SamplingRate, s.config.Collector)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	last := time.Now()
+	for now := range ticker.C {
+		for _, datagram := range s.Tick(now, now.Sub(last)) {
+			if _, err := conn.Write(datagram); err != nil {
+				log.Println(err)
+			}
+		}
+		last = now
+		if s.config.Duration > 0 && now.Sub(s.start) >= s.config.Duration {
+			break
+		}
+	}
+	log.Printf("sent %d datagrams, dropped %d", s.sent, s.lost)
+	return nil
+}
+
+// Tick advances the simulated traffic by elapsed and returns the encoded
+// datagrams to send, the lost ones already left out
+func (s *XnfvSimulator) Tick(now time.Time, elapsed time.Duration) [][]byte {
+	var out [][]byte
+	for _, sw := range s.switches {
+		var samples []interface{}
+		for _, port := range sw.ports {
+			samples = append(samples, s.trafficSamples(sw, port, elapsed)...)
+			if !now.Before(port.nextPoll) {
+				samples = append(samples, s.counterSample(sw, port))
+				port.nextPoll = port.nextPoll.Add(s.config.PollingInterval)
+				if port.nextPoll.Before(now) {
+					port.nextPoll = now.Add(s.config.PollingInterval)
+				}
+			}
+		}
+		out = append(out, s.datagrams(sw, now, samples)...)
+	}
+	return out
+}
+
+// trafficSamples grows the port counters by the packets received and sent
+// during elapsed, and returns the flow samples taken from them
+func (s *XnfvSimulator) trafficSamples(sw *xnfvSimSwitch, port *xnfvSimPort, elapsed time.Duration) []interface{} {
+	packets := s.config.PacketRate*elapsed.Seconds() + port.pending
+	whole := uint32(packets)
+	port.pending = packets - float64(whole)
+	octets := uint64(float64(whole) * s.meanSize)
+
+	// the ports forward as much as they receive
+	port.counters.IfInOctets += octets
+	port.counters.IfOutOctets += octets
+	port.counters.IfInUcastPkts += whole - whole/1000
+	port.counters.IfOutUcastPkts += whole - whole/1000
+	port.counters.IfInMulticastPkts += whole / 2000
+	port.counters.IfInBroadcastPkts += whole / 2000
+	port.counters.IfOutMulticastPkts += whole / 2000
+	port.counters.IfOutBroadcastPkts += whole / 2000
+	if s.rng.Float64() < 0.01 {
+		port.counters.IfInErrors++
+		port.ethernet.FCSErrors++
+	}
+	port.samplePool += whole
+
+	expected := float64(whole) / float64(s.config.SamplingRate)
+	count := int(expected)
+	if s.rng.Float64() < expected-float64(count) {
+		count++
+	}
+	var samples []interface{}
+	for i := 0; i < count; i++ {
+		samples = append(samples, s.flowSample(sw, port))
+	}
+	return samples
+}
+
+func (s *XnfvSimulator) flowSample(sw *xnfvSimSwitch, port *xnfvSimPort) sflow.SFlowFlowSample {
+	flow := s.profile.pick(s.rng)
+	size := flow.Sizes[s.rng.Intn(len(flow.Sizes))]
+	// the destination is the host of any other port, the frames to the
+	// hosts of the other switches leave through a random port
+	dst := port
+	for dst == port && (len(s.switches) > 1 || len(sw.ports) > 1) {
+		dst = s.switches[s.rng.Intn(len(s.switches))].ports[s.rng.Intn(s.config.PortsPerSwitch)]
+	}
+	peer := dst
+	if !bytes.Equal(dst.ip[:2], port.ip[:2]) {
+		peer = sw.ports[s.rng.Intn(len(sw.ports))]
+	}
+
+	frame := s.frame(flow, size, port, dst)
+	header := frame
+	if len(header) > s.config.HeaderBytes {
+		header = header[:s.config.HeaderBytes]
+	}
+	port.flowSequence++
+	return sflow.SFlowFlowSample{
+		SequenceNumber:  port.flowSequence,
+		SourceIDIndex:   sflow.SFlowSourceValue(port.ifIndex),
+		SamplingRate:    s.config.SamplingRate,
+		SamplePool:      port.samplePool,
+		InputInterface:  port.ifIndex,
+		OutputInterface: peer.ifIndex,
+		Records: sflow.SFlowRecords{
+			sflow.SFlowRawPacketFlowRecord{
+				HeaderProtocol: sflow.SFlowProtoEthernet,
+				// the frame length includes the FCS
+				FrameLength:    uint32(len(frame)) + 4,
+				PayloadRemoved: 0,
+				ParsedHeader:   sflow.SFlowRawHeader{Data: header},
+			},
+			sflow.SFlowExtendedSwitchFlowRecord{IncomingVLAN: 1, OutgoingVLAN: 1},
+		},
+	}
+}
+
+// frame builds an Ethernet / IPv4 / TCP or UDP frame of size bytes from
+// the host of port to the host of dst
+func (s *XnfvSimulator) frame(flow XnfvTrafficFlow, size int, port, dst *xnfvSimPort) []byte {
+	srcIP := port.ip
+	if flow.Spoofed {
+		srcIP = make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(srcIP, s.rng.Uint32())
+	}
+	srcPort := layers.TCPPort(32768 + s.rng.Intn(28232))
+	eth := &layers.Ethernet{SrcMAC: port.mac, DstMAC: dst.mac, EthernetType: layers.EthernetTypeIPv4}
+	ip := &layers.IPv4{Version: 4, IHL: 5, TTL: 64, Protocol: flow.Protocol, SrcIP: srcIP, DstIP: dst.ip}
+
+	var transport gopacket.SerializableLayer
+	headers := 14 + 20
+	switch flow.Protocol {
+	case layers.IPProtocolTCP:
+		tcp := &layers.TCP{SrcPort: srcPort, DstPort: layers.TCPPort(flow.DstPort), Seq: s.rng.Uint32(), Window: 64240}
+		if flow.SYN {
+			tcp.SYN = true
+		} else {
+			tcp.ACK, tcp.PSH = true, size > 66
+			tcp.Ack = s.rng.Uint32()
+		}
+		tcp.SetNetworkLayerForChecksum(ip)
+		transport = tcp
+		headers += 20
+	default:
+		udp := &layers.UDP{SrcPort: layers.UDPPort(srcPort), DstPort: layers.UDPPort(flow.DstPort)}
+		udp.SetNetworkLayerForChecksum(ip)
+		transport = udp
+		headers += 8
+	}
+	payload := 0
+	if size > headers {
+		payload = size - headers
+	}
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, eth, ip, transport, gopacket.Payload(make([]byte, payload))); err != nil {
+		log.Println(err)
+	}
+	return buf.Bytes()
+}
+
+func (s *XnfvSimulator) counterSample(sw *xnfvSimSwitch, port *xnfvSimPort) sflow.SFlowCounterSample {
+	port.counterSequence++
+	return sflow.SFlowCounterSample{
+		SequenceNumber: port.counterSequence,
+		SourceIDIndex:  sflow.SFlowSourceValue(port.ifIndex),
+		Records: sflow.SFlowRecords{
+			port.counters,
+			port.ethernet,
+			sflow.SFlowOFPortCounters{OfDataPathId: sw.dataPath, OfPort: port.ofPort},
+			sflow.SFlowOFPortNameCounters{OfPortName: port.name},
+		},
+	}
+}
+
+// datagrams packs the samples of a switch in datagrams below the MTU and
+// encodes them, dropping Loss of them
+func (s *XnfvSimulator) datagrams(sw *xnfvSimSwitch, now time.Time, samples []interface{}) [][]byte {
+	var out [][]byte
+	var datagram *sflow.Datagram
+	size := 0
+	flush := func() {
+		if datagram == nil {
+			return
+		}
+		// lost datagrams still take a sequence number, the collector sees the gap
+		if s.rng.Float64() < s.config.Loss {
+			s.lost++
+		} else if data, err := sflow.Encode(datagram); err != nil {
+			log.Println(err)
+		} else {
+			out = append(out, data)
+			s.sent++
+		}
+		datagram, size = nil, 0
+	}
+	for _, sample := range samples {
+		var encoded []byte
+		var err error
+		switch sample := sample.(type) {
+		case sflow.SFlowFlowSample:
+			encoded, err = sflow.EncodeFlowSample(sample)
+		case sflow.SFlowCounterSample:
+			encoded, err = sflow.EncodeCounterSample(sample)
+		}
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if datagram != nil && size+len(encoded) > xnfvSimulatorMaxDatagram {
+			flush()
+		}
+		if datagram == nil {
+			sw.sequence++
+			datagram = &sflow.Datagram{
+				DatagramVersion: 5,
+				AgentAddress:    sw.agent,
+				SequenceNumber:  sw.sequence,
+				AgentUptime:     uint32(now.Sub(s.start) / time.Millisecond),
+			}
+			// version, address, sub agent, sequence, uptime and sample count
+			size = 28
+		}
+		switch sample := sample.(type) {
+		case sflow.SFlowFlowSample:
+			datagram.FlowSamples = append(datagram.FlowSamples, sample)
+		case sflow.SFlowCounterSample:
+			datagram.CounterSamples = append(datagram.CounterSamples, sample)
+		}
+		size += len(encoded)
+	}
+	flush()
+	return out
+}
+
+// runXnfvSimulator runs the simulate subcommand
+func runXnfvSimulator(args []string) error {
+	config := DefaultXnfvSimulatorConfig()
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	flags.StringVar(&config.Collector, "collector", config.Collector, "collector UDP address")
+	flags.IntVar(&config.Switches, "switches", config.Switches, "number of switches (agents)")
+	flags.IntVar(&config.PortsPerSwitch, "ports", config.PortsPerSwitch, "ports per switch")
+	flags.Float64Var(&config.PacketRate, "rate", config.PacketRate, "packets per second received by each port")
+	sampling := flags.Uint("sampling", uint(config.SamplingRate), "sampling rate (1 in N packets)")
+	flags.DurationVar(&config.PollingInterval, "polling", config.PollingInterval, "counter polling interval")
+	flags.Float64Var(&config.Loss, "loss", config.Loss, "fraction of datagrams dropped (0-1)")
+	flags.StringVar(&config.Profile, "profile", config.Profile, "traffic profile: "+strings.Join(xnfvTrafficProfileNames(), ", "))
+	flags.IntVar(&config.HeaderBytes, "header", config.HeaderBytes, "sampled header bytes")
+	flags.DurationVar(&config.Duration, "duration", config.Duration, "stop after this long (0 runs forever)")
+	flags.Int64Var(&config.Seed, "seed", config.Seed, "random seed")
+	flags.Parse(args)
+	config.SamplingRate = uint32(*sampling)
+
+	simulator, err := NewXnfvSimulator(config)
+	if err != nil {
+		return err
+	}
+	return simulator.Run()
+}
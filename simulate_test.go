@@ -0,0 +1,196 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// xnfvTestSimulate runs the simulator for seconds one second tick at a
+// time and returns the datagrams it sent, encoded and decoded
+func xnfvTestSimulate(t *testing.T, config XnfvSimulatorConfig, seconds int) (*XnfvSimulator, [][]byte, []*sflow.Datagram) {
+	simulator, err := NewXnfvSimulator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encoded [][]byte
+	var decoded []*sflow.Datagram
+	for second := 1; second <= seconds; second++ {
+		for _, payload := range simulator.Tick(simulator.start.Add(time.Duration(second)*time.Second), time.Second) {
+			datagram, err := sflow.Decode(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, decoded = append(encoded, payload), append(decoded, datagram)
+		}
+	}
+	return simulator, encoded, decoded
+}
+
+// xnfvTestSimPort is a port of a simulated switch
+type xnfvTestSimPort struct {
+	agent   string
+	ifIndex uint32
+}
+
+func xnfvTestSimulatorConfig() XnfvSimulatorConfig {
+	config := DefaultXnfvSimulatorConfig()
+	config.Switches, config.PortsPerSwitch = 2, 2
+	config.PollingInterval = 5 * time.Second
+	return config
+}
+
+func TestXnfvSimulatorCounters(t *testing.T) {
+	_, _, datagrams := xnfvTestSimulate(t, xnfvTestSimulatorConfig(), 60)
+	previous := map[xnfvTestSimPort]sflow.SFlowGenericInterfaceCounters{}
+	polls := map[xnfvTestSimPort]int{}
+	for _, datagram := range datagrams {
+		for _, counterSample := range datagram.CounterSamples {
+			counters := counterSample.Records[0].(sflow.SFlowGenericInterfaceCounters)
+			key := xnfvTestSimPort{datagram.AgentAddress.String(), counters.IfIndex}
+			if last, ok := previous[key]; ok {
+				if counters.IfInOctets <= last.IfInOctets || counters.IfOutOctets <= last.IfOutOctets ||
+					counters.IfInUcastPkts <= last.IfInUcastPkts || counters.IfInErrors < last.IfInErrors {
+					t.Fatalf("%v: counters went from %+v to %+v", key, last, counters)
+				}
+			}
+			previous[key] = counters
+			polls[key]++
+		}
+	}
+	// every port is polled every 5 seconds
+	if len(polls) != 4 {
+		t.Fatalf("polled ports %v", polls)
+	}
+	for key, count := range polls {
+		if count < 11 || count > 12 {
+			t.Errorf("%v polled %d times in 60 seconds", key, count)
+		}
+	}
+}
+
+// The flow samples scaled by the sampling rate estimate the traffic of
+// the ports, and the sample pool counts it
+func TestXnfvSimulatorSamplingRate(t *testing.T) {
+	config := xnfvTestSimulatorConfig()
+	config.SamplingRate = 100
+	_, _, datagrams := xnfvTestSimulate(t, config, 100)
+	samples := 0
+	pools := map[xnfvTestSimPort]uint32{}
+	for _, datagram := range datagrams {
+		for _, flowSample := range datagram.FlowSamples {
+			if flowSample.SamplingRate != 100 {
+				t.Fatalf("sampling rate %d", flowSample.SamplingRate)
+			}
+			samples++
+			pools[xnfvTestSimPort{datagram.AgentAddress.String(), flowSample.InputInterface}] = flowSample.SamplePool
+		}
+	}
+	// 4 ports at 2000 packets per second for 100 seconds, 1 in 100
+	packets := 4 * config.PacketRate * 100
+	if estimated := float64(samples) * 100; math.Abs(estimated-packets) > packets/20 {
+		t.Fatalf("%d samples estimate %.0f packets, want %.0f", samples, estimated, packets)
+	}
+	for port, pool := range pools {
+		if pool < 199000 || pool > 200000 {
+			t.Errorf("%v: sample pool %d, want 200000", port, pool)
+		}
+	}
+}
+
+func TestXnfvSimulatorLoss(t *testing.T) {
+	for _, loss := range []float64{0, 0.3, 1} {
+		config := xnfvTestSimulatorConfig()
+		config.Loss = loss
+		simulator, _, datagrams := xnfvTestSimulate(t, config, 60)
+		if int(simulator.sent) != len(datagrams) || simulator.sent+simulator.lost == 0 {
+			t.Fatalf("loss %v: sent %d lost %d, %d datagrams", loss, simulator.sent, simulator.lost, len(datagrams))
+		}
+		if fraction := float64(simulator.lost) / float64(simulator.sent+simulator.lost); math.Abs(fraction-loss) > 0.1 {
+			t.Errorf("loss %v: %d of %d datagrams lost", loss, simulator.lost, simulator.sent+simulator.lost)
+		}
+		// the lost datagrams are the gaps of the sequence numbers
+		last := map[string]uint32{}
+		var gaps uint32
+		for _, datagram := range datagrams {
+			agent := datagram.AgentAddress.String()
+			gaps += datagram.SequenceNumber - last[agent] - 1
+			last[agent] = datagram.SequenceNumber
+		}
+		for _, sw := range simulator.switches {
+			gaps += sw.sequence - last[sw.agent.String()]
+		}
+		if uint64(gaps) != simulator.lost {
+			t.Errorf("loss %v: %d sequence gaps, %d lost", loss, gaps, simulator.lost)
+		}
+	}
+}
+
+// A busy switch splits its samples over several datagrams that fit in
+// the MTU
+func TestXnfvSimulatorDatagramSize(t *testing.T) {
+	config := xnfvTestSimulatorConfig()
+	// the frames of the profile are at least 66 bytes
+	config.Switches, config.SamplingRate, config.HeaderBytes = 1, 10, 64
+	simulator, err := NewXnfvSimulator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payloads := simulator.Tick(simulator.start.Add(10*time.Second), time.Second)
+	if len(payloads) < 2 {
+		t.Fatalf("%d datagrams", len(payloads))
+	}
+	flowSamples, counterSamples := 0, 0
+	for i, payload := range payloads {
+		if len(payload) > xnfvSimulatorMaxDatagram {
+			t.Errorf("datagram %d: %d bytes", i, len(payload))
+		}
+		datagram, err := sflow.Decode(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if datagram.SequenceNumber != uint32(i+1) {
+			t.Errorf("datagram %d: sequence number %d", i, datagram.SequenceNumber)
+		}
+		flowSamples += len(datagram.FlowSamples)
+		counterSamples += len(datagram.CounterSamples)
+		// headers are cut at HeaderBytes
+		for _, flowSample := range datagram.FlowSamples {
+			if rawPacket, _ := flowSample.RawPacket(); len(rawPacket.ParsedHeader.Data) != config.HeaderBytes {
+				t.Errorf("header of %d bytes", len(rawPacket.ParsedHeader.Data))
+			}
+		}
+	}
+	// 2 ports at 2000 packets per second, 1 in 10, and both polled
+	if flowSamples < 380 || flowSamples > 420 || counterSamples != 2 {
+		t.Fatalf("%d flow samples and %d counter samples", flowSamples, counterSamples)
+	}
+}
+
+func TestNewXnfvSimulatorConfig(t *testing.T) {
+	invalid := map[string]func(*XnfvSimulatorConfig){
+		"negative header": func(config *XnfvSimulatorConfig) { config.HeaderBytes = -1 },
+		"empty header":    func(config *XnfvSimulatorConfig) { config.HeaderBytes = 0 },
+		"negative loss":   func(config *XnfvSimulatorConfig) { config.Loss = -0.1 },
+		"loss above 1":    func(config *XnfvSimulatorConfig) { config.Loss = 1.5 },
+		"no polling":      func(config *XnfvSimulatorConfig) { config.PollingInterval = 0 },
+		"negative rate":   func(config *XnfvSimulatorConfig) { config.PacketRate = -1 },
+		"no sampling":     func(config *XnfvSimulatorConfig) { config.SamplingRate = 0 },
+		"no switches":     func(config *XnfvSimulatorConfig) { config.Switches = 0 },
+		"unknown profile": func(config *XnfvSimulatorConfig) { config.Profile = "video" },
+	}
+	for name, change := range invalid {
+		config := DefaultXnfvSimulatorConfig()
+		change(&config)
+		if _, err := NewXnfvSimulator(config); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+	config := DefaultXnfvSimulatorConfig()
+	config.Loss, config.HeaderBytes = 1, 1
+	if _, err := NewXnfvSimulator(config); err != nil {
+		t.Errorf("bounds: %v", err)
+	}
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+// ****************************************************************************************************
+//  Wireless (802.11) Monitoring
+// ****************************************************************************************************
+
+type XnfvWirelessTableConfig struct {
+	// MaxAge removes the radios and SSIDs not reported for that long
+	MaxAge time.Duration
+}
+
+func DefaultXnfvWirelessTableConfig() XnfvWirelessTableConfig {
+	return XnfvWirelessTableConfig{MaxAge: 10 * time.Minute}
+}
+
+// XnfvWirelessTraffic summarizes the 802.11 RX and TX records of the
+// sampled frames. The packet, byte and airtime figures are estimates,
+// weighted by the sampling rate.
+type XnfvWirelessTraffic struct {
+	RxPackets uint64 `json:"rxPackets"`
+	TxPackets uint64 `json:"txPackets"`
+	// Bytes is only counted for the samples with a raw header record
+	Bytes     uint64  `json:"bytes"`
+	AirtimeMs float64 `json:"airtimeMs"`
+	MeanRSNI  float64 `json:"meanRsni"`
+	MeanRCPI  float64 `json:"meanRcpi"`
+	// RetransmissionRatio is the share of the transmissions of the sampled
+	// TX frames that were retransmissions
+	RetransmissionRatio float64           `json:"retransmissionRatio"`
+	MeanRxSpeedMbps     float64           `json:"meanRxSpeedMbps"`
+	MeanTxSpeedMbps     float64           `json:"meanTxSpeedMbps"`
+	Versions            map[string]uint64 `json:"versions,omitempty"`
+}
+
+// XnfvWirelessAPKey identifies an access point radio by the agent and the
+// ifIndex of the radio interface
+type XnfvWirelessAPKey struct {
+	Agent   string `json:"agent"`
+	IfIndex uint32 `json:"ifIndex"`
+}
+
+// XnfvWirelessAP is the latest 802.11 and radio counters of an access
+// point radio, the rates derived from the two last records and the
+// traffic of its sampled frames
+type XnfvWirelessAP struct {
+	XnfvWirelessAPKey
+	Counters *sflow.SFlow80211Counters `json:"counters,omitempty"`
+	Radio    *sflow.SFlowRadioCounters `json:"radio,omitempty"`
+	// ChannelUtilization is the busy share of the time on channel over the
+	// last radio counter interval
+	ChannelUtilization float64 `json:"channelUtilization"`
+	AssociatedStations uint32  `json:"associatedStations"`
+	// per second rates over the last 802.11 counter interval
+	TxFrameRate    float64 `json:"txFrameRate"`
+	RxFragmentRate float64 `json:"rxFragmentRate"`
+	FailedRate     float64 `json:"failedRate"`
+	FCSErrorRate   float64 `json:"fcsErrorRate"`
+	ACKFailureRate float64 `json:"ackFailureRate"`
+	// RetryRatio is the retried over the transmitted frames
+	RetryRatio float64             `json:"retryRatio"`
+	Channel    uint32              `json:"channel,omitempty"`
+	SSIDs      []string            `json:"ssids,omitempty"`
+	BSSIDs     []string            `json:"bssids,omitempty"`
+	Traffic    XnfvWirelessTraffic `json:"traffic"`
+	Interval   float64             `json:"interval"`
+	LastUpdate time.Time           `json:"lastUpdate"`
+}
+
+// XnfvWirelessSSID is the traffic of the sampled frames of an SSID across
+// all the access points that serve it
+type XnfvWirelessSSID struct {
+	SSID       string              `json:"ssid"`
+	Agents     []string            `json:"agents"`
+	BSSIDs     []string            `json:"bssids"`
+	Channels   []uint32            `json:"channels"`
+	Traffic    XnfvWirelessTraffic `json:"traffic"`
+	LastUpdate time.Time           `json:"lastUpdate"`
+}
+
+// xnfvWirelessTrafficSums accumulates the sampled frames of a radio or an
+// SSID, XnfvWirelessTraffic is derived from it on query
+type xnfvWirelessTrafficSums struct {
+	rxPackets, txPackets, bytes uint64
+	airtimeUs                   float64
+	rxSamples, txSamples        uint64
+	rsni, rcpi                  uint64
+	rxSpeed, txSpeed            float64
+	transmissions, retransmits  uint64
+	versions                    map[string]uint64
+}
+
+type xnfvWirelessAPEntry struct {
+	ap         XnfvWirelessAP
+	traffic    xnfvWirelessTrafficSums
+	ssids      map[string]bool
+	bssids     map[string]bool
+	dot11Time  time.Time
+	lastUpdate time.Time
+}
+
+type xnfvWirelessSSIDEntry struct {
+	traffic    xnfvWirelessTrafficSums
+	agents     map[string]bool
+	bssids     map[string]bool
+	channels   map[uint32]bool
+	lastUpdate time.Time
+}
+
+// XnfvWirelessTable aggregates the 802.11 counters and flow records of the
+// access points by radio and by SSID. It is safe for concurrent use.
+type XnfvWirelessTable struct {
+	config XnfvWirelessTableConfig
+	mutex  sync.RWMutex
+	aps    map[XnfvWirelessAPKey]*xnfvWirelessAPEntry
+	ssids  map[string]*xnfvWirelessSSIDEntry
+}
+
+func NewXnfvWirelessTable(config XnfvWirelessTableConfig) *XnfvWirelessTable {
+	return &XnfvWirelessTable{
+		config: config,
+		aps:    map[XnfvWirelessAPKey]*xnfvWirelessAPEntry{},
+		ssids:  map[string]*xnfvWirelessSSIDEntry{},
+	}
+}
+
+// ObserveDatagram updates the radios with the 802.11 and radio counter
+// records of a datagram, and the radios and SSIDs with its 802.11 RX and
+// TX flow records, including those of aggregated frames
+func (t *XnfvWirelessTable) ObserveDatagram(datagram sflow.Datagram, now time.Time) {
+	if datagram.AgentAddress == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	agent := datagram.AgentAddress.String()
+	for _, counterSample := range datagram.CounterSamples {
+		key := XnfvWirelessAPKey{agent, uint32(counterSample.SourceIDIndex)}
+		for _, record := range counterSample.Records {
+			switch r := record.(type) {
+			case sflow.SFlow80211Counters:
+				t.ap(key).observe80211(r, now)
+			case sflow.SFlowRadioCounters:
+				t.ap(key).observeRadio(r, now)
+			}
+		}
+	}
+	for _, flowSample := range datagram.FlowSamples {
+		key := XnfvWirelessAPKey{agent, uint32(flowSample.SourceIDIndex)}
+		t.observeRecords(key, flowSample.SamplingRate, flowSample.Records, now)
+	}
+}
+
+func (t *XnfvWirelessTable) ap(key XnfvWirelessAPKey) *xnfvWirelessAPEntry {
+	entry, ok := t.aps[key]
+	if !ok {
+		entry = &xnfvWirelessAPEntry{
+			ap:     XnfvWirelessAP{XnfvWirelessAPKey: key},
+			ssids:  map[string]bool{},
+			bssids: map[string]bool{},
+		}
+		t.aps[key] = entry
+	}
+	return entry
+}
+
+func (t *XnfvWirelessTable) ssid(ssid string) *xnfvWirelessSSIDEntry {
+	entry, ok := t.ssids[ssid]
+	if !ok {
+		entry = &xnfvWirelessSSIDEntry{
+			agents:   map[string]bool{},
+			bssids:   map[string]bool{},
+			channels: map[uint32]bool{},
+		}
+		t.ssids[ssid] = entry
+	}
+	return entry
+}
+
+// observeRecords accounts the RX and TX records of a sampled frame to its
+// radio and SSID. The frame length comes from the raw header record of the
+// same frame, the PDUs of an aggregated frame count as frames of their own.
+func (t *XnfvWirelessTable) observeRecords(key XnfvWirelessAPKey, samplingRate uint32, records sflow.SFlowRecords, now time.Time) {
+	var frameLength uint32
+	for _, record := range records {
+		if rawPacket, ok := record.(sflow.SFlowRawPacketFlowRecord); ok {
+			frameLength = rawPacket.FrameLength
+		}
+	}
+	for _, record := range records {
+		var ssid, bssid string
+		var channel uint32
+		switch r := record.(type) {
+		case sflow.SFlowExtended80211RxFlowRecord:
+			ssid, bssid, channel = r.SSID, r.BSSID.String(), r.Channel
+		case sflow.SFlowExtended80211TxFlowRecord:
+			ssid, bssid, channel = r.SSID, r.BSSID.String(), r.Channel
+		case sflow.SFlowExtended80211AggregationFlowRecord:
+			for _, pdu := range r.PDUs {
+				t.observeRecords(key, samplingRate, pdu.Records, now)
+			}
+			continue
+		default:
+			continue
+		}
+		apEntry := t.ap(key)
+		apEntry.traffic.add(record, samplingRate, frameLength)
+		apEntry.ssids[ssid], apEntry.bssids[bssid] = true, true
+		apEntry.ap.Channel, apEntry.lastUpdate = channel, now
+
+		ssidEntry := t.ssid(ssid)
+		ssidEntry.traffic.add(record, samplingRate, frameLength)
+		ssidEntry.agents[key.Agent], ssidEntry.bssids[bssid], ssidEntry.channels[channel] = true, true, true
+		ssidEntry.lastUpdate = now
+	}
+}
+
+func (s *xnfvWirelessTrafficSums) add(record sflow.SFlowRecord, samplingRate uint32, frameLength uint32) {
+	weight := uint64(samplingRate)
+	if weight == 0 {
+		weight = 1
+	}
+	if s.versions == nil {
+		s.versions = map[string]uint64{}
+	}
+	s.bytes += uint64(frameLength) * weight
+	switch r := record.(type) {
+	case sflow.SFlowExtended80211RxFlowRecord:
+		s.rxPackets += weight
+		s.rxSamples++
+		s.rsni += uint64(r.RSNI)
+		s.rcpi += uint64(r.RCPI)
+		s.rxSpeed += float64(r.Speed)
+		s.airtimeUs += float64(r.PacketDuration) * float64(weight)
+		s.versions[r.Version.String()] += weight
+	case sflow.SFlowExtended80211TxFlowRecord:
+		s.txPackets += weight
+		s.txSamples++
+		s.txSpeed += float64(r.Speed)
+		s.airtimeUs += float64(r.PacketDuration+r.RetransDuration) * float64(weight)
+		s.versions[r.Version.String()] += weight
+		if r.Transmissions > 0 {
+			s.transmissions += uint64(r.Transmissions)
+			s.retransmits += uint64(r.Transmissions - 1)
+		}
+	}
+}
+
+func (s *xnfvWirelessTrafficSums) traffic() XnfvWirelessTraffic {
+	traffic := XnfvWirelessTraffic{
+		RxPackets: s.rxPackets,
+		TxPackets: s.txPackets,
+		Bytes:     s.bytes,
+		AirtimeMs: s.airtimeUs / 1000,
+		Versions:  map[string]uint64{},
+	}
+	for version, packets := range s.versions {
+		traffic.Versions[version] = packets
+	}
+	if s.rxSamples > 0 {
+		traffic.MeanRSNI = float64(s.rsni) / float64(s.rxSamples)
+		traffic.MeanRCPI = float64(s.rcpi) / float64(s.rxSamples)
+		traffic.MeanRxSpeedMbps = s.rxSpeed / float64(s.rxSamples) / 1e6
+	}
+	if s.txSamples > 0 {
+		traffic.MeanTxSpeedMbps = s.txSpeed / float64(s.txSamples) / 1e6
+	}
+	if s.transmissions > 0 {
+		traffic.RetransmissionRatio = float64(s.retransmits) / float64(s.transmissions)
+	}
+	return traffic
+}
+
+// observe80211 stores an 802.11 counter record and derives the rates from
+// the previous one. After a restart of the radio the record only becomes
+// the reference of the next one.
+func (e *xnfvWirelessAPEntry) observe80211(counters sflow.SFlow80211Counters, now time.Time) {
+	previous, interval := e.ap.Counters, now.Sub(e.dot11Time).Seconds()
+	e.ap.Counters, e.ap.AssociatedStations = &counters, counters.AssociatedStationCount
+	e.dot11Time, e.lastUpdate = now, now
+	if previous == nil || interval <= 0 {
+		return
+	}
+	var deltas xnfvCounterDeltas
+	transmitted := deltas.delta(previous.TransmittedFrameCount, counters.TransmittedFrameCount)
+	received := deltas.delta(previous.ReceivedFragmentCount, counters.ReceivedFragmentCount)
+	failed := deltas.delta(previous.FailedCount, counters.FailedCount)
+	fcsErrors := deltas.delta(previous.FCSErrorCount, counters.FCSErrorCount)
+	ackFailures := deltas.delta(previous.ACKFailureCount, counters.ACKFailureCount)
+	retries := deltas.delta(previous.RetryCount, counters.RetryCount)
+	if deltas.reset {
+		e.ap.Interval, e.ap.TxFrameRate, e.ap.RxFragmentRate = 0, 0, 0
+		e.ap.FailedRate, e.ap.FCSErrorRate, e.ap.ACKFailureRate, e.ap.RetryRatio = 0, 0, 0, 0
+		return
+	}
+	e.ap.Interval = interval
+	e.ap.TxFrameRate = float64(transmitted) / interval
+	e.ap.RxFragmentRate = float64(received) / interval
+	e.ap.FailedRate = float64(failed) / interval
+	e.ap.FCSErrorRate = float64(fcsErrors) / interval
+	e.ap.ACKFailureRate = float64(ackFailures) / interval
+	e.ap.RetryRatio = 0
+	if transmitted > 0 {
+		e.ap.RetryRatio = float64(retries) / float64(transmitted)
+	}
+}
+
+// observeRadio stores a radio counter record. The channel utilization
+// comes from the difference with the previous record, or from the times
+// since the radio started for the first one and after a restart.
+func (e *xnfvWirelessAPEntry) observeRadio(counters sflow.SFlowRadioCounters, now time.Time) {
+	previous := e.ap.Radio
+	e.ap.Radio, e.lastUpdate = &counters, now
+	onChannel, busy := counters.OnChannelTime, counters.OnChannelBusyTime
+	if previous != nil {
+		var deltas xnfvCounterDeltas
+		onChannelDelta := deltas.delta(previous.OnChannelTime, onChannel)
+		busyDelta := deltas.delta(previous.OnChannelBusyTime, busy)
+		if !deltas.reset {
+			onChannel, busy = onChannelDelta, busyDelta
+		}
+	}
+	e.ap.ChannelUtilization = 0
+	if onChannel > 0 {
+		e.ap.ChannelUtilization = float64(busy) / float64(onChannel)
+	}
+}
+
+// AgeOut removes the radios and SSIDs not reported since MaxAge before now
+func (t *XnfvWirelessTable) AgeOut(now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for key, entry := range t.aps {
+		if now.Sub(entry.lastUpdate) > t.config.MaxAge {
+			delete(t.aps, key)
+		}
+	}
+	for ssid, entry := range t.ssids {
+		if now.Sub(entry.lastUpdate) > t.config.MaxAge {
+			delete(t.ssids, ssid)
+		}
+	}
+}
+
+// ****************************************************************************************************
+//  Wireless Query API
+// ****************************************************************************************************
+
+// APs returns the access point radios, optionally of one agent only, the
+// busiest channel first
+func (t *XnfvWirelessTable) APs(agent string) []XnfvWirelessAP {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	aps := []XnfvWirelessAP{}
+	for key, entry := range t.aps {
+		if agent != "" && key.Agent != agent {
+			continue
+		}
+		ap := entry.ap
+		ap.SSIDs, ap.BSSIDs = sortedKeys(entry.ssids), sortedKeys(entry.bssids)
+		ap.Traffic, ap.LastUpdate = entry.traffic.traffic(), entry.lastUpdate
+		aps = append(aps, ap)
+	}
+	sort.Slice(aps, func(i, j int) bool {
+		if aps[i].ChannelUtilization != aps[j].ChannelUtilization {
+			return aps[i].ChannelUtilization > aps[j].ChannelUtilization
+		}
+		if aps[i].Agent != aps[j].Agent {
+			return aps[i].Agent < aps[j].Agent
+		}
+		return aps[i].IfIndex < aps[j].IfIndex
+	})
+	return aps
+}
+
+// ServeHTTP answers GET /aps[?agent=] with the access point radios as JSON
+func (t *XnfvWirelessTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.APs(r.URL.Query().Get("agent")))
+}
+
+// SSIDs returns the traffic of the SSIDs, optionally of one SSID only,
+// the one with the most airtime first
+func (t *XnfvWirelessTable) SSIDs(ssid string) []XnfvWirelessSSID {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	ssids := []XnfvWirelessSSID{}
+	for name, entry := range t.ssids {
+		if ssid != "" && name != ssid {
+			continue
+		}
+		stats := XnfvWirelessSSID{
+			SSID:       name,
+			Agents:     sortedKeys(entry.agents),
+			BSSIDs:     sortedKeys(entry.bssids),
+			Traffic:    entry.traffic.traffic(),
+			LastUpdate: entry.lastUpdate,
+		}
+		for channel := range entry.channels {
+			stats.Channels = append(stats.Channels, channel)
+		}
+		sort.Slice(stats.Channels, func(i, j int) bool { return stats.Channels[i] < stats.Channels[j] })
+		ssids = append(ssids, stats)
+	}
+	sort.Slice(ssids, func(i, j int) bool {
+		if ssids[i].Traffic.AirtimeMs != ssids[j].Traffic.AirtimeMs {
+			return ssids[i].Traffic.AirtimeMs > ssids[j].Traffic.AirtimeMs
+		}
+		return ssids[i].SSID < ssids[j].SSID
+	})
+	return ssids
+}
+
+// ServeSSIDs answers GET /ssids[?ssid=] with the SSID traffic as JSON
+func (t *XnfvWirelessTable) ServeSSIDs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, t.SSIDs(r.URL.Query().Get("ssid")))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nephilimboy/xnfv-SflowCollector/sflow"
+)
+
+func TestXnfvWirelessTableRates(t *testing.T) {
+	dot11 := func(transmitted uint32, retries uint32, fcsErrors uint32) sflow.SFlowRecord {
+		return sflow.SFlow80211Counters{TransmittedFrameCount: transmitted, RetryCount: retries, FCSErrorCount: fcsErrors, AssociatedStationCount: 4}
+	}
+	steps := []struct {
+		record       sflow.SFlowRecord
+		txFrameRate  float64
+		fcsErrorRate float64
+		retryRatio   float64
+	}{
+		{dot11(0xFFFFF000, 100, 0), 0, 0, 0},
+		// the transmitted frames wrap
+		{dot11(0x00001000, 300, 50), 819.2, 5, 200.0 / 8192},
+		// the radio restarted, the interval is skipped
+		{dot11(10, 0, 0), 0, 0, 0},
+		{dot11(1010, 100, 10), 100, 1, 0.1},
+	}
+	table := NewXnfvWirelessTable(DefaultXnfvWirelessTableConfig())
+	for i, step := range steps {
+		table.ObserveDatagram(xnfvTestCounterDatagram(2, step.record), xnfvTestEpoch.Add(time.Duration(i)*10*time.Second))
+		aps := table.APs("192.0.2.1")
+		if len(aps) != 1 || aps[0].XnfvWirelessAPKey != (XnfvWirelessAPKey{"192.0.2.1", 2}) || aps[0].AssociatedStations != 4 {
+			t.Fatalf("step %d: access points %+v", i, aps)
+		}
+		ap := aps[0]
+		if ap.TxFrameRate != step.txFrameRate || ap.FCSErrorRate != step.fcsErrorRate || ap.RetryRatio != step.retryRatio {
+			t.Fatalf("step %d: tx %v fcs %v retry %v, want %v %v %v", i, ap.TxFrameRate, ap.FCSErrorRate, ap.RetryRatio, step.txFrameRate, step.fcsErrorRate, step.retryRatio)
+		}
+	}
+}
+
+func TestXnfvWirelessTableChannelUtilization(t *testing.T) {
+	radio := func(onChannel uint32, busy uint32) sflow.SFlowRecord {
+		return sflow.SFlowRadioCounters{OnChannelTime: onChannel, OnChannelBusyTime: busy}
+	}
+	steps := []struct {
+		record      sflow.SFlowRecord
+		utilization float64
+	}{
+		// since the radio started
+		{radio(1000, 100), 0.1},
+		{radio(2000, 600), 0.5},
+		// the radio restarted, back to the times since it started
+		{radio(400, 100), 0.25},
+		{radio(1400, 900), 0.8},
+	}
+	table := NewXnfvWirelessTable(DefaultXnfvWirelessTableConfig())
+	for i, step := range steps {
+		table.ObserveDatagram(xnfvTestCounterDatagram(2, step.record), xnfvTestEpoch.Add(time.Duration(i)*10*time.Second))
+		if utilization := table.APs("")[0].ChannelUtilization; utilization != step.utilization {
+			t.Fatalf("step %d: channel utilization %v, want %v", i, utilization, step.utilization)
+		}
+	}
+}